@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wyvernzora/personal-finance-mcp/internal/config"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/health"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/metrics"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/prompts"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/resources"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tools"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+)
+
+// app owns the MCP server and swaps its tools, resources, prompts and credentials whenever the
+// configuration is reloaded. Sessions are kept by the MCP server, so they survive reloads.
+type app struct {
+	mcpServer *server.MCPServer
+	metrics   *metrics.Metrics
+	readiness *health.Readiness
+	drain     *health.Drain
+	logger    *slog.Logger
+	// logLevel is the level of logger, which takes effect on reload.
+	logLevel *slog.LevelVar
+
+	// contextFunc is the HTTP context function of the current configuration.
+	contextFunc atomic.Pointer[server.HTTPContextFunc]
+
+	mu  sync.Mutex
+	cfg *config.Config
+	// registered are the resources and prompts of the current configuration, removed on the next reload.
+	resourceURIs []string
+	promptNames  []string
+	// stop stops the background tasks of the current configuration, such as the resource watcher.
+	stop context.CancelFunc
+}
+
+// newApp creates the MCP server and applies the initial configuration.
+func newApp(cfg *config.Config, logger *slog.Logger, logLevel *slog.LevelVar) (*app, error) {
+	m := metrics.New()
+	drain := health.NewDrain()
+	a := &app{
+		mcpServer: createMCPServer(cfg, m, drain),
+		metrics:   m,
+		readiness: health.NewReadiness(cfg.Server.ReadinessTTL),
+		drain:     drain,
+		logger:    logger,
+		logLevel:  logLevel,
+	}
+	if err := a.apply(cfg); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// httpContext injects the clients of the current configuration into the request context.
+func (a *app) httpContext(ctx context.Context, r *http.Request) context.Context {
+	return (*a.contextFunc.Load())(ctx, r)
+}
+
+// reload applies a reloaded configuration, keeping the previous one in effect if it cannot be applied.
+func (a *app) reload(cfg *config.Config) {
+	a.mu.Lock()
+	prev := a.cfg
+	a.mu.Unlock()
+	for field, changed := range map[string]bool{
+		"server":         prev.Server != cfg.Server,
+		"base_currency":  prev.BaseCurrency != cfg.BaseCurrency,
+		"logging.format": prev.Logging.Format != cfg.Logging.Format,
+		"tracing":        prev.Tracing != cfg.Tracing,
+	} {
+		if changed {
+			a.logger.Warn("Configuration change takes effect after a restart", "field", field)
+		}
+	}
+	if err := a.apply(cfg); err != nil {
+		a.logger.Error("Configuration not applied", "error", err)
+	}
+}
+
+// apply registers the tools, resources and prompts enabled by cfg and starts watching its resources and secrets.
+func (a *app) apply(cfg *config.Config) error {
+	ctx, cancel := context.WithCancel(logging.WithLogger(context.Background(), a.logger))
+	src, err := newSources(ctx, cfg, a.metrics)
+	if err != nil {
+		cancel()
+		return err
+	}
+	serverTools, err := enabledTools(cfg.Tools, src)
+	if err != nil {
+		cancel()
+		return err
+	}
+	serverResources := src.resources()
+	serverPrompts := src.prompts(prompts.NewTemplates(cfg.Prompts.Dir, cfg.Location))
+	contextFunc := composeHTTPContextFuncs(
+		append(src.contextFuncs, tracing.HTTPContextFunc(), logging.HTTPContextFunc(a.logger))...,
+	)
+	level, err := logging.ParseLevel(cfg.Logging.Level)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.logLevel.Set(level)
+	a.contextFunc.Store(&contextFunc)
+	a.readiness.SetChecks(func(ctx context.Context) context.Context { return contextFunc(ctx, nil) }, src.registry.Checks())
+	a.mcpServer.SetTools(serverTools...)
+
+	for _, uri := range a.resourceURIs {
+		a.mcpServer.RemoveResource(uri)
+	}
+	a.resourceURIs = a.resourceURIs[:0]
+	for _, res := range serverResources {
+		a.resourceURIs = append(a.resourceURIs, res.Resource.URI)
+	}
+	a.mcpServer.AddResources(serverResources...)
+
+	a.mcpServer.DeletePrompts(a.promptNames...)
+	a.promptNames = a.promptNames[:0]
+	for _, prompt := range serverPrompts {
+		a.promptNames = append(a.promptNames, prompt.Prompt.Name)
+	}
+	a.mcpServer.AddPrompts(serverPrompts...)
+
+	if a.stop != nil {
+		a.stop()
+	}
+	a.stop = cancel
+	watcher := resources.NewWatcher(a.mcpServer, cfg.Resources.RefreshInterval,
+		func(ctx context.Context) context.Context { return contextFunc(ctx, nil) },
+		serverResources...,
+	)
+	go watcher.Run(ctx)
+
+	a.cfg = cfg
+	return nil
+}
+
+// close stops the background tasks of the current configuration.
+func (a *app) close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stop != nil {
+		a.stop()
+	}
+}
+
+// catalogue returns every tool the server offers, each paired with whether a configured data source has the
+// capability it requires.
+func (s *sources) catalogue() []catalogueEntry {
+	entries := []catalogueEntry{
+		{tool: tools.GetCategorizedTransactionsTool(s.transactions, s.budget), requires: ds.CapabilityTransactions},
+		{tool: tools.GetCategorizedSummariesTool(s.transactions), requires: ds.CapabilityTransactions},
+		{tool: tools.FindDuplicateTransactionsTool(s.allTransactions, s.duplicates), requires: ds.CapabilityTransactions},
+		{tool: tools.GetNetWorthSummary(s.portfolio, s.selectPortfolio), requires: ds.CapabilityPortfolio},
+		{tool: tools.ListDataSourcesTool(s.registry)},
+	}
+	for i, entry := range entries {
+		entries[i].available = entry.requires == "" || s.registry.Has(entry.requires)
+	}
+	return entries
+}
+
+// catalogueEntry is a tool offered by the server.
+type catalogueEntry struct {
+	tool server.ServerTool
+	// requires is the capability a data source must have for the tool to be available, if any.
+	requires  ds.Capability
+	available bool
+}
+
+// enabledTools returns the tools named in enabled, or every available tool if enabled is empty.
+// Naming an unknown tool, or a tool whose data source is not configured, is an error.
+func enabledTools(enabled []string, src *sources) ([]server.ServerTool, error) {
+	var (
+		result []server.ServerTool
+		known  []string
+	)
+	for _, entry := range src.catalogue() {
+		name := entry.tool.Tool.Name
+		known = append(known, name)
+		if len(enabled) > 0 && !slices.Contains(enabled, name) {
+			continue
+		}
+		if !entry.available {
+			if len(enabled) > 0 {
+				return nil, fmt.Errorf("tools: %s requires a data source that is not configured", name)
+			}
+			continue
+		}
+		result = append(result, entry.tool)
+	}
+	for _, name := range enabled {
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("tools: unknown tool %q, expected one of %s", name, strings.Join(known, ", "))
+		}
+	}
+	return result, nil
+}
+
+// resources returns the resources whose data sources are configured.
+func (s *sources) resources() []server.ServerResource {
+	var result []server.ServerResource
+	if s.categories != nil {
+		result = append(result, resources.CategoriesResource(s.categories))
+	}
+	if s.tags != nil {
+		result = append(result, resources.TagsResource(s.tags))
+	}
+	if s.portfolio != nil {
+		result = append(result, resources.PortfolioResource(s.portfolio))
+	}
+	return result
+}
+
+// prompts returns the prompts whose tools are backed by configured data sources.
+func (s *sources) prompts(templates *prompts.Templates) []server.ServerPrompt {
+	var result []server.ServerPrompt
+	if s.transactions != nil {
+		result = append(result,
+			prompts.MonthlySpendingReviewPrompt(templates),
+			prompts.SubscriptionAuditPrompt(templates),
+		)
+	}
+	if s.portfolio != nil {
+		result = append(result, prompts.NetWorthCheckInPrompt(templates))
+	}
+	return result
+}
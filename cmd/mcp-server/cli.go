@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wyvernzora/personal-finance-mcp/internal/config"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/metrics"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/render"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// toolAliases are the short names accepted by the query command in addition to the tool names.
+var toolAliases = map[string]string{
+	"transactions": "get_categorized_transactions",
+	"summaries":    "get_categorized_summaries",
+	"net-worth":    "get_net_worth_summary",
+	"duplicates":   "find_duplicate_transactions",
+	"sources":      "list_data_sources",
+}
+
+// runQuery calls a single tool with arguments taken from the command line and prints its result.
+func runQuery(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	configPath := configFlag(fs)
+	format := fs.String("format", string(formatJSON), "output format: json, table or csv")
+	rangeName := fs.String("range", "", "date range: today, this_month, last_month, this_year, last_year or last_<N>_days")
+	start := fs.String("start", "", "inclusive start date formatted like YYYY-MM-DD, instead of -range")
+	end := fs.String("end", "", "inclusive end date formatted like YYYY-MM-DD, instead of -range")
+	extra := make(toolArguments)
+	fs.Var(extra, "arg", "additional tool argument formatted like name=value, may be repeated")
+
+	// The tool name may come before or after the flags.
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usageErrorf("missing tool name")
+	}
+	name := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %v", fs.Args())
+	}
+	outFormat, err := parseOutputFormat(*format)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	if alias, ok := toolAliases[name]; ok {
+		name = alias
+	}
+
+	ctx, cfg, src, err := prepare(ctx, *configPath)
+	if err != nil {
+		return err
+	}
+	var entry *catalogueEntry
+	for _, e := range src.catalogue() {
+		if e.tool.Tool.Name == name {
+			entry = &e
+			break
+		}
+	}
+	switch {
+	case entry == nil:
+		return usageErrorf("unknown tool %q", name)
+	case !entry.available:
+		return fmt.Errorf("%s requires a data source that is not configured", name)
+	}
+
+	arguments := map[string]any(extra)
+	if _, ok := entry.tool.Tool.InputSchema.Properties["start_date"]; ok {
+		interval, err := queryRange(*rangeName, *start, *end, time.Now().In(cfg.Location))
+		if err != nil {
+			return usageError(err.Error())
+		}
+		arguments["start_date"] = interval.StartDate.String()
+		arguments["end_date"] = interval.EndDate.String()
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = arguments
+	result, err := entry.tool.Handler(ctx, req)
+	if err != nil {
+		return err
+	}
+	if result.IsError {
+		return errors.New(resultText(result))
+	}
+	if result.StructuredContent == nil {
+		_, err := fmt.Fprintln(os.Stdout, resultText(result))
+		return err
+	}
+	return printResult(os.Stdout, outFormat, result)
+}
+
+// runSync checks the credentials of every configured data source and fetches its data, reporting what was
+// retrieved. It fails if any data source cannot be reached.
+func runSync(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	configPath := configFlag(fs)
+	format := fs.String("format", string(formatTable), "output format: json, table or csv")
+	rangeName := fs.String("range", "this_month", "date range of the transactions to fetch, as in the query command")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %v", fs.Args())
+	}
+	outFormat, err := parseOutputFormat(*format)
+	if err != nil {
+		return usageError(err.Error())
+	}
+
+	ctx, cfg, src, err := prepare(ctx, *configPath)
+	if err != nil {
+		return err
+	}
+	interval, err := queryRange(*rangeName, "", "", time.Now().In(cfg.Location))
+	if err != nil {
+		return usageError(err.Error())
+	}
+
+	out := &render.Table{Header: []string{"Step", "Status", "Detail", "Duration"}}
+	failed := 0
+	step := func(name string, fn func() (string, error)) {
+		started := time.Now()
+		detail, err := fn()
+		status := "ok"
+		if err != nil {
+			status, detail = "failed", err.Error()
+			failed++
+		}
+		out.Rows = append(out.Rows, []string{name, status, detail, time.Since(started).Round(time.Millisecond).String()})
+	}
+	checks := src.registry.Checks()
+	for _, name := range slices.Sorted(maps.Keys(checks)) {
+		step("check "+name, func() (string, error) { return "credentials accepted", checks[name](ctx) })
+	}
+	if src.transactions != nil {
+		step("transactions", func() (string, error) {
+			cats, err := src.transactions(ctx, interval)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d transactions between %s and %s", cats.TransactionCount(), interval.StartDate, interval.EndDate), nil
+		})
+	}
+	if src.portfolio != nil {
+		step("portfolio", func() (string, error) {
+			portfolio, err := src.portfolio(ctx)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d assets and %d debts", len(portfolio.Assets), len(portfolio.Debts)), nil
+		})
+	}
+
+	if err := writeTable(os.Stdout, outFormat, out); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d steps failed", failed, len(out.Rows))
+	}
+	return nil
+}
+
+// runValidateConfig loads the configuration, resolves its secret references and checks the enabled tools.
+func runValidateConfig(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	configPath := configFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %v", fs.Args())
+	}
+
+	_, cfg, src, err := prepare(ctx, *configPath)
+	if err != nil {
+		return err
+	}
+	serverTools, err := enabledTools(cfg.Tools, src)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, tool := range serverTools {
+		names = append(names, tool.Tool.Name)
+	}
+	fmt.Println("Configuration is valid")
+	fmt.Println("Data sources:", strings.Join(src.registry.Names(), ", "))
+	fmt.Println("Tools:", strings.Join(names, ", "))
+	return nil
+}
+
+// runListTools prints every tool the server offers, along with whether the configuration enables it.
+func runListTools(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	configPath := configFlag(fs)
+	format := fs.String("format", string(formatTable), "output format: json, table or csv")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %v", fs.Args())
+	}
+	outFormat, err := parseOutputFormat(*format)
+	if err != nil {
+		return usageError(err.Error())
+	}
+
+	_, cfg, src, err := prepare(ctx, *configPath)
+	if err != nil {
+		return err
+	}
+	out := &render.Table{Header: []string{"Name", "Available", "Enabled", "Description"}}
+	for _, entry := range src.catalogue() {
+		name := entry.tool.Tool.Name
+		enabled := entry.available && (len(cfg.Tools) == 0 || slices.Contains(cfg.Tools, name))
+		out.Rows = append(out.Rows, []string{
+			name, strconv.FormatBool(entry.available), strconv.FormatBool(enabled), entry.tool.Tool.Description,
+		})
+	}
+	return writeTable(os.Stdout, outFormat, out)
+}
+
+// prepare loads the configuration and wires up its data sources for a one-shot command. The returned context
+// carries the logger and the data source clients, just like the context of a tool call made over MCP.
+func prepare(ctx context.Context, configPath string) (context.Context, *config.Config, *sources, error) {
+	cfg, logger, _, err := loadConfig(configPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ctx = logging.WithLogger(ctx, logger)
+	src, err := newSources(ctx, cfg, metrics.New())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return composeHTTPContextFuncs(src.contextFuncs...)(ctx, nil), cfg, src, nil
+}
+
+// queryRange determines the date range of a query from either a named range or explicit dates.
+// Without either, it defaults to the current month.
+func queryRange(name, start, end string, now time.Time) (ds.DateRange, error) {
+	if start != "" || end != "" {
+		if name != "" {
+			return ds.DateRange{}, errors.New("-range cannot be combined with -start and -end")
+		}
+		var (
+			r   ds.DateRange
+			err error
+		)
+		if r.StartDate, err = types.ParseDate(start); err != nil {
+			return ds.DateRange{}, fmt.Errorf("invalid -start: %w", err)
+		}
+		if r.EndDate, err = types.ParseDate(end); err != nil {
+			return ds.DateRange{}, fmt.Errorf("invalid -end: %w", err)
+		}
+		return r, nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	thisMonth := today.AddDate(0, 0, 1-today.Day())
+	thisYear := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	dateRange := func(start, end time.Time) ds.DateRange {
+		return ds.DateRange{StartDate: types.Date(start), EndDate: types.Date(end)}
+	}
+	switch name {
+	case "today":
+		return dateRange(today, today), nil
+	case "", "this_month":
+		return dateRange(thisMonth, today), nil
+	case "last_month":
+		return dateRange(thisMonth.AddDate(0, -1, 0), thisMonth.AddDate(0, 0, -1)), nil
+	case "this_year":
+		return dateRange(thisYear, today), nil
+	case "last_year":
+		return dateRange(thisYear.AddDate(-1, 0, 0), thisYear.AddDate(0, 0, -1)), nil
+	}
+	if days, ok := strings.CutPrefix(name, "last_"); ok {
+		if days, ok := strings.CutSuffix(days, "_days"); ok {
+			if n, err := strconv.Atoi(days); err == nil && n > 0 {
+				return dateRange(today.AddDate(0, 0, 1-n), today), nil
+			}
+		}
+	}
+	return ds.DateRange{}, fmt.Errorf("unknown date range %q", name)
+}
+
+// toolArguments collects repeated name=value flags. Values that parse as JSON, such as numbers and booleans,
+// are passed to the tool as such; anything else is passed as a string.
+type toolArguments map[string]any
+
+func (a toolArguments) String() string {
+	return fmt.Sprint(map[string]any(a))
+}
+
+func (a toolArguments) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=value, got %q", s)
+	}
+	var parsed any
+	if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+		parsed = value
+	}
+	a[name] = parsed
+	return nil
+}
+
+// resultText joins the text content of a tool result.
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
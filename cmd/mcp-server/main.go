@@ -2,66 +2,173 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	_ "time/tzdata"
 
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/kubera"
-	lm "github.com/wyvernzora/personal-finance-mcp/pkg/datasource/lunch_money"
-	"github.com/wyvernzora/personal-finance-mcp/pkg/tools"
+	"github.com/wyvernzora/personal-finance-mcp/internal/config"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/health"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/metrics"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
 )
 
+const (
+	serverName    = "personal-finance-mcp"
+	serverVersion = "1.0.0"
+)
+
+// command is a subcommand of the server binary.
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, fs *flag.FlagSet, args []string) error
+}
+
+// commands are the available subcommands. The first one runs when no subcommand is given.
+var commands = []command{
+	{"serve", "", "Run the MCP server", runServe},
+	{"query", "<tool>", "Call a tool once and print its result", runQuery},
+	{"sync", "", "Fetch data from every configured data source and report what was retrieved", runSync},
+	{"validate-config", "", "Check the configuration, including its secret references, and exit", runValidateConfig},
+	{"list-tools", "", "List the tools and whether their data sources are configured", runListTools},
+}
+
 func main() {
-	bindAddr := os.Getenv("BIND_ADDRESS")
-	if bindAddr == "" {
-		bindAddr = "0.0.0.0"
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Without a subcommand, or with only flags, the binary runs the server as it always has.
+	name, args := commands[0].name, os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
 	}
+	if name == "help" {
+		usage()
+		return
+	}
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		fs.Usage = func() {
+			fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] %s\n\n%s.\n\nFlags:\n", os.Args[0], cmd.name, cmd.args, cmd.summary)
+			fs.PrintDefaults()
+		}
+		err := cmd.run(ctx, fs, args)
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			os.Exit(0)
+		case errors.As(err, new(usageError)):
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			fs.Usage()
+			os.Exit(2)
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", os.Args[0], cmd.name, err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3000"
+// usage prints the available subcommands.
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", cmd.name, cmd.summary)
 	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for the flags of a command.\n", os.Args[0])
+}
 
-	httpServer := server.NewStreamableHTTPServer(
-		createMCPServer(),
-		withHTTPContextFuncs(
-			lm.InjectCredentialsFromEnvironment(),
-			kubera.InjectCredentialsFromEnvironment(),
-		),
-	)
-	log.Printf("HTTP server listening on %s:%s/mcp", bindAddr, port)
-	if err := httpServer.Start(bindAddr + ":" + port); err != nil {
-		log.Fatalf("Server error: %v", err)
+// usageError reports a malformed command line.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+// usageErrorf formats a usageError.
+func usageErrorf(format string, args ...any) error {
+	return usageError(fmt.Sprintf(format, args...))
+}
+
+// configFlag registers the flag that selects the configuration file.
+func configFlag(fs *flag.FlagSet) *string {
+	return fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML configuration file")
+}
+
+// loadConfig loads the configuration at path and installs the logger it configures as the default.
+func loadConfig(path string) (*config.Config, *slog.Logger, *slog.LevelVar, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, nil, nil, err
 	}
+	logger, logLevel := newLogger(cfg)
+	slog.SetDefault(logger)
+	return cfg, logger, logLevel, nil
 }
 
-func createMCPServer() *server.MCPServer {
-	mcpServer := server.NewMCPServer(
-		"personal-finance-mcp",
-		"1.0.0",
+// newLogger creates the logger configured by cfg, along with the variable that controls its level.
+func newLogger(cfg *config.Config) (*slog.Logger, *slog.LevelVar) {
+	level := new(slog.LevelVar)
+	if l, err := logging.ParseLevel(cfg.Logging.Level); err == nil {
+		level.Set(l)
+	}
+	format, _ := logging.ParseFormat(cfg.Logging.Format)
+	return logging.New(os.Stderr, format, level), level
+}
+
+// setupTracing installs the trace exporter configured by cfg.
+func setupTracing(cfg *config.Config) (func(context.Context) error, error) {
+	exporter, err := tracing.ParseExporter(cfg.Tracing.Exporter)
+	if err != nil {
+		return nil, err
+	}
+	return tracing.Setup(context.Background(), tracing.Options{
+		Exporter:       exporter,
+		Endpoint:       cfg.Tracing.Endpoint,
+		SampleRatio:    cfg.Tracing.SampleRatio,
+		ServiceName:    serverName,
+		ServiceVersion: serverVersion,
+		Stdout:         os.Stdout,
+	})
+}
+
+func createMCPServer(cfg *config.Config, m *metrics.Metrics, drain *health.Drain) *server.MCPServer {
+	return server.NewMCPServer(
+		serverName,
+		serverVersion,
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, true),
+		server.WithPromptCapabilities(true),
 		server.WithLogging(),
+		server.WithToolHandlerMiddleware(drain.ToolMiddleware()),
+		server.WithToolHandlerMiddleware(tracing.ToolMiddleware()),
+		server.WithToolHandlerMiddleware(logging.ToolMiddleware()),
+		server.WithToolHandlerMiddleware(m.ToolMiddleware()),
 		server.WithInstructions(
 			"This server provides tools to retrieve information about a user's personal finances, such as "+
-				"spending transactions, asset holdings, net worth etc",
+				"spending transactions, asset holdings, net worth etc. The user's base currency is "+cfg.BaseCurrency+".",
 		),
 	)
-
-	mcpServer.AddTools(
-		tools.GetCategorizedTransactionsTool(lm.GetCategorizedTransactions),
-		tools.GetCategorizedSummariesTool(lm.GetCategorizedTransactions),
-		tools.GetNetWorthSummary(kubera.GetPortfolio),
-	)
-
-	return mcpServer
 }
 
-func withHTTPContextFuncs(fns ...server.HTTPContextFunc) server.StreamableHTTPOption {
-	return server.WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context {
+func composeHTTPContextFuncs(fns ...server.HTTPContextFunc) server.HTTPContextFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
 		for _, fn := range fns {
 			ctx = fn(ctx, r)
 		}
 		return ctx
-	})
+	}
 }
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/render"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tools"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// outputFormat is the format in which the CLI commands print their results.
+type outputFormat string
+
+const (
+	formatJSON  outputFormat = "json"
+	formatTable outputFormat = "table"
+	formatCSV   outputFormat = "csv"
+)
+
+// parseOutputFormat parses the name of an output format.
+func parseOutputFormat(s string) (outputFormat, error) {
+	switch f := outputFormat(strings.ToLower(s)); f {
+	case formatJSON, formatTable, formatCSV:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q, expected json, table or csv", s)
+	}
+}
+
+// printResult writes the structured content of a tool result in the given format. JSON output is the structured
+// content exactly as an MCP client receives it; tables and CSV are only available for the result types known here.
+// Tables are followed by the text that the tool returns alongside the structured content.
+func printResult(w io.Writer, format outputFormat, result *mcp.CallToolResult) error {
+	if format == formatJSON {
+		return writeJSON(w, result.StructuredContent)
+	}
+	var t *render.Table
+	switch v := result.StructuredContent.(type) {
+	case *types.Categories:
+		t = render.CategoriesTable(v)
+	case *tools.CategorizedTransactionsPage:
+		t = render.CategoriesTable(v.Categories)
+	case *types.Portfolio:
+		t = render.PortfolioTable(v)
+	case *tools.DuplicateClusters:
+		t = tools.DuplicateClustersTable(v)
+	case *tools.DataSources:
+		t = tools.DataSourcesTable(v)
+	default:
+		return fmt.Errorf("%s output is not supported for this tool, use json", format)
+	}
+	if err := writeTable(w, format, t); err != nil {
+		return err
+	}
+	if format == formatTable {
+		_, err := fmt.Fprintf(w, "\n%s\n", resultText(result))
+		return err
+	}
+	return nil
+}
+
+// writeTable prints t in the given format. In JSON, each row becomes an object keyed by the lowercase header.
+func writeTable(w io.Writer, format outputFormat, t *render.Table) error {
+	switch format {
+	case formatCSV:
+		return t.Write(w, render.CSV)
+	case formatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(t.Header, "\t")))
+		for _, row := range t.Rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	default:
+		objects := make([]map[string]string, 0, len(t.Rows))
+		for _, row := range t.Rows {
+			obj := make(map[string]string, len(row))
+			for i, cell := range row {
+				obj[strings.ToLower(t.Header[i])] = cell
+			}
+			objects = append(objects, obj)
+		}
+		return writeJSON(w, objects)
+	}
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
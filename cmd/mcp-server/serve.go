@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wyvernzora/personal-finance-mcp/internal/config"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/health"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+)
+
+// configPollInterval is how often the configuration file is checked for changes.
+const configPollInterval = 10 * time.Second
+
+// runServe runs the MCP server until ctx is cancelled, then shuts it down gracefully.
+func runServe(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	configPath := configFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %v", fs.Args())
+	}
+
+	cfg, logger, logLevel, err := loadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	shutdownTracing, err := setupTracing(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("Failed to flush traces", "error", err)
+		}
+	}()
+
+	a, err := newApp(cfg, logger, logLevel)
+	if err != nil {
+		return fmt.Errorf("failed to apply configuration: %w", err)
+	}
+	go config.Watch(logging.WithLogger(ctx, logger), *configPath, configPollInterval, a.reload)
+
+	addr := net.JoinHostPort(cfg.Server.BindAddress, strconv.Itoa(cfg.Server.Port))
+	mux := http.NewServeMux()
+	httpServer := server.NewStreamableHTTPServer(
+		a.mcpServer,
+		server.WithHTTPContextFunc(a.httpContext),
+		server.WithStreamableHTTPServer(&http.Server{Addr: addr, Handler: mux}),
+	)
+	mux.Handle("/mcp", httpServer)
+	mux.Handle("/metrics", a.metrics.Handler())
+	mux.Handle("GET /healthz", health.LivenessHandler())
+	mux.Handle("GET /readyz", a.readiness.Handler())
+	mux.Handle("GET /version", health.VersionHandler(health.ReadVersion(serverName, serverVersion)))
+
+	errs := make(chan error, 1)
+	go func() {
+		logger.Info("HTTP server listening", "address", addr,
+			"paths", []string{"/mcp", "/metrics", "/healthz", "/readyz", "/version"})
+		errs <- httpServer.Start(addr)
+	}()
+
+	select {
+	case err := <-errs:
+		a.close()
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server error: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		// A second signal terminates the process immediately instead of waiting for the graceful shutdown.
+		signal.Reset(syscall.SIGINT, syscall.SIGTERM)
+		shutdown(a, httpServer, cfg.Server.ShutdownTimeout, logger)
+		return nil
+	}
+}
+
+// shutdown stops the server gracefully: it reports not ready, waits for in-flight tool calls to complete while
+// rejecting new ones, and then closes the HTTP server. The whole sequence is bounded by timeout.
+func shutdown(a *app, httpServer *server.StreamableHTTPServer, timeout time.Duration, logger *slog.Logger) {
+	logger.Info("Shutting down, draining in-flight tool calls", "timeout", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	a.readiness.SetDraining()
+	if err := a.drain.Wait(ctx); err != nil {
+		logger.Warn("Gave up waiting for in-flight tool calls", "error", err)
+	}
+	// Streaming connections stay open until clients disconnect, so do not wait for them past the timeout.
+	if err := httpServer.Shutdown(ctx); err != nil {
+		logger.Warn("HTTP server did not shut down cleanly", "error", err)
+	}
+	a.close()
+	logger.Info("Shutdown complete")
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/server"
+	ffapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/firefly"
+	kbapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/kubera"
+	lmapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/lunch_money"
+	sfapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/simplefin"
+	ynabapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/ynab"
+	"github.com/wyvernzora/personal-finance-mcp/internal/config"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/actual"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/csv"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/firefly"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/gnucash"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/kubera"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/ledger"
+	lm "github.com/wyvernzora/personal-finance-mcp/pkg/datasource/lunch_money"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/manual"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/ofx"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/simplefin"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/ynab"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/duplicates"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/metrics"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/secrets"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tools"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+)
+
+// sources are the data sources enabled by a configuration, and the functions that merge their results. Functions
+// of kinds of data that no configured data source provides are nil.
+type sources struct {
+	registry     *ds.Registry
+	transactions ds.GetCategorizedTransactionsFunc
+	// allTransactions are the transactions before suspected duplicates are ignored, which duplicates reviews.
+	allTransactions ds.GetCategorizedTransactionsFunc
+	duplicates      *duplicates.Detector
+	categories      ds.ListCategoriesFunc
+	tags            ds.ListTagsFunc
+	portfolio       ds.GetPortfolioFunc
+	// selectPortfolio selects among the portfolios of data sources that keep several, such as Kubera, and merges
+	// them with the portfolios of the other data sources.
+	selectPortfolio ds.SelectPortfolioFunc
+	// portfolioSelector is the data source that keeps several portfolios, if any.
+	portfolioSelector *ds.Named[ds.SelectPortfolioFunc]
+	// budget limits the size of tool responses.
+	budget tools.Budget
+	// contextFuncs inject the clients of the configured data sources into request contexts.
+	contextFuncs []server.HTTPContextFunc
+}
+
+// newSources creates the data sources configured in cfg through sourceFactories, registers them, and merges their
+// results, applying payee rules, caching and instrumentation. Secrets referenced by the credentials are resolved
+// immediately and re-read in the background until ctx is cancelled.
+func newSources(ctx context.Context, cfg *config.Config, m *metrics.Metrics) (*sources, error) {
+	src := &sources{
+		registry: ds.NewRegistry(),
+		budget:   tools.Budget{MaxTransactions: cfg.Responses.MaxTransactions, MaxTokens: cfg.Responses.MaxTokens},
+		duplicates: duplicates.New(duplicates.Options{
+			MaxDays: cfg.Duplicates.MaxDays, MinSimilarity: cfg.Duplicates.MinSimilarity,
+		}),
+	}
+	b := &sourceBuilder{ctx: ctx, cfg: cfg, metrics: m, sources: src}
+	for _, name := range cfg.DataSources.Names() {
+		factory, ok := sourceFactories[name]
+		if !ok {
+			return nil, fmt.Errorf("datasources.%s: unsupported data source", name)
+		}
+		funcs, err := factory(b)
+		if err != nil {
+			return nil, err
+		}
+		// Every data source is cached on its own and then merged with the others.
+		funcs.SourceName = name
+		if funcs.TransactionsFunc != nil {
+			funcs.TransactionsFunc = ds.CacheTransactions(
+				cfg.CompiledRules.Apply(funcs.TransactionsFunc), cfg.Cache.TTL, m.CacheObserver("transactions"),
+			)
+		}
+		if funcs.PortfolioFunc != nil {
+			funcs.PortfolioFunc = ds.CachePortfolio(funcs.PortfolioFunc, cfg.Cache.TTL, m.CacheObserver("portfolio"))
+		}
+		if err := src.registry.Register(funcs); err != nil {
+			return nil, err
+		}
+	}
+
+	policy, err := ds.ParseMergePolicy(cfg.DataSources.OnFailure)
+	if err != nil {
+		return nil, err
+	}
+	if transactions := src.registry.Transactions(); len(transactions) > 0 {
+		src.allTransactions = ds.MergeTransactions(policy, transactions...)
+		src.transactions = src.allTransactions
+		if cfg.Duplicates.Ignore {
+			src.transactions = src.duplicates.Apply(src.transactions)
+		}
+		src.transactions = m.CountTransactions(src.transactions)
+	}
+	if portfolios := src.registry.Portfolios(); len(portfolios) > 0 {
+		src.portfolio = ds.MergePortfolios(policy, portfolios...)
+		if src.portfolioSelector != nil {
+			src.selectPortfolio = ds.MergeSelectedPortfolios(policy, *src.portfolioSelector, portfolios...)
+		}
+	}
+	return src, nil
+}
+
+// sourceBuilder is what data source factories build data sources with.
+type sourceBuilder struct {
+	ctx     context.Context
+	cfg     *config.Config
+	metrics *metrics.Metrics
+	sources *sources
+}
+
+// secret resolves a secret reference of the configuration field and re-reads it in the background.
+func (b *sourceBuilder) secret(field, ref string) (*secrets.Secret, error) {
+	s, err := secrets.New(b.ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+	go s.Watch(b.ctx, b.cfg.Secrets.RefreshInterval)
+	return s, nil
+}
+
+// transport returns the HTTP transport of the API client called name, instrumented with metrics and tracing.
+func (b *sourceBuilder) transport(name string) http.RoundTripper {
+	return tracing.Transport(name, b.metrics.Transport(name, nil))
+}
+
+// injectClient adds an HTTP context function that injects the API client of a data source into request contexts.
+func (b *sourceBuilder) injectClient(fn server.HTTPContextFunc) {
+	b.sources.contextFuncs = append(b.sources.contextFuncs, fn)
+}
+
+// sourceFactories create the data sources by the name of their configuration, see config.DataSourcesConfig.Names.
+// The returned functions are cached and merged by newSources.
+var sourceFactories = map[string]func(b *sourceBuilder) (*ds.Funcs, error){
+	"lunch_money": func(b *sourceBuilder) (*ds.Funcs, error) {
+		token, err := b.secret("datasources.lunch_money.token", b.cfg.DataSources.LunchMoney.Token)
+		if err != nil {
+			return nil, err
+		}
+		b.sources.categories = lm.ListCategories
+		b.sources.tags = lm.ListTags
+		b.injectClient(lm.InjectCredentials(token, lmapi.WithTransport(b.transport("lunch_money"))))
+		return &ds.Funcs{TransactionsFunc: lm.GetCategorizedTransactions, CheckFunc: lm.CheckCredentials}, nil
+	},
+	"kubera": func(b *sourceBuilder) (*ds.Funcs, error) {
+		c := b.cfg.DataSources.Kubera
+		apiKey, err := b.secret("datasources.kubera.api_key", c.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		apiSecret, err := b.secret("datasources.kubera.api_secret", c.APISecret)
+		if err != nil {
+			return nil, err
+		}
+		b.injectClient(kubera.InjectCredentials(apiKey, apiSecret, c.PortfolioID, kbapi.WithTransport(b.transport("kubera"))))
+		b.sources.portfolioSelector = &ds.Named[ds.SelectPortfolioFunc]{Name: "kubera", Fn: ds.CacheSelectedPortfolio(
+			kubera.SelectPortfolio, b.cfg.Cache.TTL, b.metrics.CacheObserver("portfolio"),
+		)}
+		return &ds.Funcs{PortfolioFunc: kubera.GetPortfolio, CheckFunc: kubera.CheckCredentials}, nil
+	},
+	"ofx": func(b *sourceBuilder) (*ds.Funcs, error) {
+		c := b.cfg.DataSources.OFX
+		statements := ofx.NewSource(c.Dir, c.CompiledCategories)
+		return &ds.Funcs{TransactionsFunc: statements.GetCategorizedTransactions, CheckFunc: statements.Check}, nil
+	},
+	"csv": func(b *sourceBuilder) (*ds.Funcs, error) {
+		c := b.cfg.DataSources.CSV
+		exports := csv.NewSource(c.Dir, c.Profiles, c.CompiledCategories)
+		return &ds.Funcs{TransactionsFunc: exports.GetCategorizedTransactions, CheckFunc: exports.Check}, nil
+	},
+	"ynab": func(b *sourceBuilder) (*ds.Funcs, error) {
+		c := b.cfg.DataSources.YNAB
+		token, err := b.secret("datasources.ynab.token", c.Token)
+		if err != nil {
+			return nil, err
+		}
+		budget := ynab.NewSource(c.BudgetID)
+		b.injectClient(ynab.InjectCredentials(token, ynabapi.WithTransport(b.transport("ynab"))))
+		return &ds.Funcs{
+			TransactionsFunc: budget.GetCategorizedTransactions, CheckFunc: budget.Check,
+			ExtraCapabilities: []ds.Capability{ds.CapabilityBudgets, ds.CapabilityAccounts},
+		}, nil
+	},
+	"ledger": func(b *sourceBuilder) (*ds.Funcs, error) {
+		journal := ledger.NewSource(b.cfg.DataSources.Ledger.File, b.cfg.BaseCurrency)
+		return &ds.Funcs{
+			TransactionsFunc: journal.GetCategorizedTransactions, PortfolioFunc: journal.GetPortfolio, CheckFunc: journal.Check,
+		}, nil
+	},
+	"firefly": func(b *sourceBuilder) (*ds.Funcs, error) {
+		c := b.cfg.DataSources.Firefly
+		token, err := b.secret("datasources.firefly.token", c.Token)
+		if err != nil {
+			return nil, err
+		}
+		books := firefly.NewSource(b.cfg.BaseCurrency)
+		b.injectClient(firefly.InjectCredentials(c.URL, token, ffapi.WithTransport(b.transport("firefly"))))
+		return &ds.Funcs{
+			TransactionsFunc: books.GetCategorizedTransactions, PortfolioFunc: books.GetPortfolio, CheckFunc: firefly.CheckCredentials,
+			ExtraCapabilities: []ds.Capability{ds.CapabilityAccounts},
+		}, nil
+	},
+	"simplefin": func(b *sourceBuilder) (*ds.Funcs, error) {
+		c := b.cfg.DataSources.SimpleFIN
+		accessURL, err := b.secret("datasources.simplefin.access_url", c.AccessURL)
+		if err != nil {
+			return nil, err
+		}
+		bank := simplefin.NewSource(b.cfg.BaseCurrency, c.CompiledCategories)
+		b.injectClient(simplefin.InjectCredentials(accessURL, sfapi.WithTransport(b.transport("simplefin"))))
+		return &ds.Funcs{
+			TransactionsFunc: bank.GetCategorizedTransactions, PortfolioFunc: bank.GetPortfolio, CheckFunc: simplefin.CheckCredentials,
+			ExtraCapabilities: []ds.Capability{ds.CapabilityAccounts},
+		}, nil
+	},
+	"actual": func(b *sourceBuilder) (*ds.Funcs, error) {
+		budget := actual.NewSource(b.cfg.DataSources.Actual.File)
+		return &ds.Funcs{
+			TransactionsFunc: budget.GetCategorizedTransactions, PortfolioFunc: budget.GetPortfolio, CheckFunc: budget.Check,
+			ExtraCapabilities: []ds.Capability{ds.CapabilityAccounts},
+		}, nil
+	},
+	"gnucash": func(b *sourceBuilder) (*ds.Funcs, error) {
+		book := gnucash.NewSource(b.cfg.DataSources.GnuCash.File, b.cfg.BaseCurrency)
+		return &ds.Funcs{
+			TransactionsFunc: book.GetCategorizedTransactions, PortfolioFunc: book.GetPortfolio, CheckFunc: book.Check,
+		}, nil
+	},
+	"manual": func(b *sourceBuilder) (*ds.Funcs, error) {
+		c := b.cfg.DataSources.Manual
+		holdings := manual.NewSource(c.File, b.cfg.BaseCurrency, c.StaleAfter)
+		return &ds.Funcs{PortfolioFunc: holdings.GetPortfolio, CheckFunc: holdings.Check}, nil
+	},
+}
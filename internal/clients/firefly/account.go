@@ -0,0 +1,44 @@
+package firefly
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// Types of accounts, as accepted by ListAccounts.
+const (
+	AssetAccount     = "asset"
+	LiabilityAccount = "liabilities"
+)
+
+// Account is an account. Asset and liability accounts hold money, while expense and revenue accounts stand for
+// the payees and payers of withdrawals and deposits.
+type Account struct {
+	Id   string `json:"-"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// AccountRole tells what kind of asset account this is, such as "defaultAsset", "savingAsset" or "ccAsset".
+	AccountRole     string      `json:"account_role"`
+	Active          bool        `json:"active"`
+	IncludeNetWorth bool        `json:"include_net_worth"`
+	CurrencyCode    string      `json:"currency_code"`
+	CurrentBalance  types.Money `json:"current_balance"`
+	Notes           string      `json:"notes"`
+	// LiabilityType is the kind of liability, such as "loan", "debt" or "mortgage".
+	LiabilityType string `json:"liability_type"`
+	// LiabilityDirection is "debit" for money owed by the user and "credit" for money owed to the user.
+	LiabilityDirection string `json:"liability_direction"`
+}
+
+// ListAccounts fetches the accounts of the given type, such as AssetAccount or LiabilityAccount.
+func (c *client) ListAccounts(ctx context.Context, accountType string) ([]*Account, error) {
+	params := url.Values{"type": {accountType}}
+	accounts, err := list(ctx, c, "/api/v1/accounts", params, func(a *Account, id string) { a.Id = id })
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	return accounts, nil
+}
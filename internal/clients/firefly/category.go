@@ -0,0 +1,55 @@
+package firefly
+
+import (
+	"context"
+	"fmt"
+)
+
+// Category is a category of transactions.
+type Category struct {
+	Id    string `json:"-"`
+	Name  string `json:"name"`
+	Notes string `json:"notes"`
+}
+
+// Budget is a budget that transactions are spent from. Budgets are independent of categories.
+type Budget struct {
+	Id     string `json:"-"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+	Notes  string `json:"notes"`
+}
+
+// Tag is a tag that can be attached to transactions.
+type Tag struct {
+	Id          string `json:"-"`
+	Tag         string `json:"tag"`
+	Description string `json:"description"`
+}
+
+// ListCategories fetches all categories.
+func (c *client) ListCategories(ctx context.Context) ([]*Category, error) {
+	cats, err := list(ctx, c, "/api/v1/categories", nil, func(cat *Category, id string) { cat.Id = id })
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return cats, nil
+}
+
+// ListBudgets fetches all budgets.
+func (c *client) ListBudgets(ctx context.Context) ([]*Budget, error) {
+	budgets, err := list(ctx, c, "/api/v1/budgets", nil, func(b *Budget, id string) { b.Id = id })
+	if err != nil {
+		return nil, fmt.Errorf("failed to list budgets: %w", err)
+	}
+	return budgets, nil
+}
+
+// ListTags fetches all tags.
+func (c *client) ListTags(ctx context.Context) ([]*Tag, error) {
+	tags, err := list(ctx, c, "/api/v1/tags", nil, func(t *Tag, id string) { t.Id = id })
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,163 @@
+// Package firefly provides a client for the REST API of Firefly III, a self-hosted personal finance manager.
+package firefly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+)
+
+// PAGE_SIZE is the number of entities requested per page of a list endpoint.
+const PAGE_SIZE = 100
+
+// Client defines the operations supported by the Firefly III API client. List operations return every page.
+type Client interface {
+	ListTransactions(ctx context.Context, startDate, endDate string) ([]*TransactionGroup, error)
+	ListCategories(ctx context.Context) ([]*Category, error)
+	ListBudgets(ctx context.Context) ([]*Budget, error)
+	ListAccounts(ctx context.Context, accountType string) ([]*Account, error)
+	ListTags(ctx context.Context) ([]*Tag, error)
+}
+
+// client implements the Client interface.
+type client struct {
+	*http.Client
+	token   string
+	baseUrl string
+}
+
+// Option customizes a Client created by NewClient.
+type Option func(*client)
+
+// WithTransport makes the client send requests through rt, e.g. to instrument them.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *client) {
+		c.Client = &http.Client{Transport: rt}
+	}
+}
+
+// NewClient creates a new client of the Firefly III instance at baseUrl, such as "https://firefly.example.com",
+// authenticated with a personal access token.
+func NewClient(baseUrl, token string, opts ...Option) Client {
+	c := &client{
+		Client:  http.DefaultClient,
+		token:   token,
+		baseUrl: baseUrl,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// resource is an entity in a JSON:API response.
+type resource[T any] struct {
+	Id         string `json:"id"`
+	Attributes *T     `json:"attributes"`
+}
+
+// page is a page of a list endpoint.
+type page[T any] struct {
+	Data []*resource[T] `json:"data"`
+	Meta struct {
+		Pagination struct {
+			CurrentPage int `json:"current_page"`
+			TotalPages  int `json:"total_pages"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+// list fetches every page of a list endpoint and returns the entities, after passing each to setId together
+// with its ID.
+func list[T any](ctx context.Context, c *client, path string, params url.Values, setId func(*T, string)) ([]*T, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("limit", strconv.Itoa(PAGE_SIZE))
+
+	var result []*T
+	for n := 1; ; n++ {
+		params.Set("page", strconv.Itoa(n))
+		var p page[T]
+		if err := c.get(ctx, path, params, &p); err != nil {
+			return nil, err
+		}
+		for _, r := range p.Data {
+			if r.Attributes == nil {
+				continue
+			}
+			setId(r.Attributes, r.Id)
+			result = append(result, r.Attributes)
+		}
+		if p.Meta.Pagination.CurrentPage >= p.Meta.Pagination.TotalPages {
+			return result, nil
+		}
+	}
+}
+
+// get sends a GET request to the given path with query parameters and decodes the response into data.
+// Failed requests are classified with datasource.ErrorKind.
+func (c *client) get(ctx context.Context, path string, params url.Values, data any) error {
+	u, err := url.Parse(c.baseUrl)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+	u = u.JoinPath(path)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.api+json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return ds.NewError(ds.ErrUpstreamUnavailable, fmt.Errorf("failed to call Firefly III API: %w", err))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ds.NewError(ds.ErrUpstreamUnavailable, fmt.Errorf("failed to read Firefly III API response: %w", err))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("bad status %d: %s", resp.StatusCode, string(body))
+		return ds.ErrorFromHTTPStatus(resp.StatusCode, resp.Header.Get("Retry-After"), err)
+	}
+	if err := json.Unmarshal(body, data); err != nil {
+		return fmt.Errorf("failed to deserialize response: %w", err)
+	}
+	return nil
+}
+
+// Context key type and value
+type fireflyClientKeyType struct{}
+
+var fireflyClientKey = fireflyClientKeyType{}
+
+// WithFireflyCredentials returns an HTTP context function that initializes a new Firefly III client using the
+// supplied base URL, access token and options, and stores the client in the context for future use.
+func WithFireflyCredentials(baseUrl, token string, opts ...Option) func(ctx context.Context, r *http.Request) context.Context {
+	return WithFireflyClient(NewClient(baseUrl, token, opts...))
+}
+
+// WithFireflyClient returns an HTTP context function that stores the supplied client in the context for future use.
+func WithFireflyClient(client Client) func(ctx context.Context, r *http.Request) context.Context {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		return context.WithValue(ctx, fireflyClientKey, client)
+	}
+}
+
+// FromContext retrieves the Firefly III client stored in the context, and reports whether one is present.
+func FromContext(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(fireflyClientKey).(Client)
+	return client, ok
+}
@@ -0,0 +1,104 @@
+package firefly
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// fakeTransport lets us stub out HTTP responses.
+type fakeTransport struct {
+	fn func(req *http.Request) (*http.Response, error)
+}
+
+func (f *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f.fn(req)
+}
+
+// newTestClient returns a client whose HTTP transport is replaced by fn.
+func newTestClient(fn func(req *http.Request) (*http.Response, error)) Client {
+	return NewClient("https://firefly.example.com", "token", WithTransport(&fakeTransport{fn}))
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestListTransactions_Pages(t *testing.T) {
+	pages := map[string]string{
+		"1": `{"data":[{"type":"transactions","id":"10","attributes":{"transactions":[
+			{"transaction_journal_id":"11","type":"withdrawal","date":"2024-01-05T00:00:00+01:00","amount":"12.340000000000",
+			 "currency_code":"EUR","foreign_amount":null,"source_name":"Checking","destination_name":"Jita",
+			 "category_id":"3","category_name":"Fuel","tags":["work"]}]}}],
+			"meta":{"pagination":{"current_page":1,"total_pages":2}}}`,
+		"2": `{"data":[{"type":"transactions","id":"20","attributes":{"group_title":"Split","transactions":[
+			{"transaction_journal_id":"21","type":"deposit","amount":"100","foreign_amount":"110.5"},
+			{"transaction_journal_id":"22","type":"deposit","amount":"5"}]}}],
+			"meta":{"pagination":{"current_page":2,"total_pages":2}}}`,
+	}
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		if got := req.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		q := req.URL.Query()
+		if req.URL.Path != "/api/v1/transactions" || q.Get("start") != "2024-01-01" || q.Get("end") != "2024-01-31" {
+			t.Errorf("unexpected request %s", req.URL)
+		}
+		return jsonResponse(http.StatusOK, pages[q.Get("page")]), nil
+	})
+
+	groups, err := client.ListTransactions(context.Background(), "2024-01-01", "2024-01-31")
+	if err != nil {
+		t.Fatalf("ListTransactions error: %v", err)
+	}
+	if len(groups) != 2 || groups[0].Id != "10" || groups[1].GroupTitle != "Split" || len(groups[1].Transactions) != 2 {
+		t.Fatalf("groups = %+v; want both pages", groups)
+	}
+	split := groups[0].Transactions[0]
+	if split.Amount != types.Money(123400) || split.ForeignAmount != nil || split.CategoryId != "3" || split.Tags[0] != "work" {
+		t.Errorf("split = %+v", split)
+	}
+	if fa := groups[1].Transactions[0].ForeignAmount; fa == nil || *fa != types.Money(1105000) {
+		t.Errorf("foreign amount = %v; want 110.5", fa)
+	}
+}
+
+func TestListAccounts(t *testing.T) {
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != AssetAccount {
+			t.Errorf("unexpected request %s", req.URL)
+		}
+		return jsonResponse(http.StatusOK, `{"data":[{"type":"accounts","id":"1","attributes":{
+			"name":"Checking","type":"asset","account_role":"defaultAsset","active":true,"include_net_worth":true,
+			"currency_code":"EUR","current_balance":"1500.25"}}],"meta":{"pagination":{"current_page":1,"total_pages":1}}}`), nil
+	})
+	accounts, err := client.ListAccounts(context.Background(), AssetAccount)
+	if err != nil {
+		t.Fatalf("ListAccounts error: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Id != "1" || accounts[0].CurrentBalance != types.Money(15002500) {
+		t.Errorf("accounts = %+v", accounts)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusUnauthorized, `{"message":"Unauthenticated."}`), nil
+	})
+	if _, err := client.ListTags(context.Background()); !errors.Is(err, ds.ErrUnauthorized) {
+		t.Errorf("err = %v; want unauthorized", err)
+	}
+
+	client = newTestClient(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	if _, err := client.ListCategories(context.Background()); !errors.Is(err, ds.ErrUpstreamUnavailable) {
+		t.Errorf("err = %v; want upstream unavailable", err)
+	}
+}
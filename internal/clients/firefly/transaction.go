@@ -0,0 +1,63 @@
+package firefly
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// ExpenseAccountType is the source type of deposits that refund an earlier withdrawal.
+const ExpenseAccountType = "Expense account"
+
+// Types of transaction splits.
+const (
+	Withdrawal     = "withdrawal"
+	Deposit        = "deposit"
+	Transfer       = "transfer"
+	OpeningBalance = "opening balance"
+	Reconciliation = "reconciliation"
+)
+
+// TransactionGroup is a transaction, which consists of one or more splits. Most transactions have a single split.
+type TransactionGroup struct {
+	Id           string   `json:"-"`
+	GroupTitle   string   `json:"group_title"`
+	Transactions []*Split `json:"transactions"`
+}
+
+// Split is a part of a transaction with its own amount, accounts, category and budget. Amounts are always
+// positive; the type tells the direction in which money moves from the source to the destination account.
+type Split struct {
+	JournalId           string       `json:"transaction_journal_id"`
+	Type                string       `json:"type"`
+	Date                string       `json:"date"`
+	Amount              types.Money  `json:"amount"`
+	CurrencyCode        string       `json:"currency_code"`
+	ForeignAmount       *types.Money `json:"foreign_amount"`
+	ForeignCurrencyCode string       `json:"foreign_currency_code"`
+	Description         string       `json:"description"`
+	Notes               string       `json:"notes"`
+	SourceId            string       `json:"source_id"`
+	SourceName          string       `json:"source_name"`
+	SourceType          string       `json:"source_type"`
+	DestinationId       string       `json:"destination_id"`
+	DestinationName     string       `json:"destination_name"`
+	CategoryId          string       `json:"category_id"`
+	CategoryName        string       `json:"category_name"`
+	BudgetId            string       `json:"budget_id"`
+	BudgetName          string       `json:"budget_name"`
+	// Tags are the names of the tags of the split.
+	Tags []string `json:"tags"`
+}
+
+// ListTransactions fetches the transactions dated between startDate and endDate inclusive, in "YYYY-MM-DD" format.
+func (c *client) ListTransactions(ctx context.Context, startDate, endDate string) ([]*TransactionGroup, error) {
+	params := url.Values{"start": {startDate}, "end": {endDate}, "type": {"all"}}
+	groups, err := list(ctx, c, "/api/v1/transactions", params, func(g *TransactionGroup, id string) { g.Id = id })
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	return groups, nil
+}
@@ -12,6 +12,8 @@ import (
 	"net/url"
 	"strconv"
 	"time"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
 )
 
 // BASE_URL is the default Kubera API base URL.
@@ -19,8 +21,12 @@ const BASE_URL = "https://api.kubera.com/api"
 
 // Client defines the operations supported by the Kubera API client.
 type Client interface {
-	// GetPortfolio fetches the portfolio data for the configured portfolio ID.
-	GetPortfolio(ctx context.Context) (*Portfolio, error)
+	// ListPortfolios lists every portfolio the credentials have access to.
+	ListPortfolios(ctx context.Context) ([]*PortfolioSummary, error)
+	// GetPortfolio fetches the portfolio data for the given portfolio ID, or for the configured one if id is empty.
+	GetPortfolio(ctx context.Context, id string) (*Portfolio, error)
+	// PortfolioID returns the configured portfolio ID, which may be empty.
+	PortfolioID() string
 }
 
 // client implements the Client interface and handles authentication and request signing.
@@ -32,18 +38,39 @@ type client struct {
 	baseUrl     string
 }
 
-// NewClient creates a new Kubera API client configured with apiKey, apiSecret, and portfolioId.
-func NewClient(apiKey, apiSecret, portfolioId string) Client {
-	return &client{
+// Option customizes a Client created by NewClient.
+type Option func(*client)
+
+// WithTransport makes the client send requests through rt, e.g. to instrument them.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *client) {
+		c.Client = &http.Client{Transport: rt}
+	}
+}
+
+// NewClient creates a new Kubera API client configured with apiKey, apiSecret, portfolioId and options.
+// The portfolio ID is optional and only selects the portfolio fetched when no other is requested.
+func NewClient(apiKey, apiSecret, portfolioId string, opts ...Option) Client {
+	c := &client{
 		Client:      http.DefaultClient,
 		apiKey:      apiKey,
 		apiSecret:   apiSecret,
 		portfolioId: portfolioId,
 		baseUrl:     BASE_URL,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// PortfolioID implements Client.
+func (c *client) PortfolioID() string {
+	return c.portfolioId
 }
 
 // get constructs and signs a GET request to the specified API path, executes it, and returns the response bytes.
+// Failed requests are classified with datasource.ErrorKind.
 func (c *client) get(ctx context.Context, path string) ([]byte, error) {
 	method := http.MethodGet
 	fullURL := c.baseUrl + path
@@ -79,14 +106,15 @@ func (c *client) get(ctx context.Context, path string) ([]byte, error) {
 	// Perform request
 	resp, err := c.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, ds.NewError(ds.ErrUpstreamUnavailable, fmt.Errorf("request failed: %w", err))
 	}
 	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("bad status %d: %s", resp.StatusCode, string(bodyBytes))
+		err := fmt.Errorf("bad status %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, ds.ErrorFromHTTPStatus(resp.StatusCode, resp.Header.Get("Retry-After"), err)
 	}
 
 	// Read and return response body
@@ -98,9 +126,9 @@ type kuberaClientKeyType struct{}
 var kuberaClientKey = kuberaClientKeyType{}
 
 // WithKuberaCredentials returns an HTTPContextFunc that initializes a Kubera Client using the provided credentials
-// and stores that client in the context for future use.
-func WithKuberaCredentials(apiKey, apiSecret, portfolioId string) func(ctx context.Context, r *http.Request) context.Context {
-	client := NewClient(apiKey, apiSecret, portfolioId)
+// and options, and stores that client in the context for future use.
+func WithKuberaCredentials(apiKey, apiSecret, portfolioId string, opts ...Option) func(ctx context.Context, r *http.Request) context.Context {
+	client := NewClient(apiKey, apiSecret, portfolioId, opts...)
 	return WithKuberaClient(client)
 }
 
@@ -111,11 +139,8 @@ func WithKuberaClient(client Client) func(ctx context.Context, r *http.Request)
 	}
 }
 
-// FromContext fetches the Kubera Client from the context. It panics if no client is found.
-func FromContext(ctx context.Context) Client {
+// FromContext retrieves the Kubera client stored in the context, and reports whether one is present.
+func FromContext(ctx context.Context) (Client, bool) {
 	client, ok := ctx.Value(kuberaClientKey).(Client)
-	if !ok {
-		panic("Kubera client not found in context")
-	}
-	return client
+	return client, ok
 }
@@ -2,10 +2,14 @@ package kubera
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
 )
 
 // fakeTransport lets us stub HTTP responses for client.get.
@@ -72,22 +76,38 @@ func TestClientGet_BadStatus(t *testing.T) {
 	}
 }
 
+func TestClientGet_RateLimited(t *testing.T) {
+	cli := newTestClient("k", "s", "pid", func(req *http.Request) (*http.Response, error) {
+		header := make(http.Header)
+		header.Set("Retry-After", "60")
+		return &http.Response{
+			StatusCode: 429,
+			Body:       io.NopCloser(strings.NewReader("slow down")),
+			Header:     header,
+		}, nil
+	})
+	_, err := cli.get(context.Background(), "/foo")
+	if !errors.Is(err, ds.ErrRateLimited) {
+		t.Fatalf("err = %v; want ds.ErrRateLimited", err)
+	}
+	if dsErr := ds.AsError(err); dsErr.RetryAfter != time.Minute {
+		t.Errorf("RetryAfter = %v; want %v", dsErr.RetryAfter, time.Minute)
+	}
+}
+
 func TestWithKuberaClientAndFromContext(t *testing.T) {
 	ctx := context.Background()
 	req, _ := http.NewRequest("GET", "/", nil)
 	fn := WithKuberaCredentials("key", "secret", "pid")
 	newCtx := fn(ctx, req)
-	c := FromContext(newCtx)
-	if c == nil {
+	c, ok := FromContext(newCtx)
+	if !ok || c == nil {
 		t.Fatal("FromContext returned nil, expected non-nil client")
 	}
 }
 
-func TestFromContext_Panic(t *testing.T) {
-	defer func() {
-		if r := recover(); r == nil {
-			t.Fatal("expected panic when no client in context")
-		}
-	}()
-	_ = FromContext(context.Background())
+func TestFromContext_Missing(t *testing.T) {
+	if c, ok := FromContext(context.Background()); ok || c != nil {
+		t.Fatalf("FromContext = %v, %v; expected no client in context", c, ok)
+	}
 }
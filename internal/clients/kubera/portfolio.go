@@ -3,7 +3,9 @@ package kubera
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 )
 
 // Portfolio represents a Kubera portfolio containing its ID, name, assets, and debts.
@@ -14,6 +16,13 @@ type Portfolio struct {
 	Debts  []*DebtPosition  `json:"debt"`
 }
 
+// PortfolioSummary identifies a Kubera portfolio by its ID and name, along with the currency of its values.
+type PortfolioSummary struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
 // getPortfolioResponse wraps the JSON payload returned by the Kubera GetPortfolio API,
 // including the Data field on success or an error code on failure.
 type getPortfolioResponse struct {
@@ -21,22 +30,48 @@ type getPortfolioResponse struct {
 	ErrorCode int64      `json:"errorCode"`
 }
 
-// GetPortfolio retrieves the configured portfolio from the Kubera API endpoint,
-// validates the response code, and unmarshals the result into a Portfolio struct.
-func (c *client) GetPortfolio(ctx context.Context) (*Portfolio, error) {
-	data, err := c.get(ctx, fmt.Sprintf("/v3/data/portfolio/%s", c.portfolioId))
-	if err != nil {
-		return nil, fmt.Errorf("failed to call Kubera API: %w", err)
+// listPortfoliosResponse wraps the JSON payload returned by the Kubera portfolio listing API.
+type listPortfoliosResponse struct {
+	Data      []*PortfolioSummary `json:"data"`
+	ErrorCode int64               `json:"errorCode"`
+}
+
+// ListPortfolios retrieves the portfolios the credentials have access to from the Kubera API endpoint.
+func (c *client) ListPortfolios(ctx context.Context) ([]*PortfolioSummary, error) {
+	var response listPortfoliosResponse
+	if err := c.call(ctx, "/v3/data/portfolio", &response, &response.ErrorCode); err != nil {
+		return nil, err
 	}
+	return response.Data, nil
+}
 
+// GetPortfolio retrieves the portfolio with the given ID, or the configured portfolio if id is empty, from the
+// Kubera API endpoint, validates the response code, and unmarshals the result into a Portfolio struct.
+func (c *client) GetPortfolio(ctx context.Context, id string) (*Portfolio, error) {
+	if id == "" {
+		id = c.portfolioId
+	}
+	if id == "" {
+		return nil, errors.New("no Kubera portfolio ID configured")
+	}
 	var response getPortfolioResponse
-	if err := json.Unmarshal(data, &response); err != nil {
-		return nil, fmt.Errorf("failed to deserialize response: %w", err)
+	if err := c.call(ctx, "/v3/data/portfolio/"+url.PathEscape(id), &response, &response.ErrorCode); err != nil {
+		return nil, err
 	}
+	return response.Data, nil
+}
 
-	if response.ErrorCode > 0 {
-		return nil, fmt.Errorf("Kubera API error: %d", response.ErrorCode)
+// call fetches path and unmarshals the response into v, failing if the error code it holds is set.
+func (c *client) call(ctx context.Context, path string, v any, errorCode *int64) error {
+	data, err := c.get(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to call Kubera API: %w", err)
 	}
-
-	return response.Data, nil
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to deserialize response: %w", err)
+	}
+	if *errorCode > 0 {
+		return fmt.Errorf("Kubera API error: %d", *errorCode)
+	}
+	return nil
 }
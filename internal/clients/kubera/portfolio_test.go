@@ -41,7 +41,7 @@ func TestGetPortfolio_Success(t *testing.T) {
 		}, nil
 	})
 
-	got, err := cli.GetPortfolio(context.Background())
+	got, err := cli.GetPortfolio(context.Background(), "")
 	if err != nil {
 		t.Fatalf("GetPortfolio error: %v", err)
 	}
@@ -63,7 +63,7 @@ func TestGetPortfolio_APIError(t *testing.T) {
 		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(resp)), Header: make(http.Header)}, nil
 	})
 
-	_, err := cli.GetPortfolio(context.Background())
+	_, err := cli.GetPortfolio(context.Background(), "")
 	if err == nil || !strings.Contains(err.Error(), "Kubera API error: 123") {
 		t.Errorf("err = %v; want error containing Kubera API error: 123", err)
 	}
@@ -74,7 +74,7 @@ func TestGetPortfolio_NetworkFailure(t *testing.T) {
 		return nil, errors.New("conn refused")
 	})
 
-	_, err := cli.GetPortfolio(context.Background())
+	_, err := cli.GetPortfolio(context.Background(), "")
 	if err == nil || !strings.Contains(err.Error(), "failed to call Kubera API") {
 		t.Errorf("err = %v; want error prefix failed to call Kubera API", err)
 	}
@@ -85,8 +85,54 @@ func TestGetPortfolio_DeserializeError(t *testing.T) {
 		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("not json")), Header: make(http.Header)}, nil
 	})
 
-	_, err := cli.GetPortfolio(context.Background())
+	_, err := cli.GetPortfolio(context.Background(), "")
 	if err == nil || !strings.Contains(err.Error(), "failed to deserialize response") {
 		t.Errorf("err = %v; want error containing failed to deserialize response", err)
 	}
 }
+
+func TestGetPortfolio_ByID(t *testing.T) {
+	cli := newTestClient("k", "s", "", func(req *http.Request) (*http.Response, error) {
+		if want := BASE_URL + "/v3/data/portfolio/estate"; req.URL.String() != want {
+			t.Errorf("URL = %q; want %q", req.URL.String(), want)
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(`{"data":{"id":"estate"}}`)), Header: make(http.Header)}, nil
+	})
+
+	got, err := cli.GetPortfolio(context.Background(), "estate")
+	if err != nil || got.Id != "estate" {
+		t.Fatalf("GetPortfolio = %+v, %v; want the requested portfolio", got, err)
+	}
+	if _, err := cli.GetPortfolio(context.Background(), ""); err == nil {
+		t.Error("GetPortfolio succeeded; want an error without a configured portfolio ID")
+	}
+}
+
+func TestListPortfolios_Success(t *testing.T) {
+	resp := `{"data":[{"id":"p1","name":"Personal","currency":"USD"},{"id":"p2","name":"Estate","currency":"USD"}],"errorCode":0}`
+	cli := newTestClient("k", "s", "", func(req *http.Request) (*http.Response, error) {
+		if want := BASE_URL + "/v3/data/portfolio"; req.URL.String() != want {
+			t.Errorf("URL = %q; want %q", req.URL.String(), want)
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(resp)), Header: make(http.Header)}, nil
+	})
+
+	got, err := cli.ListPortfolios(context.Background())
+	if err != nil {
+		t.Fatalf("ListPortfolios error: %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "Personal" || got[1].Id != "p2" || got[1].Currency != "USD" {
+		t.Errorf("portfolios = %+v", got)
+	}
+}
+
+func TestListPortfolios_APIError(t *testing.T) {
+	cli := newTestClient("k", "s", "", func(_ *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(`{"data":null,"errorCode":7}`)), Header: make(http.Header)}, nil
+	})
+
+	_, err := cli.ListPortfolios(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Kubera API error: 7") {
+		t.Errorf("err = %v; want error containing Kubera API error: 7", err)
+	}
+}
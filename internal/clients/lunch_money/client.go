@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
 )
 
 // BASE_URL is the default Lunch Money API base URL.
@@ -24,13 +26,27 @@ type client struct {
 	baseUrl   string
 }
 
-// NewClient creates and returns a new Client initialized with the provided auth token.
-func NewClient(token string) Client {
-	return &client{
+// Option customizes a Client created by NewClient.
+type Option func(*client)
+
+// WithTransport makes the client send requests through rt, e.g. to instrument them.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *client) {
+		c.Client = &http.Client{Transport: rt}
+	}
+}
+
+// NewClient creates and returns a new Client initialized with the provided auth token and options.
+func NewClient(token string, opts ...Option) Client {
+	c := &client{
 		Client:    http.DefaultClient,
 		authToken: token,
 		baseUrl:   BASE_URL,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // get sends an HTTP GET request to the client's base URL, appends the given path and query parameters,
@@ -51,7 +67,7 @@ func (c *client) get(ctx context.Context, path string, params map[string]string)
 
 // send constructs and executes an HTTP request with the specified method, URL, and body,
 // sets the Authorization header using the client's token, verifies a 2xx status code,
-// and returns the response body or an error.
+// and returns the response body or an error classified with datasource.ErrorKind.
 func (c *client) send(ctx context.Context, method, url string, body io.Reader) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
@@ -62,14 +78,15 @@ func (c *client) send(ctx context.Context, method, url string, body io.Reader) (
 	// Execute
 	resp, err := c.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, ds.NewError(ds.ErrUpstreamUnavailable, fmt.Errorf("request failed: %w", err))
 	}
 	defer resp.Body.Close()
 
 	// Check status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("bad status %d: %s", resp.StatusCode, string(body))
+		err := fmt.Errorf("bad status %d: %s", resp.StatusCode, string(body))
+		return nil, ds.ErrorFromHTTPStatus(resp.StatusCode, resp.Header.Get("Retry-After"), err)
 	}
 
 	// Read and return body
@@ -82,9 +99,9 @@ type lmClientKeyType struct{}
 var lmClientKey = lmClientKeyType{}
 
 // WithLunchMoneyCredentials returns an HTTP context function that initializes a new LunchMoney client using
-// the supplied API token and stores the client in the context for future use.
-func WithLunchMoneyCredentials(token string) func(ctx context.Context, r *http.Request) context.Context {
-	return WithLunchMoneyClient(NewClient(token))
+// the supplied API token and options, and stores the client in the context for future use.
+func WithLunchMoneyCredentials(token string, opts ...Option) func(ctx context.Context, r *http.Request) context.Context {
+	return WithLunchMoneyClient(NewClient(token, opts...))
 }
 
 // WithLunchMoneyCredentials returns an HTTP context function that stores the supplied client in the context for future use.
@@ -94,12 +111,8 @@ func WithLunchMoneyClient(client Client) func(ctx context.Context, r *http.Reque
 	}
 }
 
-// FromContext retrieves the LunchMoney API client stored in the context.
-// It panics if no client is present.
-func FromContext(ctx context.Context) Client {
+// FromContext retrieves the Lunch Money client stored in the context, and reports whether one is present.
+func FromContext(ctx context.Context) (Client, bool) {
 	client, ok := ctx.Value(lmClientKey).(Client)
-	if !ok {
-		panic("Lunch Money client not found in context")
-	}
-	return client
+	return client, ok
 }
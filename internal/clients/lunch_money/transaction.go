@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
 	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
 )
 
@@ -60,7 +61,7 @@ func (c *client) ListTransactions(ctx context.Context, startDate, endDate string
 	if response.HasMore {
 		// 10K ceiling should be sufficient for most ML use cases
 		// Implement proper pagination if ever needed
-		return nil, fmt.Errorf("too many transactions, try smaller time interval")
+		return nil, ds.Errorf(ds.ErrRangeTooLarge, "too many transactions, try smaller time interval")
 	}
 
 	return response.Transactions, nil
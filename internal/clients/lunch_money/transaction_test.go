@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
 	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
 )
 
@@ -132,3 +133,33 @@ func TestListTransactions_BadJSON(t *testing.T) {
 		t.Errorf("error = %q; want prefix %q", err.Error(), "failed to deserialize response")
 	}
 }
+
+func TestListTransactions_Unauthorized(t *testing.T) {
+	cli := newTestClient("bad", func(_ *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 401,
+			Body:       io.NopCloser(strings.NewReader("unauthorized")),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	_, err := cli.ListTransactions(context.Background(), "2023-01-01", "2023-01-31")
+	if !errors.Is(err, ds.ErrUnauthorized) {
+		t.Errorf("err = %v; want ds.ErrUnauthorized", err)
+	}
+}
+
+func TestListTransactions_HasMore(t *testing.T) {
+	cli := newTestClient("tk", func(_ *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"transactions": [], "has_more": true}`)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	_, err := cli.ListTransactions(context.Background(), "2020-01-01", "2023-12-31")
+	if !errors.Is(err, ds.ErrRangeTooLarge) {
+		t.Errorf("err = %v; want ds.ErrRangeTooLarge", err)
+	}
+}
@@ -0,0 +1,87 @@
+package simplefin_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/clients/simplefin"
+	"github.com/wyvernzora/personal-finance-mcp/internal/clients/simplefin/simplefintest"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func newServer(t *testing.T) *simplefintest.Server {
+	t.Helper()
+	srv := simplefintest.NewServer("user", "secret")
+	t.Cleanup(srv.Close)
+	srv.PutAccount(&simplefin.Account{
+		Org: simplefin.Organization{Name: "First Bank"}, Id: "acct-1", Name: "Checking", Currency: "USD",
+		Balance: types.Money(12345600), BalanceDate: 1706745600,
+		Transactions: []*simplefin.Transaction{
+			{Id: "t1", Posted: 1704412800, Amount: types.Money(-123400), Description: "JITA MARKET"},
+			{Id: "t2", Posted: 1707091200, Amount: types.Money(-10000), Description: "LATER"},
+			{Id: "t3", TransactedAt: 1704499200, Amount: types.Money(-5000), Description: "PENDING", Pending: true},
+		},
+	})
+	return srv
+}
+
+func TestListAccounts(t *testing.T) {
+	srv := newServer(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	set, err := srv.NewClient().ListAccounts(context.Background(), start, start.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("ListAccounts error: %v", err)
+	}
+	if len(set.Accounts) != 1 || set.Accounts[0].Org.Name != "First Bank" || set.Accounts[0].Balance != types.Money(12345600) {
+		t.Fatalf("accounts = %+v; want the checking account", set.Accounts)
+	}
+	txns := set.Accounts[0].Transactions
+	if len(txns) != 2 || txns[0].Amount != types.Money(-123400) || !txns[1].Pending {
+		t.Errorf("transactions = %+v; want the posted and the pending one in January", txns)
+	}
+
+	req := srv.Requests()[0]
+	if q := req.URL.Query(); q.Get("start-date") != "1704067200" || q.Get("pending") != "1" {
+		t.Errorf("query = %v; want start date and pending", q)
+	}
+	if req.URL.User != nil {
+		t.Error("credentials must not be sent in the URL")
+	}
+}
+
+func TestListBalances(t *testing.T) {
+	srv := newServer(t)
+	srv.SetErrors("Connection to Second Bank may need attention")
+	set, err := srv.NewClient().ListBalances(context.Background())
+	if err != nil {
+		t.Fatalf("ListBalances error: %v", err)
+	}
+	if len(set.Accounts) != 1 || len(set.Accounts[0].Transactions) != 0 || len(set.Errors) != 1 {
+		t.Errorf("set = %+v; want balances without transactions and the error", set)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	srv := newServer(t)
+	ctx := context.Background()
+
+	srv.Password = "rotated"
+	if _, err := srv.NewClient().ListBalances(ctx); err != nil {
+		t.Fatalf("ListBalances error: %v", err)
+	}
+	if _, err := simplefin.NewClient(srv.URL + "/simplefin").ListBalances(ctx); !errors.Is(err, ds.ErrUnauthorized) {
+		t.Errorf("err = %v; want unauthorized for access URL without credentials", err)
+	}
+	stale := srv.AccessURL()
+	srv.Password = "revoked"
+	if _, err := simplefin.NewClient(stale).ListBalances(ctx); !errors.Is(err, ds.ErrUnauthorized) {
+		t.Errorf("err = %v; want unauthorized for revoked access URL", err)
+	}
+	srv.Close()
+	if _, err := srv.NewClient().ListBalances(ctx); !errors.Is(err, ds.ErrUpstreamUnavailable) {
+		t.Errorf("err = %v; want upstream unavailable", err)
+	}
+}
@@ -0,0 +1,126 @@
+// Package simplefintest provides a stand-in SimpleFIN server for tests. It serves accounts from memory to clients
+// with the accepted credentials and filters transactions like a real server does.
+package simplefintest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/clients/simplefin"
+)
+
+// Server is a stand-in SimpleFIN server.
+type Server struct {
+	*httptest.Server
+	// Username and Password are the credentials the server accepts.
+	Username string
+	Password string
+
+	mu       sync.Mutex
+	accounts []*simplefin.Account
+	errors   []string
+	requests []*http.Request
+}
+
+// NewServer starts a stand-in server that accepts the given credentials. The caller must Close the server.
+func NewServer(username, password string) *Server {
+	s := &Server{Username: username, Password: password}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /simplefin/accounts", s.handleAccounts)
+	s.Server = httptest.NewServer(s.authenticate(mux))
+	return s
+}
+
+// AccessURL returns the access URL of the server, with the accepted credentials.
+func (s *Server) AccessURL() string {
+	u, _ := url.Parse(s.URL)
+	u.User = url.UserPassword(s.Username, s.Password)
+	return u.JoinPath("simplefin").String()
+}
+
+// NewClient returns a client of the server with the accepted credentials.
+func (s *Server) NewClient() simplefin.Client {
+	return simplefin.NewClient(s.AccessURL())
+}
+
+// Requests returns the requests served so far.
+func (s *Server) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+// PutAccount adds or replaces an account with its transactions, identified by its ID.
+func (s *Server) PutAccount(account *simplefin.Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, acct := range s.accounts {
+		if acct.Id == account.Id {
+			s.accounts[i] = account
+			return
+		}
+	}
+	s.accounts = append(s.accounts, account)
+}
+
+// SetErrors sets the error messages included in responses.
+func (s *Server) SetErrors(errors ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = errors
+}
+
+// authenticate rejects requests without the accepted credentials and records the others.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != s.Username || password != s.Password {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	start, _ := strconv.ParseInt(q.Get("start-date"), 10, 64)
+	end, err := strconv.ParseInt(q.Get("end-date"), 10, 64)
+	if err != nil {
+		end = 1<<63 - 1
+	}
+	balancesOnly := q.Get("balances-only") == "1"
+	pending := q.Get("pending") == "1"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	accounts := make([]*simplefin.Account, 0, len(s.accounts))
+	for _, acct := range s.accounts {
+		copied := *acct
+		copied.Transactions = []*simplefin.Transaction{}
+		for _, txn := range acct.Transactions {
+			posted := txn.Posted
+			if posted == 0 {
+				posted = txn.TransactedAt
+			}
+			if balancesOnly || posted < start || posted >= end || (txn.Pending && !pending) {
+				continue
+			}
+			copied.Transactions = append(copied.Transactions, txn)
+		}
+		accounts = append(accounts, &copied)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	errors := s.errors
+	if errors == nil {
+		errors = []string{}
+	}
+	_ = json.NewEncoder(w).Encode(&simplefin.AccountSet{Errors: errors, Accounts: accounts})
+}
@@ -0,0 +1,32 @@
+package ynab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Account is an account of a budget. Tracking accounts are off budget: their transactions are not categorized.
+type Account struct {
+	Id       string     `json:"id"`
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	OnBudget bool       `json:"on_budget"`
+	Closed   bool       `json:"closed"`
+	Note     string     `json:"note"`
+	Balance  Milliunits `json:"balance"`
+	Deleted  bool       `json:"deleted"`
+}
+
+// ListAccounts fetches the accounts of a budget that changed since serverKnowledge.
+func (c *client) ListAccounts(ctx context.Context, budgetID string, serverKnowledge int64) (*Delta[*Account], error) {
+	var data struct {
+		Accounts        []*Account `json:"accounts"`
+		ServerKnowledge int64      `json:"server_knowledge"`
+	}
+	path := "/v1/budgets/" + url.PathEscape(budgetID) + "/accounts"
+	if err := c.get(ctx, path, deltaParams(serverKnowledge), &data); err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	return &Delta[*Account]{Items: data.Accounts, ServerKnowledge: data.ServerKnowledge}, nil
+}
@@ -0,0 +1,27 @@
+package ynab
+
+import (
+	"context"
+	"fmt"
+)
+
+// Budget is a YNAB budget, also called a plan.
+type Budget struct {
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	LastModifiedOn string `json:"last_modified_on"`
+	CurrencyFormat struct {
+		IsoCode string `json:"iso_code"`
+	} `json:"currency_format"`
+}
+
+// ListBudgets fetches the budgets the access token has access to.
+func (c *client) ListBudgets(ctx context.Context) ([]*Budget, error) {
+	var data struct {
+		Budgets []*Budget `json:"budgets"`
+	}
+	if err := c.get(ctx, "/v1/budgets", nil, &data); err != nil {
+		return nil, fmt.Errorf("failed to list budgets: %w", err)
+	}
+	return data.Budgets, nil
+}
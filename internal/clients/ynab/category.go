@@ -0,0 +1,50 @@
+package ynab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// InternalCategoryGroup is the name of the category group holding the categories YNAB manages itself, such as
+// InflowCategory and Uncategorized.
+const InternalCategoryGroup = "Internal Master Category"
+
+// InflowCategory is the name of the internal category of income, which is assigned to budget categories later.
+const InflowCategory = "Inflow: Ready to Assign"
+
+// CategoryGroup groups the categories of a budget.
+type CategoryGroup struct {
+	Id         string      `json:"id"`
+	Name       string      `json:"name"`
+	Hidden     bool        `json:"hidden"`
+	Deleted    bool        `json:"deleted"`
+	Categories []*Category `json:"categories"`
+}
+
+// Category is a budget category. Budgeted, activity and balance refer to the current month.
+type Category struct {
+	Id              string     `json:"id"`
+	CategoryGroupId string     `json:"category_group_id"`
+	Name            string     `json:"name"`
+	Hidden          bool       `json:"hidden"`
+	Note            string     `json:"note"`
+	Budgeted        Milliunits `json:"budgeted"`
+	Activity        Milliunits `json:"activity"`
+	Balance         Milliunits `json:"balance"`
+	Deleted         bool       `json:"deleted"`
+}
+
+// ListCategories fetches the category groups of a budget, with their categories, that changed since
+// serverKnowledge.
+func (c *client) ListCategories(ctx context.Context, budgetID string, serverKnowledge int64) (*Delta[*CategoryGroup], error) {
+	var data struct {
+		CategoryGroups  []*CategoryGroup `json:"category_groups"`
+		ServerKnowledge int64            `json:"server_knowledge"`
+	}
+	path := "/v1/budgets/" + url.PathEscape(budgetID) + "/categories"
+	if err := c.get(ctx, path, deltaParams(serverKnowledge), &data); err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return &Delta[*CategoryGroup]{Items: data.CategoryGroups, ServerKnowledge: data.ServerKnowledge}, nil
+}
@@ -0,0 +1,159 @@
+// Package ynab provides a client for the YNAB (You Need A Budget) API.
+package ynab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// BASE_URL is the default YNAB API base URL.
+const BASE_URL = "https://api.ynab.com"
+
+// LastUsedBudget is the budget ID that refers to the budget the user last opened.
+const LastUsedBudget = "last-used"
+
+// Client defines the operations supported by the YNAB API client. Operations taking a server knowledge only return
+// the entities that changed since the server knowledge was returned by an earlier call, or all entities if it is
+// zero; see Delta.
+type Client interface {
+	ListBudgets(ctx context.Context) ([]*Budget, error)
+	ListAccounts(ctx context.Context, budgetID string, serverKnowledge int64) (*Delta[*Account], error)
+	ListCategories(ctx context.Context, budgetID string, serverKnowledge int64) (*Delta[*CategoryGroup], error)
+	ListTransactions(ctx context.Context, budgetID, sinceDate string, serverKnowledge int64) (*Delta[*Transaction], error)
+}
+
+// Delta holds the entities returned by a delta request, together with the server knowledge to pass to the next
+// request to only receive the entities that changed in the meantime. Entities deleted since the previous request
+// are included with their Deleted flag set.
+type Delta[T any] struct {
+	Items           []T
+	ServerKnowledge int64
+}
+
+// Milliunits is an amount of currency in thousandths of a unit, as used throughout the YNAB API. Outflows are
+// negative and inflows positive.
+type Milliunits int64
+
+// Money converts the amount into types.Money, keeping its sign.
+func (m Milliunits) Money() types.Money {
+	return types.Money(m) * (types.FACTOR / 1000)
+}
+
+// client implements the Client interface.
+type client struct {
+	*http.Client
+	token   string
+	baseUrl string
+}
+
+// Option customizes a Client created by NewClient.
+type Option func(*client)
+
+// WithTransport makes the client send requests through rt, e.g. to instrument them.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *client) {
+		c.Client = &http.Client{Transport: rt}
+	}
+}
+
+// WithBaseURL makes the client send requests to another server, e.g. a fake one in tests.
+func WithBaseURL(baseUrl string) Option {
+	return func(c *client) {
+		c.baseUrl = baseUrl
+	}
+}
+
+// NewClient creates a new YNAB API client authenticated with a personal access token.
+func NewClient(token string, opts ...Option) Client {
+	c := &client{
+		Client:  http.DefaultClient,
+		token:   token,
+		baseUrl: BASE_URL,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// get sends a GET request to the given path with query parameters and decodes the data field of the response
+// envelope into data. Failed requests are classified with datasource.ErrorKind.
+func (c *client) get(ctx context.Context, path string, params url.Values, data any) error {
+	u, err := url.Parse(c.baseUrl)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+	u = u.JoinPath(path)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return ds.NewError(ds.ErrUpstreamUnavailable, fmt.Errorf("failed to call YNAB API: %w", err))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ds.NewError(ds.ErrUpstreamUnavailable, fmt.Errorf("failed to read YNAB API response: %w", err))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("bad status %d: %s", resp.StatusCode, string(body))
+		return ds.ErrorFromHTTPStatus(resp.StatusCode, resp.Header.Get("Retry-After"), err)
+	}
+
+	envelope := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("failed to deserialize response: %w", err)
+	}
+	return nil
+}
+
+// deltaParams returns the query parameters of a delta request.
+func deltaParams(serverKnowledge int64) url.Values {
+	params := url.Values{}
+	if serverKnowledge > 0 {
+		params.Set("last_knowledge_of_server", strconv.FormatInt(serverKnowledge, 10))
+	}
+	return params
+}
+
+// Context key type and value
+type ynabClientKeyType struct{}
+
+var ynabClientKey = ynabClientKeyType{}
+
+// WithYNABCredentials returns an HTTP context function that initializes a new YNAB client using the supplied
+// access token and options, and stores the client in the context for future use.
+func WithYNABCredentials(token string, opts ...Option) func(ctx context.Context, r *http.Request) context.Context {
+	return WithYNABClient(NewClient(token, opts...))
+}
+
+// WithYNABClient returns an HTTP context function that stores the supplied client in the context for future use.
+func WithYNABClient(client Client) func(ctx context.Context, r *http.Request) context.Context {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		return context.WithValue(ctx, ynabClientKey, client)
+	}
+}
+
+// FromContext retrieves the YNAB client stored in the context, and reports whether one is present.
+func FromContext(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(ynabClientKey).(Client)
+	return client, ok
+}
@@ -0,0 +1,108 @@
+package ynab_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/clients/ynab"
+	"github.com/wyvernzora/personal-finance-mcp/internal/clients/ynab/ynabtest"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func newServer(t *testing.T) *ynabtest.Server {
+	t.Helper()
+	srv := ynabtest.NewServer("token", &ynab.Budget{Id: "budget-1", Name: "Fleet"})
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestMilliunits_Money(t *testing.T) {
+	if got := ynab.Milliunits(-12340).Money(); got != types.Money(-123400) {
+		t.Errorf("Money() = %d; want -12.34 in types.Money", got)
+	}
+}
+
+func TestListBudgets(t *testing.T) {
+	srv := newServer(t)
+	budgets, err := srv.NewClient().ListBudgets(context.Background())
+	if err != nil {
+		t.Fatalf("ListBudgets error: %v", err)
+	}
+	if len(budgets) != 1 || budgets[0].Id != "budget-1" || budgets[0].Name != "Fleet" {
+		t.Errorf("budgets = %+v; want the served budget", budgets)
+	}
+}
+
+func TestListTransactions_Delta(t *testing.T) {
+	srv := newServer(t)
+	srv.PutTransaction(&ynab.Transaction{Id: "t1", Date: "2024-01-05", Amount: -12340, PayeeName: "Jita"})
+	srv.PutTransaction(&ynab.Transaction{Id: "t0", Date: "2023-12-31", Amount: -1000, PayeeName: "Old"})
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	first, err := client.ListTransactions(ctx, "budget-1", "2024-01-01", 0)
+	if err != nil {
+		t.Fatalf("ListTransactions error: %v", err)
+	}
+	if len(first.Items) != 1 || first.Items[0].Amount != -12340 || first.ServerKnowledge != 2 {
+		t.Fatalf("delta = %+v; want one transaction since 2024-01-01 and server knowledge 2", first)
+	}
+
+	srv.PutTransaction(&ynab.Transaction{Id: "t1", Date: "2024-01-05", Deleted: true})
+	srv.PutTransaction(&ynab.Transaction{Id: "t2", Date: "2024-01-06", Amount: 5000})
+	second, err := client.ListTransactions(ctx, "budget-1", "2024-01-01", first.ServerKnowledge)
+	if err != nil {
+		t.Fatalf("ListTransactions error: %v", err)
+	}
+	if len(second.Items) != 2 || !second.Items[0].Deleted || second.Items[1].Id != "t2" || second.ServerKnowledge != 4 {
+		t.Errorf("delta = %+v; want deleted t1 and new t2", second)
+	}
+	if q := srv.Requests()[1].URL.Query(); q.Get("last_knowledge_of_server") != "2" || q.Get("since_date") != "2024-01-01" {
+		t.Errorf("query = %v; want server knowledge and since date", q)
+	}
+}
+
+func TestListCategoriesAndAccounts(t *testing.T) {
+	srv := newServer(t)
+	srv.PutCategoryGroup(&ynab.CategoryGroup{Id: "g1", Name: "Ships", Categories: []*ynab.Category{
+		{Id: "c1", Name: "Fuel", Note: "Keep tanks full"},
+	}})
+	srv.PutAccount(&ynab.Account{Id: "a1", Name: "Checking", OnBudget: true, Balance: 1500000})
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	groups, err := client.ListCategories(ctx, ynab.LastUsedBudget, 0)
+	if err != nil {
+		t.Fatalf("ListCategories error: %v", err)
+	}
+	if len(groups.Items) != 1 || groups.Items[0].Categories[0].CategoryGroupId != "g1" {
+		t.Errorf("groups = %+v; want Ships with Fuel", groups.Items)
+	}
+	accounts, err := client.ListAccounts(ctx, "budget-1", 0)
+	if err != nil {
+		t.Fatalf("ListAccounts error: %v", err)
+	}
+	if len(accounts.Items) != 1 || accounts.Items[0].Balance.Money() != 15000000 {
+		t.Errorf("accounts = %+v; want checking with 1500.00", accounts.Items)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	srv := newServer(t)
+	ctx := context.Background()
+
+	_, err := ynab.NewClient("wrong", ynab.WithBaseURL(srv.URL)).ListBudgets(ctx)
+	if !errors.Is(err, ds.ErrUnauthorized) {
+		t.Errorf("err = %v; want unauthorized", err)
+	}
+	_, err = srv.NewClient().ListAccounts(ctx, "missing", 0)
+	if err == nil {
+		t.Error("expected error for unknown budget")
+	}
+	srv.Close()
+	if _, err = srv.NewClient().ListBudgets(ctx); !errors.Is(err, ds.ErrUpstreamUnavailable) {
+		t.Errorf("err = %v; want upstream unavailable", err)
+	}
+}
@@ -0,0 +1,58 @@
+package ynab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Transaction is a transaction of a budget. Split transactions have no category of their own; their amount is
+// divided among subtransactions instead.
+type Transaction struct {
+	Id                string            `json:"id"`
+	Date              string            `json:"date"`
+	Amount            Milliunits        `json:"amount"`
+	Memo              string            `json:"memo"`
+	Cleared           string            `json:"cleared"`
+	Approved          bool              `json:"approved"`
+	FlagColor         string            `json:"flag_color"`
+	AccountId         string            `json:"account_id"`
+	AccountName       string            `json:"account_name"`
+	PayeeId           string            `json:"payee_id"`
+	PayeeName         string            `json:"payee_name"`
+	CategoryId        string            `json:"category_id"`
+	CategoryName      string            `json:"category_name"`
+	TransferAccountId string            `json:"transfer_account_id"`
+	Deleted           bool              `json:"deleted"`
+	Subtransactions   []*Subtransaction `json:"subtransactions"`
+}
+
+// Subtransaction is a part of a split transaction.
+type Subtransaction struct {
+	Id                string     `json:"id"`
+	Amount            Milliunits `json:"amount"`
+	Memo              string     `json:"memo"`
+	PayeeName         string     `json:"payee_name"`
+	CategoryId        string     `json:"category_id"`
+	CategoryName      string     `json:"category_name"`
+	TransferAccountId string     `json:"transfer_account_id"`
+	Deleted           bool       `json:"deleted"`
+}
+
+// ListTransactions fetches the transactions of a budget dated on or after sinceDate, in "YYYY-MM-DD" format,
+// that changed since serverKnowledge.
+func (c *client) ListTransactions(ctx context.Context, budgetID, sinceDate string, serverKnowledge int64) (*Delta[*Transaction], error) {
+	var data struct {
+		Transactions    []*Transaction `json:"transactions"`
+		ServerKnowledge int64          `json:"server_knowledge"`
+	}
+	params := deltaParams(serverKnowledge)
+	if sinceDate != "" {
+		params.Set("since_date", sinceDate)
+	}
+	path := "/v1/budgets/" + url.PathEscape(budgetID) + "/transactions"
+	if err := c.get(ctx, path, params, &data); err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	return &Delta[*Transaction]{Items: data.Transactions, ServerKnowledge: data.ServerKnowledge}, nil
+}
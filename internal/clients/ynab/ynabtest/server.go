@@ -0,0 +1,195 @@
+// Package ynabtest provides a fake YNAB API server for tests. It serves a single budget from memory and
+// implements delta requests: every change increments the server knowledge, and list endpoints only return the
+// entities that changed since the server knowledge passed in the request.
+package ynabtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/clients/ynab"
+)
+
+// Server is a fake YNAB API server.
+type Server struct {
+	*httptest.Server
+	// Token is the access token the server accepts.
+	Token  string
+	Budget *ynab.Budget
+
+	mu           sync.Mutex
+	knowledge    int64
+	accounts     []*record[*ynab.Account]
+	groups       []*record[*ynab.CategoryGroup]
+	transactions []*record[*ynab.Transaction]
+	requests     []*http.Request
+}
+
+// record is an entity together with the server knowledge at which it last changed.
+type record[T any] struct {
+	knowledge int64
+	value     T
+}
+
+// NewServer starts a fake server that serves budget to clients authenticated with token. The budget can also
+// be referred to as ynab.LastUsedBudget. The caller must Close the server.
+func NewServer(token string, budget *ynab.Budget) *Server {
+	s := &Server{Token: token, Budget: budget}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/budgets", s.handleBudgets)
+	mux.HandleFunc("GET /v1/budgets/{budget}/accounts", s.handleAccounts)
+	mux.HandleFunc("GET /v1/budgets/{budget}/categories", s.handleCategories)
+	mux.HandleFunc("GET /v1/budgets/{budget}/transactions", s.handleTransactions)
+	s.Server = httptest.NewServer(s.authenticate(mux))
+	return s
+}
+
+// NewClient returns a client of the fake server authenticated with the accepted token.
+func (s *Server) NewClient() ynab.Client {
+	return ynab.NewClient(s.Token, ynab.WithBaseURL(s.URL))
+}
+
+// ServerKnowledge returns the current server knowledge.
+func (s *Server) ServerKnowledge() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.knowledge
+}
+
+// Requests returns the requests served so far.
+func (s *Server) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+// PutAccount adds or replaces an account, identified by its ID.
+func (s *Server) PutAccount(account *ynab.Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts = put(s.accounts, s.next(), account, func(a *ynab.Account) string { return a.Id })
+}
+
+// PutCategoryGroup adds or replaces a category group with its categories, identified by its ID.
+func (s *Server) PutCategoryGroup(group *ynab.CategoryGroup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, cat := range group.Categories {
+		cat.CategoryGroupId = group.Id
+	}
+	s.groups = put(s.groups, s.next(), group, func(g *ynab.CategoryGroup) string { return g.Id })
+}
+
+// PutTransaction adds or replaces a transaction, identified by its ID. Deleting a transaction is replacing it
+// with a copy that has Deleted set.
+func (s *Server) PutTransaction(txn *ynab.Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transactions = put(s.transactions, s.next(), txn, func(t *ynab.Transaction) string { return t.Id })
+}
+
+// next increments the server knowledge and returns it.
+func (s *Server) next() int64 {
+	s.knowledge++
+	return s.knowledge
+}
+
+// put replaces the record with the same ID as value, or appends a new one.
+func put[T any](records []*record[T], knowledge int64, value T, id func(T) string) []*record[T] {
+	for _, r := range records {
+		if id(r.value) == id(value) {
+			r.knowledge, r.value = knowledge, value
+			return records
+		}
+	}
+	return append(records, &record[T]{knowledge: knowledge, value: value})
+}
+
+// changed returns the values of records that changed after the server knowledge in the request and match keep.
+func changed[T any](req *http.Request, records []*record[T], keep func(T) bool) []T {
+	since, _ := strconv.ParseInt(req.URL.Query().Get("last_knowledge_of_server"), 10, 64)
+	result := make([]T, 0, len(records))
+	for _, r := range records {
+		if r.knowledge > since && keep(r.value) {
+			result = append(result, r.value)
+		}
+	}
+	return result
+}
+
+// authenticate rejects requests without the accepted token and records the others.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+s.Token {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "Unauthorized")
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// budget reports whether the request refers to the served budget, and writes an error response if not.
+func (s *Server) budget(w http.ResponseWriter, r *http.Request) bool {
+	if id := r.PathValue("budget"); id != s.Budget.Id && id != ynab.LastUsedBudget {
+		writeError(w, http.StatusNotFound, "resource_not_found", "Resource not found")
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleBudgets(w http.ResponseWriter, r *http.Request) {
+	writeData(w, map[string]any{"budgets": []*ynab.Budget{s.Budget}})
+}
+
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	if !s.budget(w, r) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	accounts := changed(r, s.accounts, func(*ynab.Account) bool { return true })
+	writeData(w, map[string]any{"accounts": accounts, "server_knowledge": s.knowledge})
+}
+
+func (s *Server) handleCategories(w http.ResponseWriter, r *http.Request) {
+	if !s.budget(w, r) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	groups := changed(r, s.groups, func(*ynab.CategoryGroup) bool { return true })
+	writeData(w, map[string]any{"category_groups": groups, "server_knowledge": s.knowledge})
+}
+
+func (s *Server) handleTransactions(w http.ResponseWriter, r *http.Request) {
+	if !s.budget(w, r) {
+		return
+	}
+	since := r.URL.Query().Get("since_date")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	transactions := changed(r, s.transactions, func(t *ynab.Transaction) bool {
+		// Dates in YYYY-MM-DD format compare like strings.
+		return since == "" || t.Date >= since
+	})
+	writeData(w, map[string]any{"transactions": transactions, "server_knowledge": s.knowledge})
+}
+
+func writeData(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+func writeError(w http.ResponseWriter, status int, name, detail string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]string{"id": strconv.Itoa(status), "name": name, "detail": detail},
+	})
+}
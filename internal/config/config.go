@@ -0,0 +1,693 @@
+// Package config loads and validates the server configuration from a YAML or TOML file,
+// with environment variables overriding values from the file.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/wyvernzora/personal-finance-mcp/internal/clients/ynab"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/csv"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/rules"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the complete server configuration.
+type Config struct {
+	// Server configures the HTTP listener.
+	Server ServerConfig `yaml:"server" toml:"server"`
+	// Timezone is the IANA name of the timezone used to interpret relative dates such as "last month".
+	Timezone string `yaml:"timezone" toml:"timezone"`
+	// BaseCurrency is the ISO 4217 code of the currency that amounts are reported in.
+	BaseCurrency string `yaml:"base_currency" toml:"base_currency"`
+	// DataSources configures the data sources and their credentials. Sources that are omitted are disabled.
+	DataSources DataSourcesConfig `yaml:"datasources" toml:"datasources"`
+	// Tools is the list of tools to enable. Empty enables every tool whose data source is configured.
+	Tools []string `yaml:"tools" toml:"tools"`
+	// Cache configures caching of data source results.
+	Cache CacheConfig `yaml:"cache" toml:"cache"`
+	// Responses configures limits on the size of tool responses.
+	Responses ResponsesConfig `yaml:"responses" toml:"responses"`
+	// Duplicates configures the detection of transactions that are likely reported twice.
+	Duplicates DuplicatesConfig `yaml:"duplicates" toml:"duplicates"`
+	// Resources configures MCP resources.
+	Resources ResourcesConfig `yaml:"resources" toml:"resources"`
+	// Prompts configures MCP prompts.
+	Prompts PromptsConfig `yaml:"prompts" toml:"prompts"`
+	// Secrets configures how secret references in credentials are resolved.
+	Secrets SecretsConfig `yaml:"secrets" toml:"secrets"`
+	// Logging configures the log output.
+	Logging LoggingConfig `yaml:"logging" toml:"logging"`
+	// Tracing configures OpenTelemetry tracing.
+	Tracing TracingConfig `yaml:"tracing" toml:"tracing"`
+	// Rules are payee rules applied to uncategorized transactions.
+	Rules []rules.Rule `yaml:"rules" toml:"rules"`
+
+	// Location is the loaded Timezone. It is populated by Validate.
+	Location *time.Location `yaml:"-" toml:"-"`
+	// CompiledRules are the compiled Rules. They are populated by Validate.
+	CompiledRules *rules.Rules `yaml:"-" toml:"-"`
+}
+
+// ServerConfig configures the HTTP listener.
+type ServerConfig struct {
+	BindAddress string `yaml:"bind_address" toml:"bind_address"`
+	Port        int    `yaml:"port" toml:"port"`
+	// ShutdownTimeout is how long in-flight tool calls may take to complete after SIGTERM.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// ReadinessTTL is how long the results of readiness checks are reused.
+	ReadinessTTL time.Duration `yaml:"readiness_ttl" toml:"readiness_ttl"`
+}
+
+// DataSourcesConfig holds the configuration of each supported data source.
+type DataSourcesConfig struct {
+	// OnFailure is what happens when one of several data sources of transactions or of assets and debts fails:
+	// fail fails the whole request, while partial returns the data of the others with a warning.
+	OnFailure string `yaml:"on_failure" toml:"on_failure"`
+
+	LunchMoney *LunchMoneyConfig `yaml:"lunch_money" toml:"lunch_money"`
+	Kubera     *KuberaConfig     `yaml:"kubera" toml:"kubera"`
+	OFX        *OFXConfig        `yaml:"ofx" toml:"ofx"`
+	CSV        *CSVConfig        `yaml:"csv" toml:"csv"`
+	Ledger     *LedgerConfig     `yaml:"ledger" toml:"ledger"`
+	YNAB       *YNABConfig       `yaml:"ynab" toml:"ynab"`
+	Firefly    *FireflyConfig    `yaml:"firefly" toml:"firefly"`
+	SimpleFIN  *SimpleFINConfig  `yaml:"simplefin" toml:"simplefin"`
+	Actual     *ActualConfig     `yaml:"actual" toml:"actual"`
+	GnuCash    *GnuCashConfig    `yaml:"gnucash" toml:"gnucash"`
+	Manual     *ManualConfig     `yaml:"manual" toml:"manual"`
+}
+
+// Names returns the names of the configured data sources, which are the keys of their configuration, in the order
+// their results are merged.
+func (c *DataSourcesConfig) Names() []string {
+	var names []string
+	for _, source := range []struct {
+		name       string
+		configured bool
+	}{
+		{"lunch_money", c.LunchMoney != nil},
+		{"kubera", c.Kubera != nil},
+		{"ofx", c.OFX != nil},
+		{"csv", c.CSV != nil},
+		{"ynab", c.YNAB != nil},
+		{"ledger", c.Ledger != nil},
+		{"firefly", c.Firefly != nil},
+		{"simplefin", c.SimpleFIN != nil},
+		{"actual", c.Actual != nil},
+		{"gnucash", c.GnuCash != nil},
+		{"manual", c.Manual != nil},
+	} {
+		if source.configured {
+			names = append(names, source.name)
+		}
+	}
+	return names
+}
+
+// LunchMoneyConfig holds the credentials of the Lunch Money data source. Credentials may be secret references,
+// see package secrets.
+type LunchMoneyConfig struct {
+	Token string `yaml:"token" toml:"token"`
+}
+
+// KuberaConfig holds the credentials of the Kubera data source. The API key and secret may be secret references,
+// see package secrets. The portfolio ID selects the default portfolio; every portfolio is combined if it is empty.
+type KuberaConfig struct {
+	APIKey      string `yaml:"api_key" toml:"api_key"`
+	APISecret   string `yaml:"api_secret" toml:"api_secret"`
+	PortfolioID string `yaml:"portfolio_id" toml:"portfolio_id"`
+}
+
+// OFXConfig configures the data source that reads OFX and QFX statement files.
+type OFXConfig struct {
+	// Dir is the directory that holds the statement files. Subdirectories are included.
+	Dir string `yaml:"dir" toml:"dir"`
+	// Categories are payee rules that assign transactions to categories. Transactions matching no rule are
+	// left uncategorized.
+	Categories []rules.Rule `yaml:"categories" toml:"categories"`
+
+	// CompiledCategories are the compiled Categories. They are populated by Validate.
+	CompiledCategories *rules.Rules `yaml:"-" toml:"-"`
+}
+
+// CSVConfig configures the data source that reads CSV exports.
+type CSVConfig struct {
+	// Dir is the directory that holds the exports. Subdirectories are included.
+	Dir string `yaml:"dir" toml:"dir"`
+	// Profiles describe the column layouts of exports. Each file is read with the first profile matching it.
+	Profiles []csv.Profile `yaml:"profiles" toml:"profiles"`
+	// Categories are payee rules that assign transactions without a category in the export to categories.
+	// Transactions matching no rule are left uncategorized.
+	Categories []rules.Rule `yaml:"categories" toml:"categories"`
+
+	// CompiledCategories are the compiled Categories. They are populated by Validate.
+	CompiledCategories *rules.Rules `yaml:"-" toml:"-"`
+}
+
+// LedgerConfig configures the data source that reads Beancount, ledger and hledger journals.
+type LedgerConfig struct {
+	// File is the journal. Files it includes are read as well.
+	File string `yaml:"file" toml:"file"`
+}
+
+// YNABConfig configures the YNAB data source. The token may be a secret reference, see package secrets.
+type YNABConfig struct {
+	Token string `yaml:"token" toml:"token"`
+	// BudgetID is the ID of the budget to read, or "last-used" for the budget that was last opened in YNAB.
+	BudgetID string `yaml:"budget_id" toml:"budget_id"`
+}
+
+// FireflyConfig configures the Firefly III data source. The token may be a secret reference, see package secrets.
+type FireflyConfig struct {
+	// URL is the base URL of the Firefly III instance, such as https://firefly.example.com.
+	URL   string `yaml:"url" toml:"url"`
+	Token string `yaml:"token" toml:"token"`
+}
+
+// SimpleFINConfig configures the data source that fetches bank accounts from a SimpleFIN server.
+type SimpleFINConfig struct {
+	// AccessURL is the access URL claimed with a setup token, which embeds the credentials. It may be a secret
+	// reference, see package secrets.
+	AccessURL string `yaml:"access_url" toml:"access_url"`
+	// Categories are payee rules that assign transactions to categories. Transactions matching no rule are
+	// left uncategorized.
+	Categories []rules.Rule `yaml:"categories" toml:"categories"`
+
+	// CompiledCategories are the compiled Categories. They are populated by Validate.
+	CompiledCategories *rules.Rules `yaml:"-" toml:"-"`
+}
+
+// ActualConfig configures the data source that reads Actual Budget files.
+type ActualConfig struct {
+	// File is the budget file, or the budget directory that holds it as db.sqlite.
+	File string `yaml:"file" toml:"file"`
+}
+
+// GnuCashConfig configures the data source that reads GnuCash books.
+type GnuCashConfig struct {
+	// File is the book, saved in the XML or the SQLite format.
+	File string `yaml:"file" toml:"file"`
+}
+
+// ManualConfig configures the data source that reads assets and debts kept by hand in a holdings file.
+type ManualConfig struct {
+	// File is the YAML holdings file.
+	File string `yaml:"file" toml:"file"`
+	// StaleAfter is the age after which the value of a holding is annotated as possibly out of date. It defaults
+	// to 90 days.
+	StaleAfter time.Duration `yaml:"stale_after" toml:"stale_after"`
+}
+
+// CacheConfig configures caching of data source results.
+type CacheConfig struct {
+	// TTL is how long results are reused; zero disables caching.
+	TTL time.Duration `yaml:"ttl" toml:"ttl"`
+}
+
+// ResponsesConfig limits the size of tool responses, so that they fit into the context window of the LLM.
+// Responses over either limit are cut down and paginated; zero disables a limit.
+type ResponsesConfig struct {
+	// MaxTransactions is the maximum number of transactions in a single response.
+	MaxTransactions int `yaml:"max_transactions" toml:"max_transactions"`
+	// MaxTokens is the approximate maximum number of tokens in a single response.
+	MaxTokens int `yaml:"max_tokens" toml:"max_tokens"`
+}
+
+// DuplicatesConfig configures the detection of transactions that are likely reported twice. Transactions of
+// different data sources are likely duplicates if their amounts are equal, their dates are at most MaxDays apart
+// and their payees are at least MinSimilarity similar. Transactions of the same data source are only duplicates if
+// their dates, amounts and payees are equal.
+type DuplicatesConfig struct {
+	// Ignore moves suspected duplicates to the ignored Duplicates category, so that they are not counted twice.
+	Ignore bool `yaml:"ignore" toml:"ignore"`
+	// MaxDays is the maximum number of days between the dates of duplicates.
+	MaxDays int `yaml:"max_days" toml:"max_days"`
+	// MinSimilarity is the minimum similarity of the payees of duplicates, between 0 and 1.
+	MinSimilarity float64 `yaml:"min_similarity" toml:"min_similarity"`
+}
+
+// ResourcesConfig configures MCP resources.
+type ResourcesConfig struct {
+	// RefreshInterval is how often resources are re-read to detect and notify changes.
+	RefreshInterval time.Duration `yaml:"refresh_interval" toml:"refresh_interval"`
+}
+
+// PromptsConfig configures MCP prompts.
+type PromptsConfig struct {
+	// Dir is the directory with prompt template overrides.
+	Dir string `yaml:"dir" toml:"dir"`
+}
+
+// SecretsConfig configures how secret references in credentials are resolved.
+type SecretsConfig struct {
+	// RefreshInterval is how often referenced secrets are re-read, so that rotated credentials take effect.
+	RefreshInterval time.Duration `yaml:"refresh_interval" toml:"refresh_interval"`
+}
+
+// LoggingConfig configures the log output.
+type LoggingConfig struct {
+	// Level is the minimum level of logged records: debug, info, warn or error.
+	Level string `yaml:"level" toml:"level"`
+	// Format is the format of log records: text or json.
+	Format string `yaml:"format" toml:"format"`
+}
+
+// TracingConfig configures OpenTelemetry tracing.
+type TracingConfig struct {
+	// Exporter is the destination of spans: none, stdout or otlp.
+	Exporter string `yaml:"exporter" toml:"exporter"`
+	// Endpoint is the OTLP/HTTP endpoint URL. Empty uses OTEL_EXPORTER_OTLP_ENDPOINT or the exporter default.
+	Endpoint string `yaml:"endpoint" toml:"endpoint"`
+	// SampleRatio is the fraction of new traces that are recorded, between 0 and 1.
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+// Default returns the configuration used when neither the configuration file nor the environment specify a value.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			BindAddress:     "0.0.0.0",
+			Port:            3000,
+			ShutdownTimeout: 30 * time.Second,
+			ReadinessTTL:    30 * time.Second,
+		},
+		Timezone:     "Local",
+		BaseCurrency: "USD",
+		DataSources: DataSourcesConfig{
+			OnFailure: string(ds.MergeFail),
+		},
+		Responses: ResponsesConfig{
+			MaxTransactions: 500,
+		},
+		Duplicates: DuplicatesConfig{
+			MaxDays:       3,
+			MinSimilarity: 0.8,
+		},
+		Resources: ResourcesConfig{
+			RefreshInterval: 15 * time.Minute,
+		},
+		Secrets: SecretsConfig{
+			RefreshInterval: 5 * time.Minute,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			SampleRatio: 1,
+		},
+	}
+}
+
+// Load reads the configuration file at path, if any, applies overrides from environment variables and
+// validates the result. The file format is determined by its extension: .yaml, .yml or .toml.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// readFile decodes the configuration file at path into the receiver. Unknown keys are rejected,
+// so that typos do not silently fall back to defaults.
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), c)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("failed to parse %s: unknown keys %v", path, undecoded)
+		}
+	default:
+		return fmt.Errorf("unsupported configuration file extension %q, expected .yaml, .yml or .toml", ext)
+	}
+	return nil
+}
+
+// applyEnv overrides configuration values with environment variables looked up via lookup.
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+	str := func(name string, dst *string) {
+		if val, ok := lookup(name); ok && val != "" {
+			*dst = val
+		}
+	}
+	duration := func(name string, dst *time.Duration) {
+		if val, ok := lookup(name); ok && val != "" {
+			d, err := time.ParseDuration(val)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("environment variable %s: %w", name, err))
+				return
+			}
+			*dst = d
+		}
+	}
+	integer := func(name string, dst *int) {
+		if val, ok := lookup(name); ok && val != "" {
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("environment variable %s: %w", name, err))
+				return
+			}
+			*dst = n
+		}
+	}
+	float := func(name string, dst *float64) {
+		if val, ok := lookup(name); ok && val != "" {
+			f, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("environment variable %s: %w", name, err))
+				return
+			}
+			*dst = f
+		}
+	}
+	boolean := func(name string, dst *bool) {
+		if val, ok := lookup(name); ok && val != "" {
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("environment variable %s: %w", name, err))
+				return
+			}
+			*dst = b
+		}
+	}
+
+	str("BIND_ADDRESS", &c.Server.BindAddress)
+	integer("PORT", &c.Server.Port)
+	duration("SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+	duration("READINESS_TTL", &c.Server.ReadinessTTL)
+	str("TIMEZONE", &c.Timezone)
+	str("BASE_CURRENCY", &c.BaseCurrency)
+	if val, ok := lookup("ENABLED_TOOLS"); ok && val != "" {
+		c.Tools = nil
+		for _, name := range strings.Split(val, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				c.Tools = append(c.Tools, name)
+			}
+		}
+	}
+	duration("CACHE_TTL", &c.Cache.TTL)
+	integer("RESPONSE_MAX_TRANSACTIONS", &c.Responses.MaxTransactions)
+	integer("RESPONSE_MAX_TOKENS", &c.Responses.MaxTokens)
+	boolean("DUPLICATES_IGNORE", &c.Duplicates.Ignore)
+	integer("DUPLICATES_MAX_DAYS", &c.Duplicates.MaxDays)
+	float("DUPLICATES_MIN_SIMILARITY", &c.Duplicates.MinSimilarity)
+	duration("RESOURCE_REFRESH_INTERVAL", &c.Resources.RefreshInterval)
+	str("PROMPTS_DIR", &c.Prompts.Dir)
+	duration("SECRETS_REFRESH_INTERVAL", &c.Secrets.RefreshInterval)
+	str("LOG_LEVEL", &c.Logging.Level)
+	str("LOG_FORMAT", &c.Logging.Format)
+	str("TRACING_EXPORTER", &c.Tracing.Exporter)
+	str("TRACING_ENDPOINT", &c.Tracing.Endpoint)
+	float("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio)
+
+	str("DATASOURCES_ON_FAILURE", &c.DataSources.OnFailure)
+	if val, ok := lookup("LUNCHMONEY_TOKEN"); ok && val != "" {
+		if c.DataSources.LunchMoney == nil {
+			c.DataSources.LunchMoney = &LunchMoneyConfig{}
+		}
+		c.DataSources.LunchMoney.Token = val
+	}
+	for _, name := range []string{"KUBERA_API_KEY", "KUBERA_API_SECRET", "KUBERA_PORTFOLIO_ID"} {
+		if val, ok := lookup(name); ok && val != "" && c.DataSources.Kubera == nil {
+			c.DataSources.Kubera = &KuberaConfig{}
+		}
+	}
+	if kb := c.DataSources.Kubera; kb != nil {
+		str("KUBERA_API_KEY", &kb.APIKey)
+		str("KUBERA_API_SECRET", &kb.APISecret)
+		str("KUBERA_PORTFOLIO_ID", &kb.PortfolioID)
+	}
+	if val, ok := lookup("OFX_DIR"); ok && val != "" {
+		if c.DataSources.OFX == nil {
+			c.DataSources.OFX = &OFXConfig{}
+		}
+		c.DataSources.OFX.Dir = val
+	}
+	if val, ok := lookup("CSV_DIR"); ok && val != "" {
+		if c.DataSources.CSV == nil {
+			c.DataSources.CSV = &CSVConfig{}
+		}
+		c.DataSources.CSV.Dir = val
+	}
+	for _, name := range []string{"YNAB_TOKEN", "YNAB_BUDGET_ID"} {
+		if val, ok := lookup(name); ok && val != "" && c.DataSources.YNAB == nil {
+			c.DataSources.YNAB = &YNABConfig{}
+		}
+	}
+	if yn := c.DataSources.YNAB; yn != nil {
+		str("YNAB_TOKEN", &yn.Token)
+		str("YNAB_BUDGET_ID", &yn.BudgetID)
+	}
+	for _, name := range []string{"FIREFLY_URL", "FIREFLY_TOKEN"} {
+		if val, ok := lookup(name); ok && val != "" && c.DataSources.Firefly == nil {
+			c.DataSources.Firefly = &FireflyConfig{}
+		}
+	}
+	if ff := c.DataSources.Firefly; ff != nil {
+		str("FIREFLY_URL", &ff.URL)
+		str("FIREFLY_TOKEN", &ff.Token)
+	}
+	if val, ok := lookup("SIMPLEFIN_ACCESS_URL"); ok && val != "" {
+		if c.DataSources.SimpleFIN == nil {
+			c.DataSources.SimpleFIN = &SimpleFINConfig{}
+		}
+		c.DataSources.SimpleFIN.AccessURL = val
+	}
+	if val, ok := lookup("LEDGER_FILE"); ok && val != "" {
+		if c.DataSources.Ledger == nil {
+			c.DataSources.Ledger = &LedgerConfig{}
+		}
+		c.DataSources.Ledger.File = val
+	}
+	if val, ok := lookup("ACTUAL_FILE"); ok && val != "" {
+		if c.DataSources.Actual == nil {
+			c.DataSources.Actual = &ActualConfig{}
+		}
+		c.DataSources.Actual.File = val
+	}
+	if val, ok := lookup("GNUCASH_FILE"); ok && val != "" {
+		if c.DataSources.GnuCash == nil {
+			c.DataSources.GnuCash = &GnuCashConfig{}
+		}
+		c.DataSources.GnuCash.File = val
+	}
+	if val, ok := lookup("MANUAL_HOLDINGS_FILE"); ok && val != "" {
+		if c.DataSources.Manual == nil {
+			c.DataSources.Manual = &ManualConfig{}
+		}
+		c.DataSources.Manual.File = val
+	}
+
+	return errors.Join(errs...)
+}
+
+// currencyPattern matches ISO 4217 currency codes.
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Validate checks the configuration for errors, reporting all of them at once, and populates derived fields.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if c.Server.BindAddress == "" {
+		fail("server.bind_address", "must be set")
+	}
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		fail("server.port", "must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		fail("server.shutdown_timeout", "must be positive")
+	}
+	if c.Server.ReadinessTTL <= 0 {
+		fail("server.readiness_ttl", "must be positive")
+	}
+
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		fail("timezone", "unknown timezone %q", c.Timezone)
+	}
+	c.Location = loc
+
+	if !currencyPattern.MatchString(c.BaseCurrency) {
+		fail("base_currency", "must be a three-letter ISO 4217 code such as USD, got %q", c.BaseCurrency)
+	}
+
+	if lm := c.DataSources.LunchMoney; lm != nil && lm.Token == "" {
+		fail("datasources.lunch_money.token", "must be set")
+	}
+	if kb := c.DataSources.Kubera; kb != nil {
+		if kb.APIKey == "" {
+			fail("datasources.kubera.api_key", "must be set")
+		}
+		if kb.APISecret == "" {
+			fail("datasources.kubera.api_secret", "must be set")
+		}
+	}
+	if ofx := c.DataSources.OFX; ofx != nil {
+		if ofx.Dir == "" {
+			fail("datasources.ofx.dir", "must be set")
+		}
+		compiled, err := rules.Compile(ofx.Categories)
+		if err != nil {
+			fail("datasources.ofx.categories", "%v", err)
+		}
+		ofx.CompiledCategories = compiled
+	}
+	if cs := c.DataSources.CSV; cs != nil {
+		if cs.Dir == "" {
+			fail("datasources.csv.dir", "must be set")
+		}
+		if len(cs.Profiles) == 0 {
+			fail("datasources.csv.profiles", "at least one profile must be configured")
+		}
+		for i := range cs.Profiles {
+			// Report each error of a profile separately, prefixed with the path of the profile.
+			if err, ok := cs.Profiles[i].Validate().(interface{ Unwrap() []error }); ok {
+				for _, err := range err.Unwrap() {
+					errs = append(errs, fmt.Errorf("datasources.csv.profiles[%d].%w", i, err))
+				}
+			}
+		}
+		compiled, err := rules.Compile(cs.Categories)
+		if err != nil {
+			fail("datasources.csv.categories", "%v", err)
+		}
+		cs.CompiledCategories = compiled
+	}
+	if lg := c.DataSources.Ledger; lg != nil && lg.File == "" {
+		fail("datasources.ledger.file", "must be set")
+	}
+	if yn := c.DataSources.YNAB; yn != nil {
+		if yn.Token == "" {
+			fail("datasources.ynab.token", "must be set")
+		}
+		if yn.BudgetID == "" {
+			yn.BudgetID = ynab.LastUsedBudget
+		}
+	}
+
+	if ff := c.DataSources.Firefly; ff != nil {
+		if ff.URL == "" {
+			fail("datasources.firefly.url", "must be set")
+		}
+		if ff.Token == "" {
+			fail("datasources.firefly.token", "must be set")
+		}
+	}
+	if sf := c.DataSources.SimpleFIN; sf != nil {
+		if sf.AccessURL == "" {
+			fail("datasources.simplefin.access_url", "must be set")
+		}
+		compiled, err := rules.Compile(sf.Categories)
+		if err != nil {
+			fail("datasources.simplefin.categories", "%v", err)
+		}
+		sf.CompiledCategories = compiled
+	}
+	if ac := c.DataSources.Actual; ac != nil && ac.File == "" {
+		fail("datasources.actual.file", "must be set")
+	}
+	if gc := c.DataSources.GnuCash; gc != nil && gc.File == "" {
+		fail("datasources.gnucash.file", "must be set")
+	}
+	if mn := c.DataSources.Manual; mn != nil {
+		if mn.File == "" {
+			fail("datasources.manual.file", "must be set")
+		}
+		if mn.StaleAfter < 0 {
+			fail("datasources.manual.stale_after", "must not be negative")
+		}
+		if mn.StaleAfter == 0 {
+			mn.StaleAfter = 90 * 24 * time.Hour
+		}
+	}
+
+	if _, err := ds.ParseMergePolicy(c.DataSources.OnFailure); err != nil {
+		fail("datasources.on_failure", "%v", err)
+	}
+	if len(c.DataSources.Names()) == 0 {
+		fail("datasources", "at least one data source must be configured")
+	}
+
+	if c.Cache.TTL < 0 {
+		fail("cache.ttl", "must not be negative")
+	}
+	if c.Responses.MaxTransactions < 0 {
+		fail("responses.max_transactions", "must not be negative")
+	}
+	if c.Responses.MaxTokens < 0 {
+		fail("responses.max_tokens", "must not be negative")
+	}
+	if c.Duplicates.MaxDays < 0 {
+		fail("duplicates.max_days", "must not be negative")
+	}
+	if c.Duplicates.MinSimilarity <= 0 || c.Duplicates.MinSimilarity > 1 {
+		fail("duplicates.min_similarity", "must be greater than 0 and at most 1, got %v", c.Duplicates.MinSimilarity)
+	}
+	if c.Resources.RefreshInterval <= 0 {
+		fail("resources.refresh_interval", "must be positive")
+	}
+	if c.Secrets.RefreshInterval <= 0 {
+		fail("secrets.refresh_interval", "must be positive")
+	}
+	if _, err := logging.ParseLevel(c.Logging.Level); err != nil {
+		fail("logging.level", "%v", err)
+	}
+	if _, err := logging.ParseFormat(c.Logging.Format); err != nil {
+		fail("logging.format", "%v", err)
+	}
+	if _, err := tracing.ParseExporter(c.Tracing.Exporter); err != nil {
+		fail("tracing.exporter", "%v", err)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+
+	compiled, err := rules.Compile(c.Rules)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	c.CompiledRules = compiled
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,520 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/datasource/csv"
+)
+
+// envVars lists every environment variable that Load consults.
+var envVars = []string{
+	"BIND_ADDRESS", "PORT", "SHUTDOWN_TIMEOUT", "READINESS_TTL", "TIMEZONE", "BASE_CURRENCY", "ENABLED_TOOLS", "CACHE_TTL",
+	"RESPONSE_MAX_TRANSACTIONS", "RESPONSE_MAX_TOKENS",
+	"DUPLICATES_IGNORE", "DUPLICATES_MAX_DAYS", "DUPLICATES_MIN_SIMILARITY",
+	"RESOURCE_REFRESH_INTERVAL", "PROMPTS_DIR", "SECRETS_REFRESH_INTERVAL", "LOG_LEVEL", "LOG_FORMAT",
+	"TRACING_EXPORTER", "TRACING_ENDPOINT", "TRACING_SAMPLE_RATIO",
+	"LUNCHMONEY_TOKEN",
+	"KUBERA_API_KEY", "KUBERA_API_SECRET", "KUBERA_PORTFOLIO_ID",
+	"OFX_DIR", "CSV_DIR", "LEDGER_FILE",
+	"YNAB_TOKEN", "YNAB_BUDGET_ID", "FIREFLY_URL", "FIREFLY_TOKEN",
+	"SIMPLEFIN_ACCESS_URL", "ACTUAL_FILE", "GNUCASH_FILE", "MANUAL_HOLDINGS_FILE",
+	"DATASOURCES_ON_FAILURE",
+}
+
+// clearEnv blanks out all configuration environment variables for the duration of the test.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range envVars {
+		t.Setenv(name, "")
+	}
+}
+
+// writeFile writes content into a file with the given name in a temporary directory and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_YAML(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+server:
+  port: 8080
+timezone: America/Los_Angeles
+base_currency: EUR
+datasources:
+  lunch_money:
+    token: lm-token
+  kubera:
+    api_key: key
+    api_secret: secret
+    portfolio_id: pid
+tools: [get_net_worth_summary]
+cache:
+  ttl: 5m
+rules:
+  - payee: netflix
+    category: Subscriptions/Streaming
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if cfg.Server.Port != 8080 || cfg.Server.BindAddress != "0.0.0.0" || cfg.Server.ShutdownTimeout != 30*time.Second {
+		t.Errorf("Server = %+v; want port 8080 and default bind address and shutdown timeout", cfg.Server)
+	}
+	if cfg.Location == nil || cfg.Location.String() != "America/Los_Angeles" {
+		t.Errorf("Location = %v; want America/Los_Angeles", cfg.Location)
+	}
+	if cfg.DataSources.LunchMoney.Token != "lm-token" || cfg.DataSources.Kubera.PortfolioID != "pid" {
+		t.Errorf("DataSources = %+v; want values from file", cfg.DataSources)
+	}
+	if cfg.Cache.TTL != 5*time.Minute {
+		t.Errorf("Cache.TTL = %v; want 5m", cfg.Cache.TTL)
+	}
+	if cfg.CompiledRules.Len() != 1 {
+		t.Errorf("CompiledRules.Len() = %d; want 1", cfg.CompiledRules.Len())
+	}
+}
+
+func TestLoad_TOML(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.toml", `
+base_currency = "GBP"
+
+[datasources.lunch_money]
+token = "lm-token"
+
+[resources]
+refresh_interval = "1h"
+
+[[rules]]
+payee = "tesco"
+category = "Food"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if cfg.BaseCurrency != "GBP" || cfg.Resources.RefreshInterval != time.Hour {
+		t.Errorf("cfg = %+v; want values from file", cfg)
+	}
+	if cfg.DataSources.Kubera != nil {
+		t.Errorf("Kubera = %+v; want nil when not configured", cfg.DataSources.Kubera)
+	}
+}
+
+func TestLoad_RejectsUnknownKeys(t *testing.T) {
+	clearEnv(t)
+	for name, content := range map[string]string{
+		"config.yaml": "datasources:\n  lunch_money:\n    tokne: typo\n",
+		"config.toml": "[datasources.lunch_money]\ntokne = \"typo\"\n",
+	} {
+		if _, err := Load(writeFile(t, name, content)); err == nil || !strings.Contains(err.Error(), "tokne") {
+			t.Errorf("%s: err = %v; want error mentioning unknown key", name, err)
+		}
+	}
+}
+
+func TestLoad_EnvironmentOverridesFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("PORT", "9090")
+	t.Setenv("LUNCHMONEY_TOKEN", "env-token")
+	t.Setenv("ENABLED_TOOLS", "get_categorized_summaries, get_categorized_transactions")
+	path := writeFile(t, "config.yml", "server:\n  port: 8080\ndatasources:\n  lunch_money:\n    token: file-token\n")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Port = %d; want 9090 from environment", cfg.Server.Port)
+	}
+	if cfg.DataSources.LunchMoney.Token != "env-token" {
+		t.Errorf("Token = %q; want env-token", cfg.DataSources.LunchMoney.Token)
+	}
+	if len(cfg.Tools) != 2 || cfg.Tools[1] != "get_categorized_transactions" {
+		t.Errorf("Tools = %v; want two tools from environment", cfg.Tools)
+	}
+}
+
+func TestLoad_EnvironmentOnly(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("KUBERA_API_KEY", "key")
+	t.Setenv("KUBERA_API_SECRET", "secret")
+	t.Setenv("KUBERA_PORTFOLIO_ID", "pid")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if cfg.DataSources.Kubera == nil || cfg.DataSources.Kubera.APISecret != "secret" {
+		t.Errorf("Kubera = %+v; want credentials from environment", cfg.DataSources.Kubera)
+	}
+	if cfg.DataSources.LunchMoney != nil {
+		t.Errorf("LunchMoney = %+v; want nil", cfg.DataSources.LunchMoney)
+	}
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	cfg := Default()
+	cfg.Server.Port = 0
+	cfg.Timezone = "Mars/Olympus_Mons"
+	cfg.BaseCurrency = "dollars"
+	cfg.DataSources.Kubera = &KuberaConfig{APIKey: "key"}
+	cfg.Cache.TTL = -time.Second
+	cfg.Responses.MaxTokens = -1
+	cfg.Logging.Format = "xml"
+	cfg.Tracing.SampleRatio = 2
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{
+		"server.port",
+		"timezone",
+		"base_currency",
+		"datasources.kubera.api_secret",
+		"cache.ttl",
+		"responses.max_tokens",
+		"logging.format",
+		"tracing.sample_ratio",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "datasources.kubera.portfolio_id") {
+		t.Errorf("error mentions the optional Kubera portfolio ID:\n%v", err)
+	}
+}
+
+func TestValidate_RequiresDataSource(t *testing.T) {
+	err := Default().Validate()
+	if err == nil || !strings.Contains(err.Error(), "at least one data source") {
+		t.Errorf("err = %v; want missing data source error", err)
+	}
+}
+
+func TestApplyEnv_InvalidValues(t *testing.T) {
+	cfg := Default()
+	env := map[string]string{
+		"PORT": "http", "CACHE_TTL": "forever", "RESPONSE_MAX_TOKENS": "lots", "DUPLICATES_IGNORE": "maybe",
+		"DUPLICATES_MIN_SIMILARITY": "high", "TRACING_SAMPLE_RATIO": "half",
+	}
+	err := cfg.applyEnv(func(name string) (string, bool) {
+		val, ok := env[name]
+		return val, ok
+	})
+	for name := range env {
+		if err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("err = %v; want an error for %s", err, name)
+		}
+	}
+	// Invalid values are reported without replacing the configured ones.
+	if want := Default(); cfg.Server.Port != want.Server.Port || cfg.Cache.TTL != want.Cache.TTL ||
+		cfg.Responses.MaxTokens != want.Responses.MaxTokens || cfg.Duplicates != want.Duplicates ||
+		cfg.Tracing.SampleRatio != want.Tracing.SampleRatio {
+		t.Errorf("cfg = %+v; want the defaults kept", cfg)
+	}
+}
+
+func TestLoad_OFX(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+datasources:
+  ofx:
+    dir: /srv/statements
+    categories:
+      - payee: netflix
+        category: Subscriptions/Streaming
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if cfg.DataSources.OFX.Dir != "/srv/statements" || cfg.DataSources.OFX.CompiledCategories.Len() != 1 {
+		t.Errorf("OFX = %+v; want directory and one compiled category rule", cfg.DataSources.OFX)
+	}
+}
+
+func TestLoad_CSV(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.toml", `
+[datasources.csv]
+dir = "/srv/exports"
+
+[[datasources.csv.profiles]]
+name = "bank"
+files = "bank/*.csv"
+date_column = "Date"
+date_format = "01/02/2006"
+amount_column = "Amount"
+payee_column = "Description"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	cs := cfg.DataSources.CSV
+	if cs.Dir != "/srv/exports" || len(cs.Profiles) != 1 || cs.Profiles[0].AmountSign != csv.ExpensesNegative {
+		t.Errorf("CSV = %+v; want directory and one profile with defaults filled in", cs)
+	}
+
+	t.Setenv("OFX_DIR", "/srv/statements")
+	if cfg, err = Load(path); err != nil || cfg.DataSources.OFX == nil || cfg.DataSources.CSV == nil {
+		t.Errorf("DataSources = %+v, %v; want OFX alongside CSV", cfg.DataSources, err)
+	}
+}
+
+func TestValidate_CSVProfiles(t *testing.T) {
+	cfg := Default()
+	cfg.DataSources.CSV = &CSVConfig{Dir: "/srv/exports", Profiles: []csv.Profile{{Name: "bank", Files: "*.csv"}}}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, field := range []string{"datasources.csv.profiles[0].date_column", "datasources.csv.profiles[0].payee_column"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("error %q does not mention %s", err, field)
+		}
+	}
+}
+
+func TestLoad_Ledger(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("LEDGER_FILE", "/srv/books/main.beancount")
+	path := writeFile(t, "config.yaml", "timezone: UTC\n")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if cfg.DataSources.Ledger.File != "/srv/books/main.beancount" {
+		t.Errorf("Ledger = %+v; want journal from environment", cfg.DataSources.Ledger)
+	}
+}
+
+func TestLoad_YNAB(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("YNAB_TOKEN", "file:///run/secrets/ynab_token")
+	path := writeFile(t, "config.yaml", "timezone: UTC\n")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if yn := cfg.DataSources.YNAB; yn.Token != "file:///run/secrets/ynab_token" || yn.BudgetID != "last-used" {
+		t.Errorf("YNAB = %+v; want token from environment and the last used budget", yn)
+	}
+
+	t.Setenv("YNAB_TOKEN", "")
+	t.Setenv("YNAB_BUDGET_ID", "budget-1")
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "datasources.ynab.token") {
+		t.Errorf("err = %v; want error for missing token", err)
+	}
+}
+
+func TestLoad_Firefly(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.toml", `
+timezone = "UTC"
+
+[datasources.firefly]
+url = "https://firefly.example.com"
+`)
+	t.Setenv("FIREFLY_TOKEN", "ff-token")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if ff := cfg.DataSources.Firefly; ff.URL != "https://firefly.example.com" || ff.Token != "ff-token" {
+		t.Errorf("Firefly = %+v; want URL from file and token from environment", ff)
+	}
+}
+
+func TestLoad_SimpleFIN(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+timezone: UTC
+datasources:
+  simplefin:
+    categories:
+      - payee: "whole ?foods"
+        category: Food/Groceries
+`)
+	t.Setenv("SIMPLEFIN_ACCESS_URL", "file:///run/secrets/simplefin_access_url")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	sf := cfg.DataSources.SimpleFIN
+	if sf.AccessURL != "file:///run/secrets/simplefin_access_url" || sf.CompiledCategories.Len() != 1 {
+		t.Errorf("SimpleFIN = %+v; want access URL from environment and compiled rules", sf)
+	}
+
+	t.Setenv("SIMPLEFIN_ACCESS_URL", "")
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "datasources.simplefin.access_url") {
+		t.Errorf("err = %v; want error for missing access URL", err)
+	}
+}
+
+func TestLoad_Actual(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("ACTUAL_FILE", "/srv/actual/My-Finances-1a2b3c4")
+	path := writeFile(t, "config.yaml", "timezone: UTC\n")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if cfg.DataSources.Actual.File != "/srv/actual/My-Finances-1a2b3c4" {
+		t.Errorf("Actual = %+v; want budget from environment", cfg.DataSources.Actual)
+	}
+}
+
+func TestLoad_GnuCash(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+timezone: UTC
+datasources:
+  gnucash:
+    file: /srv/books/household.gnucash
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if cfg.DataSources.GnuCash.File != "/srv/books/household.gnucash" {
+		t.Errorf("GnuCash = %+v; want book from file", cfg.DataSources.GnuCash)
+	}
+
+	t.Setenv("GNUCASH_FILE", "/srv/books/household.sqlite.gnucash")
+	if cfg, err = Load(path); err != nil || cfg.DataSources.GnuCash.File != "/srv/books/household.sqlite.gnucash" {
+		t.Errorf("GnuCash = %+v, %v; want book from environment", cfg.DataSources.GnuCash, err)
+	}
+}
+
+func TestLoad_Manual(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+timezone: UTC
+datasources:
+  kubera:
+    api_key: key
+    api_secret: secret
+    portfolio_id: pid
+  manual:
+    file: /srv/finance/holdings.yaml
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if mn := cfg.DataSources.Manual; mn.File != "/srv/finance/holdings.yaml" || mn.StaleAfter != 90*24*time.Hour {
+		t.Errorf("Manual = %+v; want holdings file alongside Kubera with the default staleness", mn)
+	}
+
+	t.Setenv("MANUAL_HOLDINGS_FILE", "/srv/finance/other.yaml")
+	if cfg, err = Load(path); err != nil || cfg.DataSources.Manual.File != "/srv/finance/other.yaml" {
+		t.Errorf("Manual = %+v, %v; want holdings file from environment", cfg.DataSources.Manual, err)
+	}
+
+	t.Setenv("MANUAL_HOLDINGS_FILE", "")
+	path = writeFile(t, "config.yaml", `
+timezone: UTC
+datasources:
+  manual:
+    stale_after: -1h
+`)
+	_, err = Load(path)
+	for _, want := range []string{"datasources.manual.file", "datasources.manual.stale_after"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v; want error for %s", err, want)
+		}
+	}
+}
+
+func TestLoad_OnFailure(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+timezone: UTC
+datasources:
+  ledger:
+    file: /srv/books/main.beancount
+  gnucash:
+    file: /srv/books/household.gnucash
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if cfg.DataSources.OnFailure != "fail" {
+		t.Errorf("OnFailure = %q; want fail by default", cfg.DataSources.OnFailure)
+	}
+
+	t.Setenv("DATASOURCES_ON_FAILURE", "partial")
+	if cfg, err = Load(path); err != nil || cfg.DataSources.OnFailure != "partial" {
+		t.Errorf("OnFailure = %q, %v; want partial from environment", cfg.DataSources.OnFailure, err)
+	}
+
+	t.Setenv("DATASOURCES_ON_FAILURE", "ignore")
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "datasources.on_failure") {
+		t.Errorf("err = %v; want error for unknown policy", err)
+	}
+}
+
+func TestLoad_Duplicates(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.toml", `
+timezone = "UTC"
+
+[datasources.ledger]
+file = "/srv/books/main.beancount"
+
+[duplicates]
+ignore = true
+max_days = 5
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if d := cfg.Duplicates; !d.Ignore || d.MaxDays != 5 || d.MinSimilarity != 0.8 {
+		t.Errorf("Duplicates = %+v; want values from file with the default similarity", d)
+	}
+
+	t.Setenv("DUPLICATES_IGNORE", "false")
+	t.Setenv("DUPLICATES_MIN_SIMILARITY", "0.9")
+	if cfg, err = Load(path); err != nil || cfg.Duplicates.Ignore || cfg.Duplicates.MinSimilarity != 0.9 {
+		t.Errorf("Duplicates = %+v, %v; want values from environment", cfg.Duplicates, err)
+	}
+
+	t.Setenv("DUPLICATES_MAX_DAYS", "-1")
+	t.Setenv("DUPLICATES_MIN_SIMILARITY", "1.5")
+	_, err = Load(path)
+	for _, want := range []string{"duplicates.max_days", "duplicates.min_similarity"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v; want error for %s", err, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+)
+
+// Watch reloads the configuration from path whenever the process receives SIGHUP or the file changes, and passes
+// every successfully loaded configuration to apply. Configurations that fail to load or validate are logged and
+// ignored, leaving the previous configuration in effect. The file is checked for changes every pollInterval.
+// Watch blocks until ctx is cancelled.
+func Watch(ctx context.Context, path string, pollInterval time.Duration, apply func(*Config)) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	logger := logging.FromContext(ctx)
+	last := stat(path)
+	reload := func(reason string) {
+		cfg, err := Load(path)
+		if err != nil {
+			logger.ErrorContext(ctx, "Configuration not reloaded", "reason", reason, "error", err)
+			return
+		}
+		logger.InfoContext(ctx, "Configuration reloaded", "reason", reason)
+		apply(cfg)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			last = stat(path)
+			reload("SIGHUP")
+		case <-ticker.C:
+			if path == "" {
+				continue
+			}
+			if current := stat(path); current != last {
+				last = current
+				reload("file change")
+			}
+		}
+	}
+}
+
+// fileState captures the attributes of a file that indicate it has changed.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// stat returns the current state of the file at path, or the zero state if it cannot be accessed.
+func stat(path string) fileState {
+	if path == "" {
+		return fileState{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{modTime: info.ModTime(), size: info.Size()}
+}
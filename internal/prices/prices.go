@@ -0,0 +1,93 @@
+// Package prices records the prices of commodities, such as currencies and securities, over time and converts
+// quantities between commodities with them. Commodities are identified by strings, such as USD or VTI, which data
+// sources choose so that they are unique within their prices.
+package prices
+
+import (
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// point is the price of a commodity on a date.
+type point struct {
+	date  types.Date
+	value *big.Rat
+}
+
+// pair identifies the prices of a commodity quoted in another commodity.
+type pair struct {
+	commodity, quote string
+}
+
+// DB holds the prices of commodities, ordered by date once Sort has been called.
+type DB map[pair][]point
+
+// Add records the price of a unit of commodity in quote on date. Prices of a commodity in itself and zero prices
+// are ignored.
+func (db DB) Add(date types.Date, commodity, quote string, value *big.Rat) {
+	if commodity == quote || value.Sign() == 0 {
+		return
+	}
+	p := pair{commodity: commodity, quote: quote}
+	db[p] = append(db[p], point{date: date, value: value})
+}
+
+// Sort orders the prices of every pair by date. The last of several prices recorded on the same date wins.
+func (db DB) Sort() {
+	for _, points := range db {
+		slices.SortStableFunc(points, func(a, b point) int {
+			return time.Time(a.date).Compare(time.Time(b.date))
+		})
+	}
+}
+
+// Lookup returns the price of commodity in quote closest to date: the latest one recorded on or before date,
+// or the earliest one if all were recorded later.
+func (db DB) Lookup(commodity, quote string, date types.Date) (*big.Rat, bool) {
+	points := db[pair{commodity: commodity, quote: quote}]
+	if len(points) == 0 {
+		return nil, false
+	}
+	i, _ := slices.BinarySearchFunc(points, date, func(p point, d types.Date) int {
+		if d.Before(p.date) {
+			return 1
+		}
+		return -1
+	})
+	if i == 0 {
+		return points[0].value, true
+	}
+	return points[i-1].value, true
+}
+
+// Convert returns the value of a quantity of commodity in the target commodity on date, using either a price of
+// the commodity in target or the inverse of a price of target in the commodity.
+func (db DB) Convert(quantity *big.Rat, commodity, target string, date types.Date) (*big.Rat, bool) {
+	if commodity == target {
+		return quantity, true
+	}
+	if price, ok := db.Lookup(commodity, target, date); ok {
+		return new(big.Rat).Mul(quantity, price), true
+	}
+	if price, ok := db.Lookup(target, commodity, date); ok {
+		return new(big.Rat).Quo(quantity, price), true
+	}
+	return nil, false
+}
+
+// ToMoney rounds a quantity to the precision of types.Money.
+func ToMoney(r *big.Rat) types.Money {
+	f, _ := new(big.Rat).Mul(r, big.NewRat(types.FACTOR, 1)).Float64()
+	return types.Money(math.Round(f))
+}
+
+// Format formats a quantity of a commodity for annotations, such as 10.5 VTI.
+func Format(quantity *big.Rat, commodity string) string {
+	s := strings.TrimSuffix(strings.TrimRight(quantity.FloatString(8), "0"), ".")
+	return s + " " + commodity
+}
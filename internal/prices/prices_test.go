@@ -0,0 +1,71 @@
+package prices
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func mustDate(t *testing.T, s string) types.Date {
+	t.Helper()
+	d, err := types.ParseDate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestConvert(t *testing.T) {
+	db := make(DB)
+	db.Add(mustDate(t, "2024-02-01"), "VTI", "USD", big.NewRat(220, 1))
+	db.Add(mustDate(t, "2024-01-01"), "VTI", "USD", big.NewRat(200, 1))
+	db.Add(mustDate(t, "2024-01-01"), "USD", "EUR", big.NewRat(9, 10))
+	db.Add(mustDate(t, "2024-01-01"), "BTC", "USD", new(big.Rat))
+	db.Add(mustDate(t, "2024-01-01"), "USD", "USD", big.NewRat(2, 1))
+	db.Sort()
+
+	for _, tc := range []struct {
+		name              string
+		commodity, target string
+		date              string
+		want              *big.Rat
+	}{
+		{"same commodity", "USD", "USD", "2024-01-15", big.NewRat(10, 1)},
+		{"latest before", "VTI", "USD", "2024-01-31", big.NewRat(2000, 1)},
+		{"on the date", "VTI", "USD", "2024-02-01", big.NewRat(2200, 1)},
+		{"before the first", "VTI", "USD", "2023-06-01", big.NewRat(2000, 1)},
+		{"inverse", "EUR", "USD", "2024-01-15", big.NewRat(100, 9)},
+		{"zero price ignored", "BTC", "USD", "2024-01-15", nil},
+		{"unknown", "VTI", "EUR", "2024-01-15", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := db.Convert(big.NewRat(10, 1), tc.commodity, tc.target, mustDate(t, tc.date))
+			if tc.want == nil {
+				if ok {
+					t.Errorf("Convert = %v; want no price", got)
+				}
+				return
+			}
+			if !ok || got.Cmp(tc.want) != 0 {
+				t.Errorf("Convert = %v, %v; want %v", got, ok, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for quantity, want := range map[*big.Rat]string{
+		big.NewRat(21, 2):        "10.5 VTI",
+		big.NewRat(-3, 1):        "-3 VTI",
+		big.NewRat(1, 3):         "0.33333333 VTI",
+		big.NewRat(1, 100000000): "0.00000001 VTI",
+	} {
+		if got := Format(quantity, "VTI"); got != want {
+			t.Errorf("Format(%v) = %q; want %q", quantity, got, want)
+		}
+	}
+	if got := ToMoney(big.NewRat(123456789, 100000)); got != 12345679 {
+		t.Errorf("ToMoney = %d; want 12345679", got)
+	}
+}
@@ -0,0 +1,204 @@
+package sqlite
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// readVarint decodes a SQLite variable-length integer and returns it with the number of bytes it takes.
+func readVarint(b []byte) (int64, int) {
+	var v uint64
+	for i := 0; i < 8 && i < len(b); i++ {
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return int64(v), i + 1
+		}
+	}
+	if len(b) < 9 {
+		return int64(v), len(b)
+	}
+	// The ninth byte contributes all of its eight bits
+	return int64(v<<8 | uint64(b[8])), 9
+}
+
+// decodeRecord decodes the values of a record.
+func decodeRecord(payload []byte) ([]any, error) {
+	headerSize, n := readVarint(payload)
+	if int(headerSize) > len(payload) || headerSize < int64(n) {
+		return nil, fmt.Errorf("invalid record header")
+	}
+	header, body := payload[n:headerSize], payload[headerSize:]
+
+	var values []any
+	for len(header) > 0 {
+		serial, n := readVarint(header)
+		header = header[n:]
+
+		size := serialSize(serial)
+		if serial < 0 || size > len(body) {
+			return nil, fmt.Errorf("record is truncated")
+		}
+		field := body[:size]
+		body = body[size:]
+
+		switch {
+		case serial == 0:
+			values = append(values, nil)
+		case serial <= 6:
+			values = append(values, readInt(field))
+		case serial == 7:
+			values = append(values, math.Float64frombits(binary.BigEndian.Uint64(field)))
+		case serial == 8:
+			values = append(values, int64(0))
+		case serial == 9:
+			values = append(values, int64(1))
+		case serial >= 12 && serial%2 == 0:
+			values = append(values, append([]byte(nil), field...))
+		case serial >= 13:
+			values = append(values, string(field))
+		default:
+			return nil, fmt.Errorf("invalid serial type %d", serial)
+		}
+	}
+	return values, nil
+}
+
+// serialSize returns the size of a value of the given serial type.
+func serialSize(serial int64) int {
+	switch {
+	case serial <= 4:
+		return int(serial)
+	case serial == 5:
+		return 6
+	case serial == 6 || serial == 7:
+		return 8
+	case serial < 12:
+		return 0
+	default:
+		return int(serial-12) / 2
+	}
+}
+
+// readInt decodes a big-endian two's complement integer of up to eight bytes.
+func readInt(b []byte) int64 {
+	if len(b) == 0 {
+		return 0
+	}
+	v := int64(int8(b[0]))
+	for _, c := range b[1:] {
+		v = v<<8 | int64(c)
+	}
+	return v
+}
+
+// parseCreateTable extracts the column names of a table from its CREATE TABLE statement.
+func parseCreateTable(sql string) (*table, error) {
+	open, end := strings.Index(sql, "("), strings.LastIndex(sql, ")")
+	if open < 0 || end < open {
+		return nil, fmt.Errorf("unsupported table definition: %s", sql)
+	}
+	if strings.Contains(strings.ToUpper(sql[end:]), "WITHOUT ROWID") {
+		return nil, fmt.Errorf("WITHOUT ROWID tables are not supported")
+	}
+
+	t := &table{rowidColumn: -1}
+	for _, def := range splitDefinitions(sql[open+1 : end]) {
+		tokens := tokenize(def)
+		if len(tokens) == 0 {
+			continue
+		}
+		switch strings.ToUpper(tokens[0]) {
+		case "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN":
+			// Table constraints follow the columns
+			continue
+		}
+		// Only a column declared exactly as INTEGER PRIMARY KEY aliases the rowid
+		upper := strings.ToUpper(strings.Join(tokens[1:], " "))
+		if strings.HasPrefix(upper, "INTEGER PRIMARY KEY") && !strings.Contains(upper, "DESC") {
+			t.rowidColumn = len(t.columns)
+		}
+		t.columns = append(t.columns, unquote(tokens[0]))
+	}
+	return t, nil
+}
+
+// splitDefinitions splits the body of a CREATE TABLE statement at the commas that are not nested in parentheses
+// or quotes.
+func splitDefinitions(body string) []string {
+	var (
+		defs  []string
+		depth int
+		quote rune
+		start int
+	)
+	for i, c := range body {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			defs = append(defs, body[start:i])
+			start = i + 1
+		}
+	}
+	return append(defs, body[start:])
+}
+
+// tokenize splits a column definition into words, keeping quoted identifiers together.
+func tokenize(def string) []string {
+	var (
+		tokens []string
+		cur    strings.Builder
+		quote  rune
+	)
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+	for _, c := range def {
+		switch {
+		case quote != 0:
+			cur.WriteRune(c)
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '`' || c == '\'':
+			quote = c
+			cur.WriteRune(c)
+		case c == '[':
+			quote = ']'
+			cur.WriteRune(c)
+		case unicode.IsSpace(c):
+			flush()
+		default:
+			cur.WriteRune(c)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// unquote removes the quotes around an identifier.
+func unquote(name string) string {
+	if len(name) >= 2 {
+		switch first, last := name[0], name[len(name)-1]; {
+		case first == '"' && last == '"', first == '`' && last == '`', first == '[' && last == ']':
+			return name[1 : len(name)-1]
+		}
+	}
+	return name
+}
@@ -0,0 +1,361 @@
+// Package sqlite reads tables from SQLite database files without a database driver, so that the server can
+// stay free of cgo. It implements the parts of the file format that are needed to scan ordinary tables: table
+// b-trees, overflow pages, records and the schema. Indexes, WITHOUT ROWID tables and UTF-16 databases are not
+// supported. Transactions committed to a write-ahead log next to the database file are read as well.
+package sqlite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// magic is the header string every SQLite database file starts with.
+const magic = "SQLite format 3\x00"
+
+// DB is a SQLite database read into memory.
+type DB struct {
+	data       []byte
+	pageSize   int
+	usableSize int
+	// wal holds the pages committed to the write-ahead log, which take precedence over the database file.
+	wal    map[int][]byte
+	tables map[string]*table
+}
+
+// table is an ordinary table of the database schema.
+type table struct {
+	name     string
+	rootPage int
+	columns  []string
+	// rowidColumn is the index of the INTEGER PRIMARY KEY column, which aliases the rowid, or -1.
+	rowidColumn int
+}
+
+// Row is a row of a table, keyed by column name. Values are nil, int64, float64, string or []byte.
+type Row map[string]any
+
+// String returns the value of a column as a string: text as is, and integers and reals formatted. NULL and
+// missing columns are empty.
+func (r Row) String(column string) string {
+	switch v := r[column].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// Int returns the value of a column as an integer. Reals are truncated and text is parsed, while NULL, missing
+// columns and text that is not a number are zero.
+func (r Row) Int(column string) int64 {
+	switch v := r[column].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+// Bool returns whether the value of a column is a non-zero integer, the way SQLite treats booleans.
+func (r Row) Bool(column string) bool {
+	return r.Int(column) != 0
+}
+
+// Open reads the database file at path, along with its write-ahead log if there is one.
+func Open(path string) (*DB, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	db, err := newDB(data)
+	if err != nil {
+		return nil, err
+	}
+	if db.wal, err = readWAL(path+"-wal", db.pageSize); err != nil {
+		return nil, fmt.Errorf("failed to read write-ahead log: %w", err)
+	}
+	if err := db.readSchema(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Parse reads a database from the contents of a database file.
+func Parse(data []byte) (*DB, error) {
+	db, err := newDB(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.readSchema(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// newDB validates the header of a database file.
+func newDB(data []byte) (*DB, error) {
+	if len(data) < 100 || string(data[:len(magic)]) != magic {
+		return nil, fmt.Errorf("not a SQLite database")
+	}
+	pageSize := int(binary.BigEndian.Uint16(data[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, fmt.Errorf("invalid page size %d", pageSize)
+	}
+	if reserved := int(data[20]); pageSize-reserved < 480 {
+		return nil, fmt.Errorf("invalid reserved space %d", reserved)
+	}
+	if enc := binary.BigEndian.Uint32(data[56:60]); enc > 1 {
+		return nil, fmt.Errorf("unsupported text encoding %d, only UTF-8 is supported", enc)
+	}
+	return &DB{
+		data:       data,
+		pageSize:   pageSize,
+		usableSize: pageSize - int(data[20]),
+		tables:     make(map[string]*table),
+	}, nil
+}
+
+// readSchema reads the tables of the database from its schema.
+func (db *DB) readSchema() error {
+	// The schema is a table rooted at page 1 with columns type, name, tbl_name, rootpage and sql
+	schema := &table{name: "sqlite_schema", rootPage: 1, columns: []string{"type", "name", "tbl_name", "rootpage", "sql"}, rowidColumn: -1}
+	err := db.scan(schema, func(row Row) error {
+		if row["type"] != "table" {
+			return nil
+		}
+		name, _ := row["name"].(string)
+		root, _ := row["rootpage"].(int64)
+		sql, _ := row["sql"].(string)
+		if strings.HasPrefix(name, "sqlite_") || root == 0 {
+			// Internal and virtual tables have no b-tree to scan
+			return nil
+		}
+		t, err := parseCreateTable(sql)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", name, err)
+		}
+		t.name, t.rootPage = name, int(root)
+		db.tables[strings.ToLower(name)] = t
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	return nil
+}
+
+// Tables returns the names of the tables in the database, in alphabetical order.
+func (db *DB) Tables() []string {
+	names := make([]string, 0, len(db.tables))
+	for _, t := range db.tables {
+		names = append(names, t.name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// HasTable reports whether the database has a table with the given name, which is case-insensitive.
+func (db *DB) HasTable(name string) bool {
+	_, ok := db.tables[strings.ToLower(name)]
+	return ok
+}
+
+// Columns returns the names of the columns of a table, in the order of its definition.
+func (db *DB) Columns(name string) ([]string, error) {
+	t, ok := db.tables[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("no such table: %s", name)
+	}
+	return slices.Clone(t.columns), nil
+}
+
+// Rows returns the rows of a table in rowid order. Columns added to the table after a row was written are nil.
+func (db *DB) Rows(name string) ([]Row, error) {
+	t, ok := db.tables[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("no such table: %s", name)
+	}
+	var rows []Row
+	err := db.scan(t, func(row Row) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table %s: %w", name, err)
+	}
+	return rows, nil
+}
+
+// page returns the contents of the page with the given number, which starts at one.
+func (db *DB) page(n int) ([]byte, error) {
+	if page, ok := db.wal[n]; ok {
+		return page, nil
+	}
+	start := (n - 1) * db.pageSize
+	if n < 1 || start+db.pageSize > len(db.data) {
+		return nil, fmt.Errorf("page %d is out of range", n)
+	}
+	return db.data[start : start+db.pageSize], nil
+}
+
+// scan calls fn with every row of the table b-tree of t.
+func (db *DB) scan(t *table, fn func(Row) error) error {
+	return db.walk(t.rootPage, make(map[int]bool), func(rowid int64, payload []byte) error {
+		values, err := decodeRecord(payload)
+		if err != nil {
+			return err
+		}
+		row := make(Row, len(t.columns))
+		for i, col := range t.columns {
+			var v any
+			if i < len(values) {
+				v = values[i]
+			}
+			if i == t.rowidColumn {
+				v = rowid
+			}
+			row[col] = v
+		}
+		return fn(row)
+	})
+}
+
+// walk calls fn with the rowid and payload of every cell of the table b-tree rooted at page n, in rowid order.
+// Offsets read from the file are checked against the page, and visited holds the pages walked so far, so that a
+// corrupt or partially written file yields an error rather than a panic or a cycle.
+func (db *DB) walk(n int, visited map[int]bool, fn func(rowid int64, payload []byte) error) error {
+	if visited[n] {
+		return fmt.Errorf("page %d is referenced twice, the database may be corrupt", n)
+	}
+	visited[n] = true
+	page, err := db.page(n)
+	if err != nil {
+		return err
+	}
+	header := 0
+	if n == 1 {
+		// The first page starts with the database header
+		header = 100
+	}
+	if header+12 > len(page) {
+		return fmt.Errorf("page %d is truncated", n)
+	}
+	kind := page[header]
+	cells := int(binary.BigEndian.Uint16(page[header+3 : header+5]))
+	// cell returns the offset of cell i, whose pointer follows the page header of the given size.
+	cell := func(i, size int) (int, error) {
+		ptr := header + size + 2*i
+		if ptr+2 > len(page) {
+			return 0, fmt.Errorf("page %d: cell pointer %d is out of bounds", n, i)
+		}
+		off := int(binary.BigEndian.Uint16(page[ptr:]))
+		if off >= len(page) {
+			return 0, fmt.Errorf("page %d: cell %d is out of bounds", n, i)
+		}
+		return off, nil
+	}
+
+	switch kind {
+	case 0x05: // interior table page
+		for i := range cells {
+			off, err := cell(i, 12)
+			if err != nil {
+				return err
+			}
+			if off+4 > len(page) {
+				return fmt.Errorf("page %d: cell %d is out of bounds", n, i)
+			}
+			child := int(binary.BigEndian.Uint32(page[off:]))
+			if err := db.walk(child, visited, fn); err != nil {
+				return err
+			}
+		}
+		right := int(binary.BigEndian.Uint32(page[header+8:]))
+		return db.walk(right, visited, fn)
+
+	case 0x0d: // leaf table page
+		for i := range cells {
+			off, err := cell(i, 8)
+			if err != nil {
+				return err
+			}
+			size, n1 := readVarint(page[off:])
+			rowid, n2 := readVarint(page[off+n1:])
+			if size < 0 || off+n1+n2 > len(page) {
+				return fmt.Errorf("page %d: cell %d is out of bounds", n, i)
+			}
+			payload, err := db.payload(page[off+n1+n2:], int(size))
+			if err != nil {
+				return fmt.Errorf("row %d: %w", rowid, err)
+			}
+			if err := fn(rowid, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("page %d is not a table b-tree page (type %#x)", n, kind)
+	}
+}
+
+// payload returns a payload of the given size that starts at cell, following overflow pages if it does not
+// fit into the page.
+func (db *DB) payload(cell []byte, size int) ([]byte, error) {
+	u := db.usableSize
+	maxLocal := u - 35
+	if size <= maxLocal {
+		if size > len(cell) {
+			return nil, fmt.Errorf("payload exceeds page")
+		}
+		return cell[:size], nil
+	}
+	minLocal := (u-12)*32/255 - 23
+	local := minLocal + (size-minLocal)%(u-4)
+	if local > maxLocal {
+		local = minLocal
+	}
+	if local+4 > len(cell) {
+		return nil, fmt.Errorf("payload exceeds page")
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(min(size, len(db.data)))
+	buf.Write(cell[:local])
+	next := int(binary.BigEndian.Uint32(cell[local:]))
+	for buf.Len() < size {
+		if next == 0 {
+			return nil, fmt.Errorf("overflow chain ends early")
+		}
+		page, err := db.page(next)
+		if err != nil {
+			return nil, err
+		}
+		chunk := min(size-buf.Len(), u-4)
+		if 4+chunk > len(page) {
+			return nil, fmt.Errorf("overflow page %d is truncated", next)
+		}
+		buf.Write(page[4 : 4+chunk])
+		next = int(binary.BigEndian.Uint32(page))
+	}
+	return buf.Bytes(), nil
+}
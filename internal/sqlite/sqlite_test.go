@@ -0,0 +1,212 @@
+package sqlite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testdata/fixture.sqlite is generated from testdata/fixture.sql with the sqlite3 shell. It uses small pages, so
+// that the items table spans interior pages and its longest name spills onto overflow pages.
+func open(t *testing.T) *DB {
+	t.Helper()
+	db, err := Open("testdata/fixture.sqlite")
+	if err != nil {
+		t.Fatalf("Open error: %v", err)
+	}
+	return db
+}
+
+func TestSchema(t *testing.T) {
+	db := open(t)
+	if tables := db.Tables(); !slices.Equal(tables, []string{"accounts", "items"}) {
+		t.Errorf("Tables() = %v; want accounts and items", tables)
+	}
+	if !db.HasTable("ITEMS") || db.HasTable("items_name") {
+		t.Error("HasTable must be case-insensitive and only report tables")
+	}
+	columns, err := db.Columns("items")
+	if err != nil {
+		t.Fatalf("Columns error: %v", err)
+	}
+	if want := []string{"id", "name", "price", "data", "count", "note"}; !slices.Equal(columns, want) {
+		t.Errorf("Columns() = %v; want %v", columns, want)
+	}
+	if _, err := db.Rows("missing"); err == nil {
+		t.Error("expected an error for a missing table")
+	}
+}
+
+func TestRows(t *testing.T) {
+	db := open(t)
+	rows, err := db.Rows("items")
+	if err != nil {
+		t.Fatalf("Rows error: %v", err)
+	}
+	if len(rows) != 503 {
+		t.Fatalf("len(rows) = %d; want 503", len(rows))
+	}
+
+	first := rows[0]
+	if first["id"] != int64(1) || first["name"] != "Crème brûlée" || first["price"] != -1.5 || first["count"] != int64(-1) {
+		t.Errorf("first row = %v", first)
+	}
+	if data, _ := first["data"].([]byte); !bytes.Equal(data, []byte{0x00, 0xff, 0x10}) {
+		t.Errorf("data = %v; want the blob", first["data"])
+	}
+
+	if long := rows[2]; long["id"] != int64(3) || long["name"] != strings.Repeat("ab", 6000) || long["price"] != nil {
+		t.Errorf("overflowing row = id %v, %d characters, price %v", long["id"], len(long["name"].(string)), long["price"])
+	}
+
+	var prev int64
+	for _, row := range rows {
+		id := row["id"].(int64)
+		if id <= prev {
+			t.Fatalf("row %d follows row %d; want rowid order", id, prev)
+		}
+		prev = id
+		if id%2 != 0 || id > 1000 {
+			continue
+		}
+		n := id / 2
+		if row["name"] != "item "+strconv.FormatInt(n, 10) || row["count"] != n*1000000000 || row["note"] != nil {
+			t.Fatalf("row = %v; want item %d", row, n)
+		}
+	}
+
+	if last := rows[len(rows)-1]; last["id"] != int64(5000) || last["note"] != "added later" || last["count"] != int64(0) {
+		t.Errorf("last row = %v; want the row with a note", last)
+	}
+}
+
+func TestRows_TextPrimaryKey(t *testing.T) {
+	rows, err := open(t).Rows("accounts")
+	if err != nil {
+		t.Fatalf("Rows error: %v", err)
+	}
+	if len(rows) != 2 || rows[0]["id"] != "a1" || rows[1]["name"] != "Mortgage" || rows[1]["offbudget"] != int64(1) {
+		t.Errorf("rows = %v", rows)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	if _, err := Parse([]byte("not a database")); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+// TestParse_Corrupt feeds truncated and corrupted copies of the fixture to Parse and Rows, which must return
+// errors, or whatever rows they can still read, rather than panic.
+func TestParse_Corrupt(t *testing.T) {
+	data, err := os.ReadFile("testdata/fixture.sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := func(t *testing.T, data []byte) {
+		t.Helper()
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("panic: %v", r)
+			}
+		}()
+		db, err := Parse(data)
+		if err != nil {
+			return
+		}
+		for _, name := range db.Tables() {
+			_, _ = db.Rows(name)
+		}
+	}
+
+	for _, size := range []int{100, 512, 1000, 1024, len(data) / 2, len(data) - 1} {
+		t.Run("truncated to "+strconv.Itoa(size), func(t *testing.T) {
+			read(t, data[:size])
+		})
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range 200 {
+		t.Run("corrupted "+strconv.Itoa(i), func(t *testing.T) {
+			corrupt := slices.Clone(data)
+			for range 1 + rng.IntN(8) {
+				// Leave the header alone, which Parse validates anyway, and corrupt the b-tree pages.
+				corrupt[100+rng.IntN(len(corrupt)-100)] = byte(rng.Uint32())
+			}
+			read(t, corrupt)
+		})
+	}
+
+	// The root page of the schema points its first cell past the end of the page.
+	corrupt := slices.Clone(data)
+	header := 100
+	binary.BigEndian.PutUint16(corrupt[header+8:], 0xffff)
+	if _, err := Parse(corrupt); err == nil {
+		t.Error("Parse succeeded; want an error for a cell out of bounds")
+	}
+}
+
+func TestParseCreateTable(t *testing.T) {
+	tbl, err := parseCreateTable(`CREATE TABLE t ("a b" TEXT, id integer primary key, c DECIMAL(10, 2), PRIMARY KEY (c))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tbl.columns, []string{"a b", "id", "c"}) || tbl.rowidColumn != 1 {
+		t.Errorf("table = %+v", tbl)
+	}
+	if _, err := parseCreateTable(`CREATE TABLE t (id TEXT PRIMARY KEY) WITHOUT ROWID`); err == nil {
+		t.Error("expected an error for a WITHOUT ROWID table")
+	}
+}
+
+// testdata/wal.sqlite is generated by testdata/wal.py with a transaction in its write-ahead log.
+func TestOpen_WriteAheadLog(t *testing.T) {
+	db, err := Open("testdata/wal.sqlite")
+	if err != nil {
+		t.Fatalf("Open error: %v", err)
+	}
+	rows, err := db.Rows("notes")
+	if err != nil {
+		t.Fatalf("Rows error: %v", err)
+	}
+	if len(rows) != 2 || rows[0]["text"] != "checkpointed" || rows[1]["text"] != "committed" || !db.HasTable("tags") {
+		t.Errorf("rows = %v, tables = %v; want the committed transaction", rows, db.Tables())
+	}
+
+	data, err := os.ReadFile("testdata/wal.sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db, err := Parse(data); err != nil || db.HasTable("tags") {
+		t.Errorf("Parse() = %v, %v; want the database without the log", db.Tables(), err)
+	}
+
+	wal, err := os.ReadFile("testdata/wal.sqlite-wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages, err := parseWAL(wal[:len(wal)-1], 1024); err != nil || len(pages) != 0 {
+		t.Errorf("parseWAL() = %d pages, %v; want none without the commit frame", len(pages), err)
+	}
+	wal[len(wal)-1] ^= 0xff
+	if pages, err := parseWAL(wal, 1024); err != nil || len(pages) != 0 {
+		t.Errorf("parseWAL() = %d pages, %v; want none with a bad checksum", len(pages), err)
+	}
+}
+
+func TestRow(t *testing.T) {
+	row := Row{"text": "42", "int": int64(7), "real": 2.5, "null": nil}
+	if row.String("int") != "7" || row.String("real") != "2.5" || row.String("null") != "" || row.String("missing") != "" {
+		t.Errorf("String() = %q, %q, %q", row.String("int"), row.String("real"), row.String("null"))
+	}
+	if row.Int("text") != 42 || row.Int("real") != 2 || row.Int("null") != 0 {
+		t.Errorf("Int() = %d, %d, %d", row.Int("text"), row.Int("real"), row.Int("null"))
+	}
+	if !row.Bool("int") || row.Bool("null") {
+		t.Error("Bool() must be true for non-zero integers only")
+	}
+}
@@ -0,0 +1,26 @@
+-- Regenerate fixture.sqlite with: rm -f fixture.sqlite && sqlite3 fixture.sqlite < fixture.sql
+PRAGMA page_size = 1024;
+
+CREATE TABLE "items" (
+  id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  price REAL,
+  data BLOB,
+  [count] INTEGER DEFAULT 0,
+  CONSTRAINT named CHECK (length(name) > 0)
+);
+
+WITH RECURSIVE seq(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM seq WHERE n < 500)
+INSERT INTO items (id, name, price, data, count)
+SELECT n * 2, 'item ' || n, n / 4.0, NULL, n * 1000000000 FROM seq;
+
+INSERT INTO items (id, name, price, data, count) VALUES (1, 'Crème brûlée', -1.5, x'00ff10', -1);
+INSERT INTO items (id, name, price, data, count) VALUES (3, replace(hex(zeroblob(3000)), '0', 'ab'), NULL, NULL, NULL);
+
+ALTER TABLE items ADD COLUMN note TEXT;
+INSERT INTO items (id, name, note) VALUES (5000, 'noted', 'added later');
+
+CREATE TABLE accounts (id TEXT PRIMARY KEY, name TEXT, "offbudget" INTEGER DEFAULT 0, tombstone INTEGER DEFAULT 0);
+INSERT INTO accounts VALUES ('a1', 'Checking', 0, 0), ('a2', 'Mortgage', 1, 0);
+
+CREATE INDEX items_name ON items (name);
@@ -0,0 +1,26 @@
+# Regenerates wal.sqlite and wal.sqlite-wal, a database with a transaction that is committed to the write-ahead
+# log but not checkpointed into the database file: python3 wal.py
+import os, shutil, sqlite3
+
+for f in ("wal.sqlite", "wal.sqlite-wal", "wal.sqlite-shm", "tmp.sqlite", "tmp.sqlite-wal", "tmp.sqlite-shm"):
+    if os.path.exists(f):
+        os.remove(f)
+
+db = sqlite3.connect("tmp.sqlite", isolation_level=None)
+db.execute("PRAGMA page_size = 1024")
+db.execute("PRAGMA journal_mode = WAL")
+db.execute("PRAGMA wal_autocheckpoint = 0")
+db.execute("CREATE TABLE notes (id INTEGER PRIMARY KEY, text TEXT)")
+db.execute("INSERT INTO notes (text) VALUES ('checkpointed')")
+db.execute("PRAGMA wal_checkpoint(TRUNCATE)")
+
+db.execute("BEGIN")
+db.execute("INSERT INTO notes (text) VALUES ('committed')")
+db.execute("CREATE TABLE tags (name TEXT)")
+db.execute("COMMIT")
+
+# Closing the connection checkpoints the log, so copy the files while it is open
+shutil.copy("tmp.sqlite", "wal.sqlite")
+shutil.copy("tmp.sqlite-wal", "wal.sqlite-wal")
+db.close()
+os.remove("tmp.sqlite")
@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// readWAL returns the pages committed to the write-ahead log at path, which have not been checkpointed into the
+// database file yet, keyed by page number. A missing log has no pages.
+func readWAL(path string, pageSize int) (map[int][]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return parseWAL(data, pageSize)
+}
+
+// parseWAL returns the pages of the valid, committed frames of a write-ahead log. Frames after the last commit, or
+// with a salt or checksum that does not match, are left out as SQLite does.
+func parseWAL(data []byte, pageSize int) (map[int][]byte, error) {
+	if len(data) < 32 {
+		// An empty log is left behind after a checkpoint
+		return nil, nil
+	}
+	var order binary.ByteOrder
+	switch binary.BigEndian.Uint32(data) {
+	case 0x377f0682:
+		order = binary.LittleEndian
+	case 0x377f0683:
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("invalid write-ahead log header")
+	}
+	if size := int(binary.BigEndian.Uint32(data[8:])); size != pageSize {
+		return nil, fmt.Errorf("write-ahead log page size %d does not match database page size %d", size, pageSize)
+	}
+	salt := data[16:24]
+	s0, s1 := walChecksum(order, data[:24], 0, 0)
+	if s0 != binary.BigEndian.Uint32(data[24:]) || s1 != binary.BigEndian.Uint32(data[28:]) {
+		return nil, nil
+	}
+
+	var (
+		pages   = make(map[int][]byte)
+		pending = make(map[int][]byte)
+	)
+	for off := 32; off+24+pageSize <= len(data); off += 24 + pageSize {
+		frame := data[off : off+24]
+		page := data[off+24 : off+24+pageSize]
+		if string(frame[8:16]) != string(salt) {
+			break
+		}
+		s0, s1 = walChecksum(order, frame[:8], s0, s1)
+		s0, s1 = walChecksum(order, page, s0, s1)
+		if s0 != binary.BigEndian.Uint32(frame[16:]) || s1 != binary.BigEndian.Uint32(frame[20:]) {
+			break
+		}
+		pending[int(binary.BigEndian.Uint32(frame))] = page
+		if binary.BigEndian.Uint32(frame[4:]) != 0 {
+			// A frame with the database size after the commit ends a transaction
+			for n, p := range pending {
+				pages[n] = p
+			}
+			clear(pending)
+		}
+	}
+	return pages, nil
+}
+
+// walChecksum continues a write-ahead log checksum over b, whose length is a multiple of eight.
+func walChecksum(order binary.ByteOrder, b []byte, s0, s1 uint32) (uint32, uint32) {
+	for i := 0; i+8 <= len(b); i += 8 {
+		s0 += order.Uint32(b[i:]) + s1
+		s1 += order.Uint32(b[i+4:]) + s0
+	}
+	return s0, s1
+}
@@ -0,0 +1,213 @@
+package actual
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/sqlite"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// budgetFile is the name of the database within an Actual budget directory.
+const budgetFile = "db.sqlite"
+
+// account is an account of the budget.
+type account struct {
+	id        string
+	name      string
+	offBudget bool
+	closed    bool
+}
+
+// group is a category group of the budget.
+type group struct {
+	id       string
+	name     string
+	isIncome bool
+}
+
+// category is a category of the budget.
+type category struct {
+	id       string
+	name     string
+	groupId  string
+	isIncome bool
+}
+
+// payee is a payee of the budget. Transfer payees stand for the account money is transferred to.
+type payee struct {
+	id              string
+	name            string
+	transferAccount string
+}
+
+// transaction is a transaction of the budget. Split transactions are a parent with a child for each part.
+type transaction struct {
+	id                  string
+	accountId           string
+	categoryId          string
+	payeeId             string
+	amount              int64
+	date                types.Date
+	notes               string
+	importedDescription string
+	isParent            bool
+	parentId            string
+	startingBalance     bool
+	cleared             bool
+	reconciled          bool
+}
+
+// budget is the content of an Actual budget file. Deleted entities, which Actual keeps as tombstones so that
+// the deletions sync, are left out.
+type budget struct {
+	accounts     map[string]*account
+	groups       map[string]*group
+	categories   map[string]*category
+	payees       map[string]*payee
+	transactions []*transaction
+}
+
+// readBudget reads the budget file at path, which may also be the budget directory that holds it.
+func readBudget(path string) (*budget, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, budgetFile)
+	}
+	db, err := sqlite.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"accounts", "category_groups", "categories", "payees", "transactions"} {
+		if !db.HasTable(name) {
+			return nil, fmt.Errorf("%s is not an Actual budget, it has no %s table", path, name)
+		}
+	}
+
+	b := &budget{
+		accounts:   make(map[string]*account),
+		groups:     make(map[string]*group),
+		categories: make(map[string]*category),
+		payees:     make(map[string]*payee),
+	}
+	err = live(db, "accounts", func(row sqlite.Row) error {
+		a := &account{id: row.String("id"), name: row.String("name"), offBudget: row.Bool("offbudget"), closed: row.Bool("closed")}
+		b.accounts[a.id] = a
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = live(db, "category_groups", func(row sqlite.Row) error {
+		g := &group{id: row.String("id"), name: row.String("name"), isIncome: row.Bool("is_income")}
+		b.groups[g.id] = g
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = live(db, "categories", func(row sqlite.Row) error {
+		c := &category{id: row.String("id"), name: row.String("name"), groupId: row.String("cat_group"), isIncome: row.Bool("is_income")}
+		b.categories[c.id] = c
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = live(db, "payees", func(row sqlite.Row) error {
+		p := &payee{id: row.String("id"), name: row.String("name"), transferAccount: row.String("transfer_acct")}
+		b.payees[p.id] = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Merged categories and payees live on as mappings to the ones they were merged into
+	categoryMapping, err := mapping(db, "category_mapping", "transferId")
+	if err != nil {
+		return nil, err
+	}
+	payeeMapping, err := mapping(db, "payee_mapping", "targetId")
+	if err != nil {
+		return nil, err
+	}
+
+	err = live(db, "transactions", func(row sqlite.Row) error {
+		date, err := parseDate(row.Int("date"))
+		if err != nil {
+			return fmt.Errorf("transaction %s: %w", row.String("id"), err)
+		}
+		b.transactions = append(b.transactions, &transaction{
+			id:                  row.String("id"),
+			accountId:           row.String("acct"),
+			categoryId:          resolve(categoryMapping, row.String("category")),
+			payeeId:             resolve(payeeMapping, row.String("description")),
+			amount:              row.Int("amount"),
+			date:                date,
+			notes:               row.String("notes"),
+			importedDescription: row.String("imported_description"),
+			isParent:            row.Bool("isParent"),
+			parentId:            row.String("parent_id"),
+			startingBalance:     row.Bool("starting_balance_flag"),
+			cleared:             row.Bool("cleared"),
+			reconciled:          row.Bool("reconciled"),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// live calls fn with every row of a table that is not a tombstone.
+func live(db *sqlite.DB, table string, fn func(sqlite.Row) error) error {
+	rows, err := db.Rows(table)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if row.Bool("tombstone") {
+			continue
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapping reads a table that maps IDs to the IDs that replaced them. Budgets without the table have no mapping.
+func mapping(db *sqlite.DB, table, target string) (map[string]string, error) {
+	result := make(map[string]string)
+	if !db.HasTable(table) {
+		return result, nil
+	}
+	rows, err := db.Rows(table)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.String("id")] = row.String(target)
+	}
+	return result, nil
+}
+
+// resolve returns the ID that replaced id, or id itself.
+func resolve(mapping map[string]string, id string) string {
+	if target := mapping[id]; target != "" {
+		return target
+	}
+	return id
+}
+
+// parseDate converts a date stored as the number YYYYMMDD.
+func parseDate(n int64) (types.Date, error) {
+	return types.ParseDate(fmt.Sprintf("%04d-%02d-%02d", n/10000, n/100%100, n%100))
+}
+
+// toMoney converts an amount in cents, as Actual stores them, into Money.
+func toMoney(cents int64) types.Money {
+	return types.Money(cents * (types.FACTOR / 100))
+}
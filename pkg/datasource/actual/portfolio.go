@@ -0,0 +1,57 @@
+package actual
+
+import (
+	"context"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetPortfolio implements ds.GetPortfolioFunc. The balance of every open account, on-budget and off-budget
+// alike, becomes a position: positive balances are assets and negative balances are debts. On-budget accounts
+// are cash, while off-budget accounts are tracking accounts, such as investments and loans. Balances include
+// every transaction, like the balances Actual shows.
+func (s *Source) GetPortfolio(ctx context.Context) (portfolio *types.Portfolio, err error) {
+	b, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(ctx, "actual.portfolio")
+	defer func() { tracing.End(span, err) }()
+
+	balances := make(map[string]int64)
+	for _, txn := range b.transactions {
+		if !txn.isParent {
+			balances[txn.accountId] += txn.amount
+		}
+	}
+
+	portfolio = types.NewPortfolio()
+	for _, acct := range b.accounts {
+		if acct.closed {
+			continue
+		}
+		positionType, budget := "cash", "on-budget"
+		if acct.offBudget {
+			positionType, budget = "tracking", "off-budget"
+		}
+		balance := balances[acct.id]
+		if balance < 0 {
+			debt := types.NewDebtPosition(acct.name, positionType, -toMoney(balance))
+			debt.Annotate("budget", budget)
+			portfolio.AddDebt(debt)
+			continue
+		}
+		asset := types.NewAssetPosition(acct.name, "", positionType, "", toMoney(balance))
+		asset.Annotate("budget", budget)
+		portfolio.AddAsset(asset)
+	}
+	portfolio.Sort()
+	span.SetAttributes(attribute.Int("assets", len(portfolio.Assets)), attribute.Int("debts", len(portfolio.Debts)))
+	logging.FromContext(ctx).DebugContext(ctx, "Computed Actual portfolio",
+		"assets", len(portfolio.Assets), "debts", len(portfolio.Debts))
+	return portfolio, nil
+}
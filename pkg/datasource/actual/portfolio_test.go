@@ -0,0 +1,48 @@
+package actual
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetPortfolio(t *testing.T) {
+	portfolio, err := NewSource("testdata/budget.sqlite").GetPortfolio(context.Background())
+	if err != nil {
+		t.Fatalf("GetPortfolio error: %v", err)
+	}
+
+	if len(portfolio.Assets) != 2 || len(portfolio.Debts) != 2 {
+		t.Fatalf("portfolio = %+v; want 2 assets and 2 debts without the closed and deleted accounts", portfolio)
+	}
+	for _, asset := range portfolio.Assets {
+		switch asset.Name {
+		case "Checking":
+			if asset.Value != 49265600 || asset.Type != "cash" || asset.Annotations["budget"] != "on-budget" {
+				t.Errorf("checking = %+v; want the balance of all transactions", asset)
+			}
+		case "Brokerage":
+			if asset.Value != 10000000 || asset.Type != "tracking" || asset.Annotations["budget"] != "off-budget" {
+				t.Errorf("brokerage = %+v; want the off-budget balance", asset)
+			}
+		default:
+			t.Errorf("unexpected asset %+v", asset)
+		}
+	}
+	for _, debt := range portfolio.Debts {
+		switch debt.Name {
+		case "Credit Card":
+			if debt.Value != 2500000 || debt.Type != "cash" {
+				t.Errorf("card = %+v; want the negated balance", debt)
+			}
+		case "Mortgage":
+			if debt.Value != 2988000000 || debt.Type != "tracking" {
+				t.Errorf("mortgage = %+v; want the remaining principal", debt)
+			}
+		default:
+			t.Errorf("unexpected debt %+v", debt)
+		}
+	}
+	if portfolio.NetWorth != 49265600+10000000-2500000-2988000000 {
+		t.Errorf("NetWorth = %d; want assets minus debts", portfolio.NetWorth)
+	}
+}
@@ -0,0 +1,70 @@
+-- A subset of the schema of Actual Budget, with a small household budget.
+-- Regenerate budget.sqlite with: rm -f budget.sqlite && sqlite3 budget.sqlite < budget.sql
+CREATE TABLE accounts (id TEXT PRIMARY KEY, account_id TEXT, name TEXT, balance_current INTEGER, balance_available INTEGER, balance_limit INTEGER, mask TEXT, official_name TEXT, type TEXT, subtype TEXT, bank TEXT, offbudget INTEGER DEFAULT 0, closed INTEGER DEFAULT 0, tombstone INTEGER DEFAULT 0, sort_order REAL);
+CREATE TABLE category_groups (id TEXT PRIMARY KEY, name TEXT UNIQUE, is_income INTEGER DEFAULT 0, sort_order REAL, tombstone INTEGER DEFAULT 0, hidden BOOLEAN NOT NULL DEFAULT 0);
+CREATE TABLE categories (id TEXT PRIMARY KEY, name TEXT, is_income INTEGER DEFAULT 0, cat_group TEXT, sort_order REAL, tombstone INTEGER DEFAULT 0, hidden BOOLEAN NOT NULL DEFAULT 0);
+CREATE TABLE category_mapping (id TEXT PRIMARY KEY, transferId TEXT);
+CREATE TABLE payees (id TEXT PRIMARY KEY, name TEXT, category TEXT, tombstone INTEGER DEFAULT 0, transfer_acct TEXT);
+CREATE TABLE payee_mapping (id TEXT PRIMARY KEY, targetId TEXT);
+CREATE TABLE transactions (id TEXT PRIMARY KEY, isParent INTEGER DEFAULT 0, isChild INTEGER DEFAULT 0, acct TEXT, category TEXT, amount INTEGER, description TEXT, notes TEXT, date INTEGER, financial_id TEXT, type TEXT, location TEXT, error TEXT, imported_description TEXT, starting_balance_flag INTEGER DEFAULT 0, transferred_id TEXT, sort_order REAL, tombstone INTEGER DEFAULT 0, cleared INTEGER DEFAULT 1, pending INTEGER DEFAULT 0, parent_id TEXT, schedule TEXT, reconciled INTEGER DEFAULT 0);
+
+INSERT INTO accounts (id, name, offbudget, closed, tombstone, sort_order) VALUES
+  ('checking', 'Checking', 0, 0, 0, 1),
+  ('card', 'Credit Card', 0, 0, 0, 2),
+  ('brokerage', 'Brokerage', 1, 0, 0, 3),
+  ('mortgage', 'Mortgage', 1, 0, 0, 4),
+  ('old', 'Old Savings', 0, 1, 0, 5),
+  ('deleted', 'Deleted', 0, 0, 1, 6);
+
+INSERT INTO category_groups (id, name, is_income, sort_order) VALUES
+  ('food', 'Food', 0, 1),
+  ('bills', 'Bills', 0, 2),
+  ('income', 'Income', 1, 3);
+
+INSERT INTO categories (id, name, is_income, cat_group, sort_order, tombstone) VALUES
+  ('groceries', 'Groceries', 0, 'food', 1, 0),
+  ('restaurants', 'Restaurants', 0, 'food', 2, 0),
+  ('old-groceries', 'Supermarket', 0, 'food', 3, 1),
+  ('rent', 'Rent', 0, 'bills', 1, 0),
+  ('housing', 'Mortgage', 0, 'bills', 2, 0),
+  ('salary', 'Salary', 1, 'income', 1, 0),
+  ('starting', 'Starting Balances', 1, 'income', 2, 0);
+
+-- Actual maps every category to itself, and merged categories to the category they were merged into
+INSERT INTO category_mapping (id, transferId) VALUES
+  ('groceries', 'groceries'), ('restaurants', 'restaurants'), ('old-groceries', 'groceries'),
+  ('rent', 'rent'), ('housing', 'housing'), ('salary', 'salary'), ('starting', 'starting');
+
+INSERT INTO payees (id, name, tombstone, transfer_acct) VALUES
+  ('jita', 'Jita Market', 0, NULL),
+  ('jita-2', 'JITA MKT', 1, NULL),
+  ('landlord', 'Landlord', 0, NULL),
+  ('acme', 'ACME Corp', 0, NULL),
+  ('bistro', 'Bistro', 0, NULL),
+  ('to-checking', '', 0, 'checking'),
+  ('to-card', '', 0, 'card'),
+  ('to-mortgage', '', 0, 'mortgage');
+
+INSERT INTO payee_mapping (id, targetId) VALUES
+  ('jita', 'jita'), ('jita-2', 'jita'), ('landlord', 'landlord'), ('acme', 'acme'), ('bistro', 'bistro'),
+  ('to-checking', 'to-checking'), ('to-card', 'to-card'), ('to-mortgage', 'to-mortgage');
+
+INSERT INTO transactions (id, isParent, isChild, acct, category, amount, description, notes, date, imported_description, starting_balance_flag, transferred_id, tombstone, cleared, parent_id, reconciled) VALUES
+  ('start-checking', 0, 0, 'checking', 'starting', 500000, NULL, NULL, 20231231, NULL, 1, NULL, 0, 1, NULL, 1),
+  ('start-mortgage', 0, 0, 'mortgage', NULL, -30000000, NULL, NULL, 20231231, NULL, 1, NULL, 0, 1, NULL, 1),
+  ('rent', 0, 0, 'checking', 'rent', -150000, 'landlord', NULL, 20240101, NULL, 0, NULL, 0, 1, NULL, 1),
+  ('groceries', 0, 0, 'checking', 'old-groceries', -12345, 'jita-2', 'weekly', 20240105, 'JITA MKT #12', 0, NULL, 0, 1, NULL, 0),
+  ('split', 1, 0, 'checking', NULL, -5000, 'jita', 'party', 20240110, NULL, 0, NULL, 0, 1, NULL, 0),
+  ('split-1', 0, 1, 'checking', 'groceries', -3000, 'jita', NULL, 20240110, NULL, 0, NULL, 0, 1, 'split', 0),
+  ('split-2', 0, 1, 'checking', 'restaurants', -2000, 'jita', 'cake', 20240110, NULL, 0, NULL, 0, 1, 'split', 0),
+  ('salary', 0, 0, 'checking', 'salary', 300000, 'acme', NULL, 20240115, NULL, 0, NULL, 0, 1, NULL, 0),
+  ('card-payment', 0, 0, 'checking', NULL, -20000, 'to-card', NULL, 20240120, NULL, 0, 'card-payment-2', 0, 1, NULL, 0),
+  ('card-payment-2', 0, 0, 'card', NULL, 20000, 'to-checking', NULL, 20240120, NULL, 0, 'card-payment', 0, 1, NULL, 0),
+  ('dinner', 0, 0, 'card', 'restaurants', -45000, 'bistro', NULL, 20240121, NULL, 0, NULL, 0, 0, NULL, 0),
+  ('deleted', 0, 0, 'checking', 'groceries', -99999, 'jita', NULL, 20240122, NULL, 0, NULL, 1, 1, NULL, 0),
+  ('dividend', 0, 0, 'brokerage', NULL, 100000, NULL, 'dividend', 20240125, NULL, 0, NULL, 0, 1, NULL, 0),
+  ('mortgage', 0, 0, 'checking', 'housing', -120000, 'to-mortgage', NULL, 20240128, NULL, 0, 'mortgage-2', 0, 1, NULL, 0),
+  ('mortgage-2', 0, 0, 'mortgage', NULL, 120000, 'to-checking', NULL, 20240128, NULL, 0, 'mortgage', 0, 1, NULL, 0),
+  ('refund', 0, 0, 'checking', NULL, 1000, NULL, NULL, 20240129, 'REFUND 123', 0, NULL, 0, 1, NULL, 0),
+  ('february', 0, 0, 'checking', 'groceries', -999, 'jita', NULL, 20240201, NULL, 0, NULL, 0, 1, NULL, 0),
+  ('closed', 0, 0, 'old', NULL, 0, NULL, NULL, 20230101, NULL, 1, NULL, 0, 1, NULL, 0);
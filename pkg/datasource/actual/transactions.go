@@ -0,0 +1,194 @@
+// Package actual implements a data source that reads the budget files of Actual Budget directly, without the
+// Actual server. Category groups and categories of the budget become categories of transactions, while account
+// balances make up the portfolio.
+package actual
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sort"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Source reads an Actual budget file. The file is re-read on every call, so changes are picked up without a
+// restart.
+type Source struct {
+	path string
+}
+
+// NewSource creates a Source that reads the budget file at path, or the db.sqlite file of the budget directory
+// at path.
+func NewSource(path string) *Source {
+	return &Source{path: path}
+}
+
+// Check implements ds.CheckFunc by verifying that the budget file can be read.
+func (s *Source) Check(ctx context.Context) error {
+	_, err := s.read(ctx)
+	return err
+}
+
+// read reads the budget file.
+func (s *Source) read(ctx context.Context) (b *budget, err error) {
+	ctx, span := tracing.Start(ctx, "actual.read")
+	defer func() { tracing.End(span, err) }()
+
+	b, err = readBudget(s.path)
+	if err != nil {
+		hint := "the budget file on the server cannot be read; ask the user to check that it is an Actual budget"
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			hint = "the budget file configured on the server cannot be read; ask the user to fix it"
+		}
+		return nil, ds.Errorf(ds.ErrUpstreamUnavailable, "failed to read budget: %w", err).WithHint(hint)
+	}
+	span.SetAttributes(attribute.Int("accounts", len(b.accounts)), attribute.Int("transactions", len(b.transactions)))
+	logging.FromContext(ctx).DebugContext(ctx, "Read Actual budget", "transactions", len(b.transactions))
+	return b, nil
+}
+
+// GetCategorizedTransactions implements ds.GetCategorizedTransactionsFunc. Transactions in categories of income
+// groups are income and uncategorized inflows are income as well. Starting balances, transactions of off-budget
+// accounts and uncategorized transfers are ignored, and everything else is an expense. Split transactions are
+// divided into their parts.
+func (s *Source) GetCategorizedTransactions(ctx context.Context, interval ds.DateRange) (*types.Categories, error) {
+	b, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return categorizeTransactions(ctx, b, interval)
+}
+
+// categorizeTransactions converts the budget transactions within interval into domain types and sorts them into
+// category trees.
+func categorizeTransactions(ctx context.Context, b *budget, interval ds.DateRange) (result *types.Categories, err error) {
+	ctx, span := tracing.Start(ctx, "actual.categorize", attribute.Int("transactions", len(b.transactions)))
+	defer func() { tracing.End(span, err) }()
+
+	parents := make(map[string]*transaction)
+	for _, txn := range b.transactions {
+		if txn.isParent {
+			parents[txn.id] = txn
+		}
+	}
+	sort.SliceStable(b.transactions, func(i, j int) bool {
+		return b.transactions[i].date.Before(b.transactions[j].date)
+	})
+
+	result = types.NewCategories()
+	logger := logging.FromContext(ctx)
+	for _, txn := range b.transactions {
+		if txn.isParent || txn.date.Before(interval.StartDate) || interval.EndDate.Before(txn.date) {
+			// The parts of split transactions are their children
+			continue
+		}
+		acct := b.accounts[txn.accountId]
+		if acct == nil {
+			continue
+		}
+
+		// Parts of split transactions without a payee or notes of their own share the ones of the transaction
+		payeeId, notes := txn.payeeId, txn.notes
+		if parent := parents[txn.parentId]; parent != nil {
+			if payeeId == "" {
+				payeeId = parent.payeeId
+			}
+			if notes == "" {
+				notes = parent.notes
+			}
+		}
+		p := b.payees[payeeId]
+		transfer := p != nil && p.transferAccount != ""
+
+		tx := types.NewTransaction(txn.date, payeeName(b, p, txn), -toMoney(txn.amount))
+		tx.Description = notes
+		tx.Annotate("account", acct.name)
+		switch {
+		case txn.reconciled:
+			tx.Annotate("cleared", "reconciled")
+		case txn.cleared:
+			tx.Annotate("cleared", "cleared")
+		default:
+			tx.Annotate("cleared", "uncleared")
+		}
+
+		cat, grp := b.categories[txn.categoryId], (*group)(nil)
+		if cat != nil {
+			grp = b.groups[cat.groupId]
+		}
+
+		// Determine which "bucket" does the transaction fall under
+		var bucket *types.Category
+		switch {
+		case txn.startingBalance:
+			bucket = getOrCreateCategoryByName(result.Ignored, "Starting Balances")
+		case acct.offBudget:
+			bucket = result.Ignored
+		case transfer && txn.categoryId == "":
+			bucket = getOrCreateCategoryByName(result.Ignored, "Transfers")
+		case cat != nil && (cat.isIncome || (grp != nil && grp.isIncome)):
+			bucket = result.Income
+		case txn.categoryId == "" && txn.amount > 0:
+			bucket = result.Income
+		default:
+			bucket = result.Expenses
+		}
+
+		switch {
+		case txn.startingBalance || (transfer && txn.categoryId == ""):
+			// Already placed under their own category
+		case txn.categoryId == "":
+			bucket = getOrCreateCategoryByName(bucket, types.UncategorizedName)
+		case cat == nil:
+			logger.WarnContext(ctx, "Missing category in Actual budget", "category_id", txn.categoryId)
+			tx.Annotate("category_error", "uncategorized due to invalid category id")
+			bucket = getOrCreateCategoryByName(bucket, types.UncategorizedName)
+		case grp == nil:
+			logger.WarnContext(ctx, "Missing category group in Actual budget", "category_group_id", cat.groupId)
+			tx.Annotate("category_error", "uncategorized due to invalid category group id")
+			bucket = getOrCreateCategoryByName(bucket, types.UncategorizedName)
+		default:
+			if grp.name != bucket.Name {
+				bucket = getOrCreateCategoryByName(bucket, grp.name)
+			}
+			bucket = getOrCreateCategoryByName(bucket, cat.name)
+		}
+		if err := bucket.AddTransaction(tx); err != nil {
+			return nil, err
+		}
+	}
+	logger.DebugContext(ctx, "Categorized Actual transactions", "transactions", result.TransactionCount())
+	return result, nil
+}
+
+// payeeName returns the name of the payee of a transaction. Transfers are named after the other account, and
+// transactions without a payee after their imported description.
+func payeeName(b *budget, p *payee, txn *transaction) string {
+	switch {
+	case p == nil:
+		return txn.importedDescription
+	case p.transferAccount != "":
+		if acct := b.accounts[p.transferAccount]; acct != nil {
+			return acct.name
+		}
+	}
+	return p.name
+}
+
+// getOrCreateCategoryByName returns an existing subcategory by name under the parent,
+// or creates and attaches a new Category with that name if none exists.
+func getOrCreateCategoryByName(parent *types.Category, name string) *types.Category {
+	for _, sc := range parent.Subcategories {
+		if sc.Name == name {
+			return sc
+		}
+	}
+	newCat := types.NewCategory(name)
+	_ = parent.AddSubcategory(newCat)
+	return newCat
+}
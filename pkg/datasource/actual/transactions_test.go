@@ -0,0 +1,97 @@
+package actual
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// testdata/budget.sqlite is generated from testdata/budget.sql with the sqlite3 shell.
+
+func january(t *testing.T) ds.DateRange {
+	t.Helper()
+	start, _ := types.ParseDate("2024-01-01")
+	end, _ := types.ParseDate("2024-01-31")
+	return ds.DateRange{StartDate: start, EndDate: end}
+}
+
+func TestGetCategorizedTransactions(t *testing.T) {
+	result, err := NewSource("testdata/budget.sqlite").GetCategorizedTransactions(context.Background(), january(t))
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+
+	rent := result.Expenses.GetOrCreatePath("Bills", "Rent").Transactions
+	if len(rent) != 1 || rent[0].Amount != 15000000 || rent[0].Payee != "Landlord" || rent[0].Annotations["cleared"] != "reconciled" {
+		t.Errorf("rent = %+v; want the reconciled rent payment", rent)
+	}
+
+	groceries := result.Expenses.GetOrCreatePath("Food", "Groceries").Transactions
+	if len(groceries) != 2 {
+		t.Fatalf("groceries = %+v; want the merged category and the split part", groceries)
+	}
+	if tx := groceries[0]; tx.Payee != "Jita Market" || tx.Amount != 1234500 || tx.Description != "weekly" ||
+		tx.Date.String() != "2024-01-05" || tx.Annotations["account"] != "Checking" {
+		t.Errorf("groceries = %+v; want the merged payee and category", tx)
+	}
+	if tx := groceries[1]; tx.Amount != 300000 || tx.Description != "party" {
+		t.Errorf("split part = %+v; want the notes of the split transaction", tx)
+	}
+
+	restaurants := result.Expenses.GetOrCreatePath("Food", "Restaurants").Transactions
+	if len(restaurants) != 2 || restaurants[0].Description != "cake" || restaurants[1].Annotations["cleared"] != "uncleared" {
+		t.Errorf("restaurants = %+v; want the split part and the uncleared dinner", restaurants)
+	}
+
+	// Transfers to off-budget accounts with a category are expenses, and are named after the account
+	mortgage := result.Expenses.GetOrCreatePath("Bills", "Mortgage").Transactions
+	if len(mortgage) != 1 || mortgage[0].Payee != "Mortgage" || mortgage[0].Amount != 12000000 {
+		t.Errorf("mortgage = %+v; want the categorized transfer", mortgage)
+	}
+
+	// Income groups are not repeated below the income bucket
+	salary := result.Income.GetOrCreatePath("Salary").Transactions
+	if len(salary) != 1 || salary[0].Amount != -30000000 {
+		t.Errorf("salary = %+v; want the paycheck", salary)
+	}
+	refund := result.Income.GetOrCreatePath(types.UncategorizedName).Transactions
+	if len(refund) != 1 || refund[0].Payee != "REFUND 123" {
+		t.Errorf("uncategorized income = %+v; want the refund named by its imported description", refund)
+	}
+
+	transfers := result.Ignored.GetOrCreatePath("Transfers").Transactions
+	if len(transfers) != 2 || transfers[0].Payee != "Credit Card" || transfers[1].Payee != "Checking" {
+		t.Errorf("transfers = %+v; want both sides of the card payment", transfers)
+	}
+	if n := result.TransactionCount(); n != 12 {
+		t.Errorf("TransactionCount() = %d; want 12 without parents, deleted and February transactions", n)
+	}
+}
+
+func TestGetCategorizedTransactions_StartingBalances(t *testing.T) {
+	start, _ := types.ParseDate("2023-12-01")
+	end, _ := types.ParseDate("2023-12-31")
+	result, err := NewSource("testdata/budget.sqlite").GetCategorizedTransactions(context.Background(), ds.DateRange{StartDate: start, EndDate: end})
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+	starting := result.Ignored.GetOrCreatePath("Starting Balances").Transactions
+	if len(starting) != 2 || result.TransactionCount() != 2 {
+		t.Errorf("starting balances = %+v; want both opening balances ignored", starting)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := NewSource("testdata").Check(context.Background()); !errors.Is(err, ds.ErrUpstreamUnavailable) {
+		t.Errorf("err = %v; want upstream unavailable for a directory without a budget", err)
+	}
+	if err := NewSource("testdata/budget.sql").Check(context.Background()); !errors.Is(err, ds.ErrUpstreamUnavailable) {
+		t.Errorf("err = %v; want upstream unavailable for a file that is not a database", err)
+	}
+	if err := NewSource("testdata/budget.sqlite").Check(context.Background()); err != nil {
+		t.Errorf("Check error: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package datasource
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// CacheObserver is notified of every cache lookup and whether it was served from the cache.
+type CacheObserver func(hit bool)
+
+// CacheTransactions wraps a data source so that its results are reused for ttl, keyed by date range.
+// Callers receive deep copies of cached results and are free to modify them. A non-positive ttl disables caching.
+// Lookups are reported to observers, if any.
+func CacheTransactions(fn GetCategorizedTransactionsFunc, ttl time.Duration, observers ...CacheObserver) GetCategorizedTransactionsFunc {
+	if ttl <= 0 {
+		return fn
+	}
+	c := newCache[DateRange](ttl, (*types.Categories).Clone, observers)
+	return func(ctx context.Context, interval DateRange) (*types.Categories, error) {
+		return c.get(interval, func() (*types.Categories, error) {
+			return fn(ctx, interval)
+		})
+	}
+}
+
+// CachePortfolio wraps a data source so that its result is reused for ttl.
+// Callers receive deep copies of the cached result and are free to modify it. A non-positive ttl disables caching.
+// Lookups are reported to observers, if any.
+func CachePortfolio(fn GetPortfolioFunc, ttl time.Duration, observers ...CacheObserver) GetPortfolioFunc {
+	if ttl <= 0 {
+		return fn
+	}
+	c := newCache[struct{}](ttl, (*types.Portfolio).Clone, observers)
+	return func(ctx context.Context) (*types.Portfolio, error) {
+		return c.get(struct{}{}, func() (*types.Portfolio, error) {
+			return fn(ctx)
+		})
+	}
+}
+
+// CacheSelectedPortfolio wraps a data source so that its results are reused for ttl, keyed by selector.
+// Callers receive deep copies of cached results and are free to modify them. A non-positive ttl disables caching.
+// Lookups are reported to observers, if any.
+func CacheSelectedPortfolio(fn SelectPortfolioFunc, ttl time.Duration, observers ...CacheObserver) SelectPortfolioFunc {
+	if ttl <= 0 {
+		return fn
+	}
+	c := newCache[string](ttl, (*types.Portfolio).Clone, observers)
+	return func(ctx context.Context, selector string) (*types.Portfolio, error) {
+		return c.get(selector, func() (*types.Portfolio, error) {
+			return fn(ctx, selector)
+		})
+	}
+}
+
+// cacheEntry is a cached value along with its expiration time.
+type cacheEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// cache is a minimal TTL cache. Failed loads are not cached.
+type cache[K comparable, V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	clone   func(V) (V, error)
+	now     func() time.Time
+	entries map[K]cacheEntry[V]
+	// observers are notified of every lookup.
+	observers []CacheObserver
+}
+
+func newCache[K comparable, V any](ttl time.Duration, clone func(V) (V, error), observers []CacheObserver) *cache[K, V] {
+	return &cache[K, V]{
+		ttl:       ttl,
+		clone:     clone,
+		now:       time.Now,
+		entries:   make(map[K]cacheEntry[V]),
+		observers: observers,
+	}
+}
+
+// observe reports a lookup to the observers.
+func (c *cache[K, V]) observe(hit bool) {
+	for _, observer := range c.observers {
+		observer(hit)
+	}
+}
+
+// get returns a copy of the cached value for key, calling load to populate the cache on a miss.
+func (c *cache[K, V]) get(key K, load func() (V, error)) (V, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		c.observe(true)
+		return c.clone(entry.value)
+	}
+	c.observe(false)
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry[V]{value: value, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+
+	return c.clone(value)
+}
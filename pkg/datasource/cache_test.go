@@ -0,0 +1,112 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func TestCacheTransactions_ReusesResultsPerRange(t *testing.T) {
+	calls := 0
+	fn := CacheTransactions(func(ctx context.Context, _ DateRange) (*types.Categories, error) {
+		calls++
+		cats := types.NewCategories()
+		_ = cats.Expenses.AddTransaction(types.NewTransaction(types.Date{}, "Payee", 100))
+		return cats, nil
+	}, time.Minute)
+
+	jan := DateRange{StartDate: mustDate(t, "2024-01-01"), EndDate: mustDate(t, "2024-01-31")}
+	feb := DateRange{StartDate: mustDate(t, "2024-02-01"), EndDate: mustDate(t, "2024-02-29")}
+
+	first, _ := fn(context.Background(), jan)
+	first.Expenses.Transactions = nil // callers may modify results freely
+	second, _ := fn(context.Background(), jan)
+	if calls != 1 {
+		t.Errorf("calls = %d; want 1", calls)
+	}
+	if len(second.Expenses.Transactions) != 1 {
+		t.Errorf("cached result was modified through a previous copy")
+	}
+
+	_, _ = fn(context.Background(), feb)
+	if calls != 2 {
+		t.Errorf("calls = %d; want 2 for a different range", calls)
+	}
+}
+
+func TestCachePortfolio_Expires(t *testing.T) {
+	calls := 0
+	load := func() (*types.Portfolio, error) {
+		calls++
+		return types.NewPortfolio(), nil
+	}
+	var hits, misses int
+	c := newCache[struct{}](time.Minute, (*types.Portfolio).Clone, []CacheObserver{func(hit bool) {
+		if hit {
+			hits++
+		} else {
+			misses++
+		}
+	}})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	_, _ = c.get(struct{}{}, load)
+	now = now.Add(30 * time.Second)
+	_, _ = c.get(struct{}{}, load)
+	if calls != 1 {
+		t.Errorf("calls = %d; want 1 before expiration", calls)
+	}
+	now = now.Add(time.Minute)
+	_, _ = c.get(struct{}{}, load)
+	if calls != 2 {
+		t.Errorf("calls = %d; want 2 after expiration", calls)
+	}
+	if hits != 1 || misses != 2 {
+		t.Errorf("hits, misses = %d, %d; want 1, 2", hits, misses)
+	}
+}
+
+func TestCacheSelectedPortfolio_ReusesResultsPerSelector(t *testing.T) {
+	var calls []string
+	fn := CacheSelectedPortfolio(func(ctx context.Context, selector string) (*types.Portfolio, error) {
+		calls = append(calls, selector)
+		return types.NewPortfolio(), nil
+	}, time.Minute)
+
+	for _, selector := range []string{"", "Estate", "", "Estate", "all"} {
+		if _, err := fn(context.Background(), selector); err != nil {
+			t.Fatalf("fn(%q) error: %v", selector, err)
+		}
+	}
+	if strings.Join(calls, ",") != ",Estate,all" {
+		t.Errorf("calls = %q; want one per selector", calls)
+	}
+}
+
+func TestCache_DoesNotCacheErrors(t *testing.T) {
+	calls := 0
+	fn := CachePortfolio(func(ctx context.Context) (*types.Portfolio, error) {
+		calls++
+		return nil, errors.New("boom")
+	}, time.Minute)
+
+	_, _ = fn(context.Background())
+	_, err := fn(context.Background())
+	if err == nil || calls != 2 {
+		t.Errorf("err = %v, calls = %d; want error and 2 calls", err, calls)
+	}
+}
+
+func mustDate(t *testing.T, s string) types.Date {
+	t.Helper()
+	d, err := types.ParseDate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
@@ -0,0 +1,258 @@
+package csv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// AmountSign is the sign convention of the amount column of a CSV export.
+type AmountSign string
+
+const (
+	// ExpensesNegative means that money leaving the account is negative, which is what most banks export.
+	ExpensesNegative AmountSign = "expenses_negative"
+	// ExpensesPositive means that money leaving the account is positive, which is common for credit cards.
+	ExpensesPositive AmountSign = "expenses_positive"
+)
+
+// Profile describes the column layout of the CSV exports of one institution. Columns are identified by the
+// names in the header row.
+type Profile struct {
+	// Name identifies the profile in errors and annotations.
+	Name string `yaml:"name" toml:"name"`
+	// Files is a glob pattern, relative to the export directory, that selects the files using this profile.
+	Files string `yaml:"files" toml:"files"`
+	// Account is an optional name of the account, attached to its transactions as an annotation.
+	Account string `yaml:"account" toml:"account"`
+	// Delimiter is the field separator. Defaults to a comma.
+	Delimiter string `yaml:"delimiter" toml:"delimiter"`
+	// SkipRows is the number of lines preceding the header row, such as account information.
+	SkipRows int `yaml:"skip_rows" toml:"skip_rows"`
+
+	// DateColumn holds the transaction date.
+	DateColumn string `yaml:"date_column" toml:"date_column"`
+	// DateFormat is the layout of dates as understood by time.Parse, e.g. 01/02/2006. Defaults to 2006-01-02.
+	DateFormat string `yaml:"date_format" toml:"date_format"`
+
+	// AmountColumn holds signed amounts, interpreted according to AmountSign.
+	// Exports with separate debit and credit columns use DebitColumn and CreditColumn instead.
+	AmountColumn string `yaml:"amount_column" toml:"amount_column"`
+	// AmountSign is the sign convention of AmountColumn. Defaults to ExpensesNegative.
+	AmountSign AmountSign `yaml:"amount_sign" toml:"amount_sign"`
+	// DebitColumn holds amounts of money leaving the account.
+	DebitColumn string `yaml:"debit_column" toml:"debit_column"`
+	// CreditColumn holds amounts of money entering the account.
+	CreditColumn string `yaml:"credit_column" toml:"credit_column"`
+	// DecimalSeparator is the decimal separator of amounts, either a period or a comma. Defaults to a period.
+	DecimalSeparator string `yaml:"decimal_separator" toml:"decimal_separator"`
+
+	// PayeeColumn holds the payee or transaction description.
+	PayeeColumn string `yaml:"payee_column" toml:"payee_column"`
+	// CategoryColumn optionally holds the category assigned by the institution. Nested categories are
+	// separated by slashes. Transactions without a category are categorized by payee rules.
+	CategoryColumn string `yaml:"category_column" toml:"category_column"`
+	// MemoColumn optionally holds notes, which become the transaction description.
+	MemoColumn string `yaml:"memo_column" toml:"memo_column"`
+	// IgnoredCategories are categories, such as transfers and card payments, whose transactions are ignored.
+	IgnoredCategories []string `yaml:"ignored_categories" toml:"ignored_categories"`
+}
+
+// Validate checks the profile for errors, reporting all of them at once, and fills in defaults.
+func (p *Profile) Validate() error {
+	var errs []error
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if p.Name == "" {
+		fail("name", "must be set")
+	}
+	if p.Files == "" {
+		fail("files", "must be set")
+	} else if _, err := filepath.Match(p.Files, ""); err != nil {
+		fail("files", "invalid glob pattern %q", p.Files)
+	}
+	if p.Delimiter == "" {
+		p.Delimiter = ","
+	}
+	if len([]rune(p.Delimiter)) != 1 {
+		fail("delimiter", "must be a single character, got %q", p.Delimiter)
+	}
+	if p.SkipRows < 0 {
+		fail("skip_rows", "must not be negative")
+	}
+	if p.DateColumn == "" {
+		fail("date_column", "must be set")
+	}
+	if p.DateFormat == "" {
+		p.DateFormat = time.DateOnly
+	}
+	switch {
+	case p.AmountColumn == "" && p.DebitColumn == "" && p.CreditColumn == "":
+		fail("amount_column", "either amount_column or debit_column and credit_column must be set")
+	case p.AmountColumn != "" && (p.DebitColumn != "" || p.CreditColumn != ""):
+		fail("amount_column", "cannot be combined with debit_column and credit_column")
+	}
+	switch p.AmountSign {
+	case "":
+		p.AmountSign = ExpensesNegative
+	case ExpensesNegative, ExpensesPositive:
+	default:
+		fail("amount_sign", "must be %s or %s, got %q", ExpensesNegative, ExpensesPositive, p.AmountSign)
+	}
+	switch p.DecimalSeparator {
+	case "":
+		p.DecimalSeparator = "."
+	case ".", ",":
+	default:
+		fail("decimal_separator", "must be a period or a comma, got %q", p.DecimalSeparator)
+	}
+	if p.PayeeColumn == "" {
+		fail("payee_column", "must be set")
+	}
+	return errors.Join(errs...)
+}
+
+// matches reports whether the file at path, relative to the export directory, uses this profile.
+func (p *Profile) matches(path string) bool {
+	ok, _ := filepath.Match(p.Files, filepath.ToSlash(path))
+	return ok
+}
+
+// columns maps the column names of a profile to their indexes in the header row of a file.
+type columns struct {
+	date, amount, debit, credit, payee, category, memo int
+}
+
+// resolveColumns locates the columns of the profile in header. Optional columns that are not configured
+// are -1; configured columns that are missing from the header are an error.
+func (p *Profile) resolveColumns(header []string) (columns, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+	var missing []string
+	lookup := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		i, ok := index[name]
+		if !ok {
+			missing = append(missing, strconv.Quote(name))
+			return -1
+		}
+		return i
+	}
+	cols := columns{
+		date:     lookup(p.DateColumn),
+		amount:   lookup(p.AmountColumn),
+		debit:    lookup(p.DebitColumn),
+		credit:   lookup(p.CreditColumn),
+		payee:    lookup(p.PayeeColumn),
+		category: lookup(p.CategoryColumn),
+		memo:     lookup(p.MemoColumn),
+	}
+	if len(missing) > 0 {
+		return columns{}, fmt.Errorf("header has no column %s", strings.Join(missing, ", "))
+	}
+	return cols, nil
+}
+
+// row is a transaction read from a CSV export.
+type row struct {
+	date types.Date
+	// amount follows the domain convention: positive amounts are expenses.
+	amount   types.Money
+	payee    string
+	category string
+	memo     string
+}
+
+// parseRow converts the fields of a record into a row.
+func (p *Profile) parseRow(cols columns, record []string) (row, error) {
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	t, err := time.Parse(p.DateFormat, field(cols.date))
+	if err != nil {
+		return row{}, fmt.Errorf("invalid date %q, expected format %s", field(cols.date), p.DateFormat)
+	}
+	r := row{
+		date:     types.Date(t),
+		payee:    field(cols.payee),
+		category: field(cols.category),
+		memo:     field(cols.memo),
+	}
+
+	if cols.amount >= 0 {
+		amount, err := p.parseAmount(field(cols.amount))
+		if err != nil {
+			return row{}, err
+		}
+		if p.AmountSign == ExpensesNegative {
+			amount = -amount
+		}
+		r.amount = amount
+		return r, nil
+	}
+	debit, err := p.parseAmount(field(cols.debit))
+	if err != nil {
+		return row{}, err
+	}
+	credit, err := p.parseAmount(field(cols.credit))
+	if err != nil {
+		return row{}, err
+	}
+	// Some exports put negative numbers in the debit column, so only the column tells the direction.
+	r.amount = abs(debit) - abs(credit)
+	return r, nil
+}
+
+// amountReplacer strips currency symbols and whitespace from amounts.
+var amountReplacer = strings.NewReplacer("$", "", "€", "", "£", "", "¥", "", " ", "", " ", "")
+
+// parseAmount parses an amount such as 1,234.56, -12.00, (12.00) or 1.234,56 with a comma decimal separator.
+// An empty amount is zero.
+func (p *Profile) parseAmount(s string) (types.Money, error) {
+	orig := s
+	s = amountReplacer.Replace(s)
+	if s == "" {
+		return 0, nil
+	}
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative, s = true, s[1:len(s)-1]
+	}
+	thousands := ","
+	if p.DecimalSeparator == "," {
+		thousands = "."
+	}
+	s = strings.ReplaceAll(s, thousands, "")
+	s = strings.Replace(s, p.DecimalSeparator, ".", 1)
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", orig)
+	}
+	if negative {
+		f = -f
+	}
+	return types.Money(math.Round(f * types.FACTOR)), nil
+}
+
+func abs(m types.Money) types.Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
@@ -0,0 +1,62 @@
+package csv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func TestValidate_Defaults(t *testing.T) {
+	p := Profile{Name: "bank", Files: "*.csv", DateColumn: "Date", AmountColumn: "Amount", PayeeColumn: "Payee"}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	if p.Delimiter != "," || p.DateFormat != "2006-01-02" || p.AmountSign != ExpensesNegative || p.DecimalSeparator != "." {
+		t.Errorf("profile = %+v; want defaults filled in", p)
+	}
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	p := Profile{Files: "[", AmountColumn: "Amount", DebitColumn: "Debit", AmountSign: "up", Delimiter: ";;"}
+	err := p.Validate()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, field := range []string{"name", "files", "delimiter", "date_column", "amount_column", "amount_sign", "payee_column"} {
+		if !strings.Contains(err.Error(), field+":") {
+			t.Errorf("error %q does not mention %s", err, field)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	period := Profile{DecimalSeparator: "."}
+	for in, want := range map[string]types.Money{
+		"-12.34": -123400, "$1,234.56": 12345600, "(7.00)": -70000, "": 0, " 3 ": 30000,
+	} {
+		got, err := period.parseAmount(in)
+		if err != nil || got != want {
+			t.Errorf("parseAmount(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	comma := Profile{DecimalSeparator: ","}
+	if got, err := comma.parseAmount("-1.234,5 €"); err != nil || got != -12345000 {
+		t.Errorf("parseAmount with comma separator = %d, %v; want -1234.50", got, err)
+	}
+	if _, err := period.parseAmount("twelve"); err == nil {
+		t.Error("expected error for invalid amount")
+	}
+}
+
+func TestParseRow_AmountSign(t *testing.T) {
+	cols := columns{date: 0, amount: 1, debit: -1, credit: -1, payee: 2, category: -1, memo: -1}
+	record := []string{"2024-01-10", "-5.00", "Jita"}
+	for sign, want := range map[AmountSign]types.Money{ExpensesNegative: 50000, ExpensesPositive: -50000} {
+		p := Profile{DateFormat: "2006-01-02", AmountSign: sign, DecimalSeparator: "."}
+		r, err := p.parseRow(cols, record)
+		if err != nil || r.amount != want {
+			t.Errorf("%s: amount = %d, %v; want %d", sign, r.amount, err, want)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+// Package csv implements a data source that reads transactions from CSV exports of banks and credit card
+// issuers. Since every institution lays out its exports differently, files are read according to column
+// mapping profiles.
+package csv
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/rules"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Source reads transactions from the CSV files in a directory and its subdirectories, using the first profile
+// whose pattern matches each file. Files are re-read on every call, so exports added to the directory are picked
+// up without a restart.
+type Source struct {
+	dir        string
+	profiles   []Profile
+	categories *rules.Rules
+}
+
+// NewSource creates a Source that reads exports from dir according to profiles, which must have been validated.
+// Transactions without a category column value are assigned to categories by payee according to categories.
+func NewSource(dir string, profiles []Profile, categories *rules.Rules) *Source {
+	return &Source{dir: dir, profiles: profiles, categories: categories}
+}
+
+// export is the content of a CSV file read with a profile.
+type export struct {
+	path    string
+	profile *Profile
+	rows    []row
+}
+
+// GetCategorizedTransactions implements ds.GetCategorizedTransactionsFunc. Money leaving the account is an
+// expense and money entering it is income, unless its category is one of the ignored categories of the profile.
+//
+// Exports of the same profile often overlap, so rows are deduplicated by a hash of their content: a row is
+// included as many times as it occurs in the single file where it occurs the most, which keeps genuinely
+// repeated transactions, such as two identical purchases on the same day, while dropping the copies of
+// overlapping exports.
+func (s *Source) GetCategorizedTransactions(ctx context.Context, interval ds.DateRange) (result *types.Categories, err error) {
+	exports, err := s.readExports(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(ctx, "csv.categorize", attribute.Int("files", len(exports)))
+	defer func() { tracing.End(span, err) }()
+
+	result = types.NewCategories()
+	included := make(map[string]int)
+	duplicates := 0
+	for _, exp := range exports {
+		occurrences := make(map[string]int)
+		for _, r := range exp.rows {
+			if r.date.Before(interval.StartDate) || interval.EndDate.Before(r.date) {
+				continue
+			}
+			hash := r.hash(exp.profile.Name)
+			occurrences[hash]++
+			if occurrences[hash] <= included[hash] {
+				duplicates++
+				continue
+			}
+			included[hash]++
+
+			if err := s.categorize(result, exp.profile, r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	logging.FromContext(ctx).DebugContext(ctx, "Categorized CSV transactions",
+		"transactions", result.TransactionCount(), "duplicates", duplicates)
+	return result, nil
+}
+
+// categorize adds the transaction of a row to result, in the category given by the export if there is one.
+func (s *Source) categorize(result *types.Categories, profile *Profile, r row) error {
+	tx := types.NewTransaction(r.date, r.payee, r.amount)
+	tx.Description = r.memo
+	if profile.Account != "" {
+		tx.Annotate("account", profile.Account)
+	}
+
+	bucket := result.Expenses
+	if r.amount < 0 {
+		bucket = result.Income
+	}
+	for _, ignored := range profile.IgnoredCategories {
+		if r.category != "" && strings.EqualFold(r.category, ignored) {
+			bucket = result.Ignored
+			break
+		}
+	}
+	if path := rules.SplitPath(r.category); len(path) > 0 {
+		return bucket.GetOrCreatePath(path...).AddTransaction(tx)
+	}
+	return s.categories.Categorize(bucket, tx)
+}
+
+// Check implements ds.CheckFunc by verifying that the export directory can be read.
+func (s *Source) Check(ctx context.Context) error {
+	_, err := os.ReadDir(s.dir)
+	if err != nil {
+		return ds.Errorf(ds.ErrUpstreamUnavailable, "failed to read CSV export directory: %w", err).
+			WithHint("the export directory configured on the server cannot be read; ask the user to fix it")
+	}
+	return nil
+}
+
+// readExports reads every CSV file in the export directory that matches a profile, in lexical order of paths.
+func (s *Source) readExports(ctx context.Context) (exports []*export, err error) {
+	ctx, span := tracing.Start(ctx, "csv.read")
+	defer func() { tracing.End(span, err) }()
+
+	logger := logging.FromContext(ctx)
+	rows := 0
+	err = filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".csv") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		profile := s.profile(rel)
+		if profile == nil {
+			logger.WarnContext(ctx, "Skipping CSV file that matches no profile", "file", rel)
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		parsed, err := profile.read(f)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s with profile %s: %w", rel, profile.Name, err)
+		}
+		rows += len(parsed)
+		exports = append(exports, &export{path: rel, profile: profile, rows: parsed})
+		return nil
+	})
+	if err != nil {
+		return nil, ds.Errorf(ds.ErrUpstreamUnavailable, "failed to read CSV exports: %w", err).
+			WithHint("the export files on the server cannot be read; ask the user to fix them")
+	}
+	sort.Slice(exports, func(i, j int) bool { return exports[i].path < exports[j].path })
+	span.SetAttributes(attribute.Int("files", len(exports)), attribute.Int("rows", rows))
+	logger.DebugContext(ctx, "Read CSV exports", "files", len(exports), "rows", rows)
+	return exports, nil
+}
+
+// profile returns the first profile matching the file at path, relative to the export directory, or nil.
+func (s *Source) profile(path string) *Profile {
+	for i := range s.profiles {
+		if s.profiles[i].matches(path) {
+			return &s.profiles[i]
+		}
+	}
+	return nil
+}
+
+// read parses the rows of an export. Blank lines are skipped, and so is a leading byte order mark.
+func (p *Profile) read(r io.Reader) ([]row, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	for range p.SkipRows {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			return nil, nil
+		}
+		data = data[i+1:]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = []rune(p.Delimiter)[0]
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cols, err := p.resolveColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isBlank(record) {
+			continue
+		}
+		r, err := p.parseRow(cols, record)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: %w", line+p.SkipRows, err)
+		}
+		rows = append(rows, r)
+	}
+}
+
+// hash identifies the content of a row within the exports of a profile.
+func (r row) hash(profile string) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%s\x00%d\x00%s\x00%s\x00%s",
+		profile, r.date, r.amount, r.payee, r.category, r.memo))
+	return hex.EncodeToString(sum[:])
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+package csv
+
+import (
+	"context"
+	"testing"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/rules"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func mustDate(t *testing.T, s string) types.Date {
+	t.Helper()
+	d, err := types.ParseDate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func testProfiles(t *testing.T) []Profile {
+	t.Helper()
+	profiles := []Profile{
+		{
+			Name:         "bank",
+			Files:        "bank/*.csv",
+			Account:      "Checking",
+			DateColumn:   "Date",
+			DateFormat:   "01/02/2006",
+			AmountColumn: "Amount",
+			PayeeColumn:  "Description",
+			MemoColumn:   "Memo",
+		},
+		{
+			Name:              "card",
+			Files:             "card/*.csv",
+			Delimiter:         ";",
+			SkipRows:          1,
+			DateColumn:        "Transaction Date",
+			DebitColumn:       "Debit",
+			CreditColumn:      "Credit",
+			DecimalSeparator:  ",",
+			PayeeColumn:       "Payee",
+			CategoryColumn:    "Category",
+			IgnoredCategories: []string{"payment"},
+		},
+	}
+	for i := range profiles {
+		if err := profiles[i].Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return profiles
+}
+
+func TestGetCategorizedTransactions(t *testing.T) {
+	categories, err := rules.Compile([]rules.Rule{
+		{Payee: "netflix", Category: "Subscriptions/Streaming"},
+		{Payee: "payroll", Category: "Bounties"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := NewSource("testdata", testProfiles(t), categories)
+	interval := ds.DateRange{StartDate: mustDate(t, "2024-01-01"), EndDate: mustDate(t, "2024-02-29")}
+
+	result, err := src.GetCategorizedTransactions(context.Background(), interval)
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+
+	bounties := result.Income.FindSubcategory("Bounties")
+	if bounties == nil || bounties.TotalAmount != -50000000 {
+		t.Errorf("Income/Bounties = %+v; want credit of 5000.00 as negative amount", bounties)
+	}
+	if got := bounties.Transactions[0].Annotations["account"]; got != "Checking" {
+		t.Errorf("account annotation = %q; want Checking", got)
+	}
+	streaming := result.Expenses.GetOrCreatePath("Subscriptions", "Streaming")
+	if len(streaming.Transactions) != 1 || streaming.TotalAmount != 159900 {
+		t.Errorf("Subscriptions/Streaming = %+v; want Netflix with parenthesized amount as expense", streaming)
+	}
+	ships := result.Expenses.GetOrCreatePath("Shopping", "Ships")
+	if len(ships.Transactions) != 1 || ships.TotalAmount != 12340000 {
+		t.Errorf("Shopping/Ships = %+v; want debit of 1234.00 in category from the export", ships)
+	}
+	if got := result.Ignored.TransactionCount(); got != 1 {
+		t.Errorf("Ignored transactions = %d; want the card payment", got)
+	}
+	if unc := result.Income.FindSubcategory(types.UncategorizedName); unc == nil || unc.TotalAmount != -100000 {
+		t.Errorf("Income/Uncategorized = %+v; want refund credit", unc)
+	}
+
+	unc := result.Expenses.FindSubcategory(types.UncategorizedName)
+	if unc == nil || len(unc.Transactions) != 3 {
+		t.Fatalf("Expenses/Uncategorized = %+v; want Jita once and both coffees from overlapping exports", unc)
+	}
+	if got := unc.Transactions[0].Description; got != "Ammo & fuel" {
+		t.Errorf("Description = %q; want memo", got)
+	}
+}
+
+func TestGetCategorizedTransactions_FiltersByDate(t *testing.T) {
+	src := NewSource("testdata", testProfiles(t), nil)
+	interval := ds.DateRange{StartDate: mustDate(t, "2024-02-01"), EndDate: mustDate(t, "2024-02-11")}
+
+	result, err := src.GetCategorizedTransactions(context.Background(), interval)
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+	if got := result.TransactionCount(); got != 1 {
+		t.Errorf("TransactionCount = %d; want only the transaction of 2024-02-10", got)
+	}
+}
+
+func TestGetCategorizedTransactions_MissingColumn(t *testing.T) {
+	profiles := testProfiles(t)
+	profiles[0].MemoColumn = "Notes"
+	src := NewSource("testdata", profiles, nil)
+	interval := ds.DateRange{StartDate: mustDate(t, "2024-01-01"), EndDate: mustDate(t, "2024-02-29")}
+
+	if _, err := src.GetCategorizedTransactions(context.Background(), interval); err == nil {
+		t.Error("expected error for a column missing from the header")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := NewSource("testdata", nil, nil).Check(context.Background()); err != nil {
+		t.Errorf("Check error: %v", err)
+	}
+	if err := NewSource("testdata/missing", nil, nil).Check(context.Background()); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
@@ -0,0 +1,166 @@
+package datasource
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrorKind classifies a data source failure. Every ErrorKind is itself an error, so callers can test
+// for a particular class of failure with errors.Is(err, ErrRateLimited) regardless of how deeply it was wrapped.
+type ErrorKind string
+
+const (
+	// ErrUnauthorized indicates that the upstream rejected the configured credentials.
+	ErrUnauthorized ErrorKind = "unauthorized"
+	// ErrRateLimited indicates that the upstream is throttling requests.
+	ErrRateLimited ErrorKind = "rate limited"
+	// ErrRangeTooLarge indicates that the requested date range yields more data than can be returned at once.
+	ErrRangeTooLarge ErrorKind = "range too large"
+	// ErrNotConfigured indicates that the data source required to serve the request is not configured.
+	ErrNotConfigured ErrorKind = "not configured"
+	// ErrUpstreamUnavailable indicates that the upstream could not be reached or failed to serve the request.
+	ErrUpstreamUnavailable ErrorKind = "upstream unavailable"
+	// ErrInvalidInput indicates that the request itself is malformed and will never succeed as is.
+	ErrInvalidInput ErrorKind = "invalid input"
+)
+
+// Error implements the error interface.
+func (k ErrorKind) Error() string {
+	return string(k)
+}
+
+// Retryable reports whether a request that failed with this kind of error may succeed if repeated unchanged.
+func (k ErrorKind) Retryable() bool {
+	return k == ErrRateLimited || k == ErrUpstreamUnavailable
+}
+
+// Hint returns the default suggestion on how to recover from this kind of error.
+func (k ErrorKind) Hint() string {
+	switch k {
+	case ErrUnauthorized:
+		return "the data source credentials configured on the server are invalid or expired; ask the user to fix them"
+	case ErrRateLimited:
+		return "wait before retrying"
+	case ErrRangeTooLarge:
+		return "narrow the date range"
+	case ErrNotConfigured:
+		return "this data source is not configured on the server; do not retry"
+	case ErrUpstreamUnavailable:
+		return "retry in a few moments"
+	case ErrInvalidInput:
+		return "fix the tool arguments before retrying"
+	default:
+		return ""
+	}
+}
+
+// Error is a classified data source error. It wraps the underlying cause together with its ErrorKind
+// and optional recovery information that can be relayed to the caller.
+type Error struct {
+	// Kind is the class of the failure.
+	Kind ErrorKind
+	// Hint is a suggestion on how to recover; defaults to Kind.Hint() when empty.
+	Hint string
+	// RetryAfter is how long the caller should wait before retrying, if known.
+	RetryAfter time.Duration
+	// Err is the underlying cause.
+	Err error
+}
+
+// NewError wraps err into an Error of the specified kind.
+func NewError(kind ErrorKind, err error) *Error {
+	return &Error{Kind: kind, Err: err}
+}
+
+// Errorf formats an error message according to the format specifier and wraps it into an Error of the specified kind.
+func Errorf(kind ErrorKind, format string, args ...any) *Error {
+	return NewError(kind, fmt.Errorf(format, args...))
+}
+
+// WithHint overrides the default recovery hint of the error.
+func (e *Error) WithHint(hint string) *Error {
+	e.Hint = hint
+	return e
+}
+
+// WithRetryAfter sets how long the caller should wait before retrying.
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	e.RetryAfter = d
+	return e
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Kind.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Kind, e.Err)
+}
+
+// Unwrap exposes both the kind and the underlying cause to errors.Is and errors.As.
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// Retryable reports whether the failed request may succeed if repeated unchanged.
+func (e *Error) Retryable() bool {
+	return e.Kind.Retryable()
+}
+
+// RecoveryHint returns the hint set on the error, falling back to the default hint of its kind.
+func (e *Error) RecoveryHint() string {
+	if e.Hint != "" {
+		return e.Hint
+	}
+	return e.Kind.Hint()
+}
+
+// AsError extracts the classified Error from the error chain of err. It returns nil if err is not classified.
+func AsError(err error) *Error {
+	var dsErr *Error
+	if errors.As(err, &dsErr) {
+		return dsErr
+	}
+	return nil
+}
+
+// ErrorFromHTTPStatus classifies err, which resulted from an upstream HTTP response with the given status code.
+// The value of the Retry-After header, if any, is parsed into Error.RetryAfter. Status codes that do not map
+// onto any ErrorKind leave err unchanged.
+func ErrorFromHTTPStatus(status int, retryAfter string, err error) error {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return NewError(ErrUnauthorized, err)
+	case status == http.StatusTooManyRequests:
+		return NewError(ErrRateLimited, err).WithRetryAfter(parseRetryAfter(retryAfter))
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		return NewError(ErrInvalidInput, err)
+	case status >= 500:
+		return NewError(ErrUpstreamUnavailable, err).WithRetryAfter(parseRetryAfter(retryAfter))
+	default:
+		return err
+	}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+// It returns zero if the value is empty or malformed.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d.Round(time.Second)
+		}
+	}
+	return 0
+}
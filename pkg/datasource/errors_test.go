@@ -0,0 +1,104 @@
+package datasource
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestError_IsKind(t *testing.T) {
+	cause := errors.New("bad status 401")
+	err := fmt.Errorf("failed to call API: %w", NewError(ErrUnauthorized, cause))
+
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("errors.Is(err, ErrUnauthorized) = false; want true")
+	}
+	if errors.Is(err, ErrRateLimited) {
+		t.Errorf("errors.Is(err, ErrRateLimited) = true; want false")
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("errors.Is(err, cause) = false; want true")
+	}
+	if got, want := err.Error(), "failed to call API: unauthorized: bad status 401"; got != want {
+		t.Errorf("Error() = %q; want %q", got, want)
+	}
+}
+
+func TestAsError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", Errorf(ErrRangeTooLarge, "too many transactions"))
+	dsErr := AsError(err)
+	if dsErr == nil {
+		t.Fatal("AsError returned nil; want classified error")
+	}
+	if dsErr.Kind != ErrRangeTooLarge {
+		t.Errorf("Kind = %q; want %q", dsErr.Kind, ErrRangeTooLarge)
+	}
+	if dsErr.RecoveryHint() != "narrow the date range" {
+		t.Errorf("RecoveryHint() = %q; want default hint", dsErr.RecoveryHint())
+	}
+	if AsError(errors.New("plain")) != nil {
+		t.Errorf("AsError(plain) != nil; want nil")
+	}
+}
+
+func TestError_WithHint(t *testing.T) {
+	err := NewError(ErrInvalidInput, nil).WithHint("use YYYY-MM-DD")
+	if err.RecoveryHint() != "use YYYY-MM-DD" {
+		t.Errorf("RecoveryHint() = %q; want override", err.RecoveryHint())
+	}
+	if err.Error() != "invalid input" {
+		t.Errorf("Error() = %q; want %q", err.Error(), "invalid input")
+	}
+}
+
+func TestErrorKind_Retryable(t *testing.T) {
+	cases := map[ErrorKind]bool{
+		ErrUnauthorized:        false,
+		ErrRateLimited:         true,
+		ErrRangeTooLarge:       false,
+		ErrNotConfigured:       false,
+		ErrUpstreamUnavailable: true,
+		ErrInvalidInput:        false,
+	}
+	for kind, want := range cases {
+		if got := kind.Retryable(); got != want {
+			t.Errorf("%q.Retryable() = %v; want %v", kind, got, want)
+		}
+	}
+}
+
+func TestErrorFromHTTPStatus(t *testing.T) {
+	cause := errors.New("boom")
+	cases := []struct {
+		status int
+		want   ErrorKind
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusBadRequest, ErrInvalidInput},
+		{http.StatusBadGateway, ErrUpstreamUnavailable},
+	}
+	for _, c := range cases {
+		err := ErrorFromHTTPStatus(c.status, "", cause)
+		if !errors.Is(err, c.want) {
+			t.Errorf("status %d: err = %v; want kind %q", c.status, err, c.want)
+		}
+	}
+	if err := ErrorFromHTTPStatus(http.StatusNotFound, "", cause); err != cause {
+		t.Errorf("status 404: err = %v; want cause unchanged", err)
+	}
+}
+
+func TestErrorFromHTTPStatus_RetryAfter(t *testing.T) {
+	err := AsError(ErrorFromHTTPStatus(http.StatusTooManyRequests, "30", errors.New("slow down")))
+	if err == nil || err.RetryAfter != 30*time.Second {
+		t.Errorf("err = %+v; want RetryAfter 30s", err)
+	}
+	err = AsError(ErrorFromHTTPStatus(http.StatusTooManyRequests, "garbage", errors.New("slow down")))
+	if err == nil || err.RetryAfter != 0 {
+		t.Errorf("err = %+v; want RetryAfter 0", err)
+	}
+}
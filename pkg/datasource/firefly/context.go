@@ -0,0 +1,29 @@
+package firefly
+
+import (
+	"context"
+	"net/http"
+
+	ffapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/firefly"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/secrets"
+)
+
+// InjectCredentials returns an HTTP context injector function that injects a client of the Firefly III instance
+// at baseUrl, configured with the given access token and client options, into the context of incoming HTTP
+// requests. The current value of the token is used for every request, so rotated tokens take effect immediately.
+func InjectCredentials(baseUrl string, token *secrets.Secret, opts ...ffapi.Option) func(ctx context.Context, req *http.Request) context.Context {
+	return func(ctx context.Context, req *http.Request) context.Context {
+		return ffapi.WithFireflyCredentials(baseUrl, token.Value(), opts...)(ctx, req)
+	}
+}
+
+// clientFromContext returns the Firefly III client injected by InjectCredentials, or an ErrNotConfigured error if
+// there is none.
+func clientFromContext(ctx context.Context) (ffapi.Client, error) {
+	client, ok := ffapi.FromContext(ctx)
+	if !ok {
+		return nil, ds.Errorf(ds.ErrNotConfigured, "no Firefly III client in the request context")
+	}
+	return client, nil
+}
@@ -0,0 +1,112 @@
+package firefly
+
+import (
+	"context"
+	"fmt"
+
+	ffapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/firefly"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetPortfolio implements ds.GetPortfolioFunc. Active asset and liability accounts that are included in the net
+// worth become positions valued at their current balance. Credit cards with a negative balance are debts, and
+// liabilities owed to the user are assets. Balances in other currencies than the base currency are included with
+// a zero value and a note, so that they are not silently lost.
+func (s *Source) GetPortfolio(ctx context.Context) (*types.Portfolio, error) {
+	client, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assets, liabilities, err := fetchAccounts(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(ctx, "firefly.transform",
+		attribute.Int("assets", len(assets)), attribute.Int("liabilities", len(liabilities)))
+	defer span.End()
+
+	portfolio := types.NewPortfolio()
+	for _, acct := range assets {
+		if !acct.Active || !acct.IncludeNetWorth {
+			continue
+		}
+		balance, priced := s.balance(ctx, acct)
+		if acct.AccountRole == "ccAsset" && balance < 0 {
+			debt := types.NewDebtPosition(acct.Name, "credit card", -balance)
+			annotateAccount(&debt.Position, acct, priced)
+			portfolio.AddDebt(debt)
+			continue
+		}
+		asset := types.NewAssetPosition(acct.Name, "", "cash", "", balance)
+		annotateAccount(&asset.Position, acct, priced)
+		portfolio.AddAsset(asset)
+	}
+	for _, acct := range liabilities {
+		if !acct.Active || !acct.IncludeNetWorth {
+			continue
+		}
+		balance, priced := s.balance(ctx, acct)
+		if balance < 0 {
+			balance = -balance
+		}
+		if acct.LiabilityDirection == "credit" {
+			asset := types.NewAssetPosition(acct.Name, "", "receivable", "", balance)
+			annotateAccount(&asset.Position, acct, priced)
+			portfolio.AddAsset(asset)
+			continue
+		}
+		liabilityType := acct.LiabilityType
+		if liabilityType == "" {
+			liabilityType = "liability"
+		}
+		debt := types.NewDebtPosition(acct.Name, liabilityType, balance)
+		annotateAccount(&debt.Position, acct, priced)
+		portfolio.AddDebt(debt)
+	}
+	portfolio.Sort()
+	logging.FromContext(ctx).DebugContext(ctx, "Fetched Firefly III portfolio",
+		"assets", len(portfolio.Assets), "debts", len(portfolio.Debts))
+
+	return portfolio, nil
+}
+
+// fetchAccounts retrieves the asset and liability accounts from Firefly III.
+func fetchAccounts(ctx context.Context, client ffapi.Client) (assets, liabilities []*ffapi.Account, err error) {
+	ctx, span := tracing.Start(ctx, "firefly.fetch")
+	defer func() { tracing.End(span, err) }()
+
+	if assets, err = client.ListAccounts(ctx, ffapi.AssetAccount); err != nil {
+		return nil, nil, err
+	}
+	if liabilities, err = client.ListAccounts(ctx, ffapi.LiabilityAccount); err != nil {
+		return nil, nil, err
+	}
+	return assets, liabilities, nil
+}
+
+// balance returns the current balance of an account in the base currency, and whether it could be determined.
+func (s *Source) balance(ctx context.Context, acct *ffapi.Account) (types.Money, bool) {
+	if acct.CurrencyCode != "" && acct.CurrencyCode != s.base {
+		logging.FromContext(ctx).WarnContext(ctx, "Account is not in the base currency",
+			"account", acct.Name, "currency", acct.CurrencyCode)
+		return 0, false
+	}
+	return acct.CurrentBalance, true
+}
+
+// annotateAccount copies the notes of the account it was made from into a position and annotates it with the
+// role of the account.
+func annotateAccount(pos *types.Position, acct *ffapi.Account, priced bool) {
+	pos.Description = acct.Notes
+	if acct.AccountRole != "" {
+		pos.Annotate("account_role", acct.AccountRole)
+	}
+	if !priced {
+		balance := float64(acct.CurrentBalance) / types.FACTOR
+		pos.Annotate("note", fmt.Sprintf("balance of %.2f %s is not converted to the base currency", balance, acct.CurrencyCode))
+	}
+}
@@ -0,0 +1,55 @@
+package firefly
+
+import (
+	"testing"
+
+	ffapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/firefly"
+)
+
+func TestGetPortfolio(t *testing.T) {
+	client := &fakeClient{
+		assets: []*ffapi.Account{
+			{Id: "1", Name: "Checking", AccountRole: "defaultAsset", Active: true, IncludeNetWorth: true,
+				CurrencyCode: "USD", CurrentBalance: money(1500), Notes: "Main account"},
+			{Id: "2", Name: "Card", AccountRole: "ccAsset", Active: true, IncludeNetWorth: true,
+				CurrencyCode: "USD", CurrentBalance: money(-300)},
+			{Id: "3", Name: "Euro savings", AccountRole: "savingAsset", Active: true, IncludeNetWorth: true,
+				CurrencyCode: "EUR", CurrentBalance: money(1000)},
+			{Id: "4", Name: "Closed", Active: false, IncludeNetWorth: true, CurrencyCode: "USD", CurrentBalance: money(5)},
+			{Id: "5", Name: "Excluded", Active: true, IncludeNetWorth: false, CurrencyCode: "USD", CurrentBalance: money(5)},
+		},
+		liabilities: []*ffapi.Account{
+			{Id: "6", Name: "Mortgage", Active: true, IncludeNetWorth: true, CurrencyCode: "USD",
+				CurrentBalance: money(-200000), LiabilityType: "mortgage", LiabilityDirection: "debit"},
+			{Id: "7", Name: "Loan to Sam", Active: true, IncludeNetWorth: true, CurrencyCode: "USD",
+				CurrentBalance: money(250), LiabilityType: "loan", LiabilityDirection: "credit"},
+		},
+	}
+
+	portfolio, err := NewSource("USD").GetPortfolio(contextWithClient(client))
+	if err != nil {
+		t.Fatalf("GetPortfolio error: %v", err)
+	}
+	if len(portfolio.Assets) != 3 || len(portfolio.Debts) != 2 {
+		t.Fatalf("portfolio = %+v; want 3 assets and 2 debts", portfolio)
+	}
+	checking := portfolio.Assets[0]
+	if checking.Name != "Checking" || checking.Value != money(1500) || checking.Type != "cash" || checking.Description != "Main account" {
+		t.Errorf("first asset = %+v; want checking", checking)
+	}
+	if loan := portfolio.Assets[1]; loan.Name != "Loan to Sam" || loan.Type != "receivable" || loan.Value != money(250) {
+		t.Errorf("second asset = %+v; want the loan owed to the user", loan)
+	}
+	if eur := portfolio.Assets[2]; eur.Name != "Euro savings" || eur.Value != 0 || eur.Annotations["note"] == "" {
+		t.Errorf("third asset = %+v; want euro savings without a value and a note", eur)
+	}
+	if mortgage := portfolio.Debts[0]; mortgage.Type != "mortgage" || mortgage.Value != money(200000) {
+		t.Errorf("first debt = %+v; want the mortgage", mortgage)
+	}
+	if card := portfolio.Debts[1]; card.Type != "credit card" || card.Value != money(300) {
+		t.Errorf("second debt = %+v; want the credit card", card)
+	}
+	if portfolio.NetWorth != money(1500+250-200000-300) {
+		t.Errorf("net worth = %d", portfolio.NetWorth)
+	}
+}
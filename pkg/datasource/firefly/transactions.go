@@ -0,0 +1,210 @@
+// Package firefly implements a data source that fetches transactions and the balances of asset and liability
+// accounts from a Firefly III instance.
+package firefly
+
+import (
+	"context"
+	"fmt"
+
+	ffapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/firefly"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Source fetches data from Firefly III with the client in the request context.
+type Source struct {
+	base string
+}
+
+// NewSource creates a Source that reports amounts in baseCurrency. Amounts in other currencies are converted with
+// the foreign amounts Firefly III keeps, when they are in the base currency.
+func NewSource(baseCurrency string) *Source {
+	return &Source{base: baseCurrency}
+}
+
+// CheckCredentials verifies that Firefly III is reachable and accepts the access token by listing tags,
+// which is the cheapest authenticated call.
+var CheckCredentials ds.CheckFunc = func(ctx context.Context) error {
+	client, err := clientFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.ListTags(ctx)
+	return err
+}
+
+// GetCategorizedTransactions implements ds.GetCategorizedTransactionsFunc. Every split of a transaction becomes a
+// transaction of its own. Withdrawals are expenses, deposits are income unless they refund an expense, and
+// transfers, opening balances and reconciliations are ignored. Tags become annotations.
+func (s *Source) GetCategorizedTransactions(ctx context.Context, interval ds.DateRange) (*types.Categories, error) {
+	client, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cats, tags, groups, err := fetchTransactions(ctx, client, interval)
+	if err != nil {
+		return nil, err
+	}
+	return s.categorizeTransactions(ctx, cats, tags, groups)
+}
+
+// fetchTransactions retrieves categories, tags and the transactions within interval from Firefly III.
+func fetchTransactions(ctx context.Context, client ffapi.Client, interval ds.DateRange) (
+	cats []*ffapi.Category, tags []*ffapi.Tag, groups []*ffapi.TransactionGroup, err error,
+) {
+	ctx, span := tracing.Start(ctx, "firefly.fetch")
+	defer func() { tracing.End(span, err) }()
+
+	if cats, err = client.ListCategories(ctx); err != nil {
+		return nil, nil, nil, err
+	}
+	if tags, err = client.ListTags(ctx); err != nil {
+		return nil, nil, nil, err
+	}
+	if groups, err = client.ListTransactions(ctx, interval.StartDate.String(), interval.EndDate.String()); err != nil {
+		return nil, nil, nil, err
+	}
+	return cats, tags, groups, nil
+}
+
+// categorizeTransactions converts Firefly III transactions into domain types and sorts them into category trees.
+func (s *Source) categorizeTransactions(ctx context.Context, cats []*ffapi.Category, tags []*ffapi.Tag, groups []*ffapi.TransactionGroup) (
+	result *types.Categories, err error,
+) {
+	ctx, span := tracing.Start(ctx, "firefly.categorize", attribute.Int("transactions", len(groups)))
+	defer func() { tracing.End(span, err) }()
+
+	catsById := make(map[string]*ffapi.Category, len(cats))
+	for _, cat := range cats {
+		catsById[cat.Id] = cat
+	}
+	// Splits refer to tags by name
+	tagsByName := make(map[string]*ffapi.Tag, len(tags))
+	for _, tag := range tags {
+		tagsByName[tag.Tag] = tag
+	}
+
+	result = types.NewCategories()
+	logger := logging.FromContext(ctx)
+	for _, group := range groups {
+		for _, split := range group.Transactions {
+			tx, err := s.buildTransaction(ctx, split)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range split.Tags {
+				tag, ok := tagsByName[name]
+				if !ok {
+					logger.WarnContext(ctx, "Missing tag from Firefly III response", "tag", name)
+					continue
+				}
+				addTransactionTag(tx, tag)
+			}
+
+			// Determine which "bucket" does the transaction fall under
+			var bucket *types.Category
+			switch {
+			case split.Type == ffapi.Withdrawal:
+				bucket = result.Expenses
+			case split.Type == ffapi.Deposit && split.SourceType == ffapi.ExpenseAccountType:
+				bucket = result.Expenses
+			case split.Type == ffapi.Deposit:
+				bucket = result.Income
+			default:
+				bucket = result.Ignored
+			}
+
+			switch cat, ok := catsById[split.CategoryId]; {
+			case split.CategoryId == "":
+				bucket = getOrCreateCategoryByName(bucket, types.UncategorizedName)
+			case !ok:
+				logger.WarnContext(ctx, "Missing category from Firefly III response", "category_id", split.CategoryId)
+				tx.Annotate("category_error", "uncategorized due to invalid category id")
+				bucket = getOrCreateCategoryByName(bucket, types.UncategorizedName)
+			default:
+				bucket = getOrCreateCategory(bucket, cat)
+			}
+			if err := bucket.AddTransaction(tx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// buildTransaction converts a split into a domain Transaction. The payee is the expense account of withdrawals
+// and the revenue account of deposits, while the other side is annotated as the account.
+func (s *Source) buildTransaction(ctx context.Context, split *ffapi.Split) (*types.Transaction, error) {
+	if len(split.Date) < len("2006-01-02") {
+		return nil, fmt.Errorf("invalid date %q of transaction %s", split.Date, split.JournalId)
+	}
+	date, err := types.ParseDate(split.Date[:len("2006-01-02")])
+	if err != nil {
+		return nil, err
+	}
+
+	amount, currency := split.Amount, ""
+	switch {
+	case split.CurrencyCode == "" || split.CurrencyCode == s.base:
+	case split.ForeignAmount != nil && split.ForeignCurrencyCode == s.base:
+		amount = *split.ForeignAmount
+	default:
+		// Neither amount is in the base currency, keep the original one
+		currency = split.CurrencyCode
+		logging.FromContext(ctx).WarnContext(ctx, "Transaction is not in the base currency",
+			"transaction_journal_id", split.JournalId, "currency", split.CurrencyCode)
+	}
+
+	payee, account := split.DestinationName, split.SourceName
+	if split.Type == ffapi.Deposit {
+		// Money flows from the payer into an asset account
+		payee, account, amount = split.SourceName, split.DestinationName, -amount
+	}
+	tx := types.NewTransaction(date, payee, amount)
+	tx.Description = split.Description
+	if account != "" {
+		tx.Annotate("account", account)
+	}
+	if currency != "" {
+		tx.Annotate("currency", currency)
+	}
+	if split.BudgetName != "" {
+		tx.Annotate("budget", split.BudgetName)
+	}
+	if split.Notes != "" {
+		tx.Annotate("notes", split.Notes)
+	}
+	return tx, nil
+}
+
+// addTransactionTag annotates a Transaction with the given Firefly III Tag, using the same keys as the
+// other data sources.
+func addTransactionTag(tx *types.Transaction, tag *ffapi.Tag) {
+	key := fmt.Sprintf("tag:%s", tag.Id)
+	value := fmt.Sprintf("%s: %s", tag.Tag, tag.Description)
+	tx.Annotate(key, value)
+}
+
+// getOrCreateCategory finds or creates a subcategory under the parent Category matching the given Firefly III
+// Category. It also copies the notes from Firefly III as the description.
+func getOrCreateCategory(parent *types.Category, ffcat *ffapi.Category) *types.Category {
+	cat := getOrCreateCategoryByName(parent, ffcat.Name)
+	cat.Description = ffcat.Notes
+	return cat
+}
+
+// getOrCreateCategoryByName returns an existing subcategory by name under the parent,
+// or creates and attaches a new Category with that name if none exists.
+func getOrCreateCategoryByName(parent *types.Category, name string) *types.Category {
+	for _, sc := range parent.Subcategories {
+		if sc.Name == name {
+			return sc
+		}
+	}
+	newCat := types.NewCategory(name)
+	_ = parent.AddSubcategory(newCat)
+	return newCat
+}
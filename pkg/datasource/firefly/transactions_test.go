@@ -0,0 +1,120 @@
+package firefly
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	ffapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/firefly"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// fakeClient implements the Firefly III API client interface for testing.
+type fakeClient struct {
+	cats        []*ffapi.Category
+	tags        []*ffapi.Tag
+	groups      []*ffapi.TransactionGroup
+	assets      []*ffapi.Account
+	liabilities []*ffapi.Account
+}
+
+func (f *fakeClient) ListTransactions(ctx context.Context, startDate, endDate string) ([]*ffapi.TransactionGroup, error) {
+	return f.groups, nil
+}
+func (f *fakeClient) ListCategories(ctx context.Context) ([]*ffapi.Category, error) {
+	return f.cats, nil
+}
+func (f *fakeClient) ListBudgets(ctx context.Context) ([]*ffapi.Budget, error) {
+	return nil, nil
+}
+func (f *fakeClient) ListAccounts(ctx context.Context, accountType string) ([]*ffapi.Account, error) {
+	if accountType == ffapi.LiabilityAccount {
+		return f.liabilities, nil
+	}
+	return f.assets, nil
+}
+func (f *fakeClient) ListTags(ctx context.Context) ([]*ffapi.Tag, error) {
+	return f.tags, nil
+}
+
+// contextWithClient returns a context with the fake client injected.
+func contextWithClient(c ffapi.Client) context.Context {
+	return ffapi.WithFireflyClient(c)(context.Background(), &http.Request{})
+}
+
+func money(units float64) types.Money {
+	return types.Money(units * types.FACTOR)
+}
+
+func TestGetCategorizedTransactions(t *testing.T) {
+	foreign := money(55)
+	client := &fakeClient{
+		cats: []*ffapi.Category{{Id: "1", Name: "Groceries", Notes: "Food at home"}, {Id: "2", Name: "Salary"}},
+		tags: []*ffapi.Tag{{Id: "7", Tag: "vacation", Description: "Summer trip"}},
+		groups: []*ffapi.TransactionGroup{
+			{Id: "100", Transactions: []*ffapi.Split{{
+				JournalId: "101", Type: ffapi.Withdrawal, Date: "2024-01-05T00:00:00+01:00", Amount: money(12.5),
+				CurrencyCode: "USD", SourceName: "Checking", DestinationName: "Market", Description: "Weekly shop",
+				CategoryId: "1", BudgetName: "Living", Tags: []string{"vacation", "unknown"},
+			}}},
+			{Id: "200", GroupTitle: "Paycheck", Transactions: []*ffapi.Split{
+				{JournalId: "201", Type: ffapi.Deposit, Date: "2024-01-15T00:00:00+01:00", Amount: money(3000),
+					CurrencyCode: "USD", SourceName: "Employer", DestinationName: "Checking", CategoryId: "2"},
+				{JournalId: "202", Type: ffapi.Deposit, Date: "2024-01-15T00:00:00+01:00", Amount: money(20),
+					CurrencyCode: "USD", SourceName: "Market", SourceType: ffapi.ExpenseAccountType, DestinationName: "Checking", CategoryId: "1"},
+			}},
+			{Id: "300", Transactions: []*ffapi.Split{{
+				JournalId: "301", Type: ffapi.Transfer, Date: "2024-01-20", Amount: money(500),
+				CurrencyCode: "USD", SourceName: "Checking", DestinationName: "Savings",
+			}}},
+			{Id: "400", Transactions: []*ffapi.Split{{
+				JournalId: "401", Type: ffapi.Withdrawal, Date: "2024-01-21", Amount: money(50), CurrencyCode: "EUR",
+				ForeignAmount: &foreign, ForeignCurrencyCode: "USD", DestinationName: "Cafe", CategoryId: "9",
+			}}},
+			{Id: "500", Transactions: []*ffapi.Split{{
+				JournalId: "501", Type: ffapi.Withdrawal, Date: "2024-01-22", Amount: money(8), CurrencyCode: "GBP",
+				DestinationName: "Kiosk",
+			}}},
+		},
+	}
+
+	result, err := NewSource("USD").GetCategorizedTransactions(contextWithClient(client), ds.DateRange{})
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+
+	groceries := result.Expenses.Subcategories[0]
+	if groceries.Name != "Groceries" || groceries.Description != "Food at home" || len(groceries.Transactions) != 2 {
+		t.Fatalf("groceries = %+v; want the withdrawal and the refund", groceries)
+	}
+	tx := groceries.Transactions[0]
+	if tx.Amount != money(12.5) || tx.Payee != "Market" || tx.Description != "Weekly shop" || tx.Date.String() != "2024-01-05" {
+		t.Errorf("withdrawal = %+v", tx)
+	}
+	if tx.Annotations["tag:7"] != "vacation: Summer trip" || tx.Annotations["budget"] != "Living" || tx.Annotations["account"] != "Checking" {
+		t.Errorf("withdrawal annotations = %v", tx.Annotations)
+	}
+	if refund := groceries.Transactions[1]; refund.Amount != money(-20) || refund.Payee != "Market" {
+		t.Errorf("refund = %+v; want -20 from Market", refund)
+	}
+
+	salary := result.Income.Subcategories[0]
+	if salary.Name != "Salary" || salary.Transactions[0].Amount != money(-3000) || salary.Transactions[0].Payee != "Employer" {
+		t.Errorf("income = %+v; want salary from Employer", salary)
+	}
+	if ignored := result.Ignored.Subcategories[0]; ignored.Name != types.UncategorizedName || ignored.Transactions[0].Amount != money(500) {
+		t.Errorf("ignored = %+v; want the transfer", ignored)
+	}
+
+	unc := result.Expenses.Subcategories[1]
+	if unc.Name != types.UncategorizedName || len(unc.Transactions) != 2 {
+		t.Fatalf("uncategorized = %+v; want the cafe and kiosk", unc)
+	}
+	if cafe := unc.Transactions[0]; cafe.Amount != money(55) || cafe.Annotations["category_error"] == "" {
+		t.Errorf("cafe = %+v; want the foreign amount and a category error", cafe)
+	}
+	if kiosk := unc.Transactions[1]; kiosk.Amount != money(8) || kiosk.Annotations["currency"] != "GBP" {
+		t.Errorf("kiosk = %+v; want the original amount with its currency", kiosk)
+	}
+}
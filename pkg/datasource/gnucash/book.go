@@ -0,0 +1,202 @@
+package gnucash
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/prices"
+	"github.com/wyvernzora/personal-finance-mcp/internal/sqlite"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// commodity identifies a currency or security by its namespace, such as CURRENCY or NASDAQ, and its mnemonic.
+type commodity struct {
+	space string
+	id    string
+}
+
+// currency returns the commodity of a currency with the given ISO 4217 code.
+func currency(code string) commodity {
+	return commodity{space: "CURRENCY", id: code}
+}
+
+// newCommodity creates a commodity, normalizing the ISO4217 namespace of old books to CURRENCY.
+func newCommodity(space, id string) commodity {
+	if space == "ISO4217" {
+		space = "CURRENCY"
+	}
+	return commodity{space: space, id: id}
+}
+
+// String returns the namespace and mnemonic of the commodity, such as NASDAQ:VTI, which keys the price database.
+func (c commodity) String() string {
+	return c.space + ":" + c.id
+}
+
+// Account types of GnuCash.
+const (
+	typeRoot       = "ROOT"
+	typeBank       = "BANK"
+	typeCash       = "CASH"
+	typeAsset      = "ASSET"
+	typeStock      = "STOCK"
+	typeMutual     = "MUTUAL"
+	typeCurrency   = "CURRENCY"
+	typeReceivable = "RECEIVABLE"
+	typeCredit     = "CREDIT"
+	typeLiability  = "LIABILITY"
+	typePayable    = "PAYABLE"
+	typeIncome     = "INCOME"
+	typeExpense    = "EXPENSE"
+	typeEquity     = "EQUITY"
+)
+
+// account is an account of the book.
+type account struct {
+	guid        string
+	name        string
+	kind        string
+	commodity   commodity
+	parent      string
+	description string
+}
+
+// split is the part of a transaction that moves money into or out of one account. Debits are positive.
+type split struct {
+	account string
+	memo    string
+	// value is the amount in the currency of the transaction.
+	value *big.Rat
+	// quantity is the amount in the commodity of the account.
+	quantity *big.Rat
+}
+
+// transaction is a transaction of the book.
+type transaction struct {
+	date        types.Date
+	currency    commodity
+	description string
+	notes       string
+	splits      []*split
+}
+
+// book is the content of a GnuCash book. Only accounts in the tree below the root account are kept, so that
+// the template accounts and transactions of scheduled transactions are left out.
+type book struct {
+	root         string
+	accounts     map[string]*account
+	transactions []*transaction
+	prices       prices.DB
+}
+
+// readBook reads a book saved in the XML format, compressed or not, or in the SQLite format.
+func readBook(path string) (*book, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	head, _ := r.Peek(16)
+	var b *book
+	switch {
+	case bytes.HasPrefix(head, []byte("SQLite format 3")):
+		db, err := sqlite.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		b, err = readSQLite(db)
+		if err != nil {
+			return nil, err
+		}
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		if b, err = readXML(gz); err != nil {
+			return nil, err
+		}
+	default:
+		if b, err = readXML(r); err != nil {
+			return nil, err
+		}
+	}
+	b.prune()
+	b.prices.Sort()
+	// Books list transactions in the order they were entered, keep the output ordered by date
+	slices.SortStableFunc(b.transactions, func(x, y *transaction) int {
+		return time.Time(x.date).Compare(time.Time(y.date))
+	})
+	return b, nil
+}
+
+// prune removes the accounts that are not in the tree below the root account, and the splits and transactions
+// that refer to them.
+func (b *book) prune() {
+	for guid := range b.accounts {
+		if _, ok := b.path(guid); !ok {
+			delete(b.accounts, guid)
+		}
+	}
+	transactions := b.transactions[:0]
+	for _, txn := range b.transactions {
+		splits := txn.splits[:0]
+		for _, s := range txn.splits {
+			if b.accounts[s.account] != nil {
+				splits = append(splits, s)
+			}
+		}
+		if txn.splits = splits; len(splits) > 0 {
+			transactions = append(transactions, txn)
+		}
+	}
+	b.transactions = transactions
+}
+
+// path returns the names of an account and its ancestors below the root account, starting with the top-level
+// account, and whether the account is in the tree below the root account at all.
+func (b *book) path(guid string) ([]string, bool) {
+	var names []string
+	for depth := 0; depth < 64; depth++ {
+		if guid == b.root {
+			// The root account itself has no path
+			return names, len(names) > 0
+		}
+		acct := b.accounts[guid]
+		if acct == nil {
+			return nil, false
+		}
+		names = append([]string{acct.name}, names...)
+		guid = acct.parent
+	}
+	return nil, false
+}
+
+// fullName returns the full name of an account as GnuCash shows it, such as Assets:Current Assets:Checking.
+func (b *book) fullName(guid string) string {
+	names, _ := b.path(guid)
+	return strings.Join(names, ":")
+}
+
+// parseDate converts the date of a GnuCash timestamp, which is written in the time zone it was entered in, such
+// as 2024-01-05 10:59:00 +0000, or stored as 20240105105900 by old versions of the SQLite format.
+func parseDate(ts string) (types.Date, error) {
+	ts = strings.TrimSpace(ts)
+	if len(ts) >= 8 && !strings.Contains(ts[:8], "-") {
+		return types.ParseDate(ts[:4] + "-" + ts[4:6] + "-" + ts[6:8])
+	}
+	if len(ts) < 10 {
+		return types.Date{}, fmt.Errorf("invalid timestamp %q", ts)
+	}
+	return types.ParseDate(ts[:10])
+}
@@ -0,0 +1,108 @@
+package gnucash
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/prices"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetPortfolio implements ds.GetPortfolioFunc. The balance of every asset and liability account as of today
+// becomes a position valued in the base currency at the latest price in the price database. Bank and cash
+// accounts are cash, stock and mutual fund accounts are investments with their commodity as the ticker, and
+// other accounts are named by their type. Accounts without a price are included with a zero value and a note,
+// so that they are not silently lost.
+func (s *Source) GetPortfolio(ctx context.Context) (portfolio *types.Portfolio, err error) {
+	b, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(ctx, "gnucash.portfolio")
+	defer func() { tracing.End(span, err) }()
+
+	now := s.now()
+	today := types.Date(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	balances := make(map[string]*big.Rat)
+	for _, txn := range b.transactions {
+		if today.Before(txn.date) {
+			continue
+		}
+		for _, sp := range txn.splits {
+			if balances[sp.account] == nil {
+				balances[sp.account] = new(big.Rat)
+			}
+			balances[sp.account].Add(balances[sp.account], sp.quantity)
+		}
+	}
+
+	portfolio = types.NewPortfolio()
+	unpriced := 0
+	for guid, balance := range balances {
+		acct := b.accounts[guid]
+		class := accountClass(acct.kind)
+		if balance.Sign() == 0 || (class != classAsset && class != classLiability) {
+			continue
+		}
+		value, priced := b.prices.Convert(balance, acct.commodity.String(), s.base.String(), today)
+		if !priced {
+			unpriced++
+			value = new(big.Rat)
+		}
+		names, _ := b.path(guid)
+		name := strings.Join(names[min(1, len(names)-1):], ":")
+
+		if class == classLiability {
+			// Liabilities have credit balances, which are negative in the book.
+			debt := types.NewDebtPosition(name, debtType(acct.kind), -prices.ToMoney(value))
+			s.annotate(&debt.Position, b, acct, balance, priced)
+			portfolio.AddDebt(debt)
+			continue
+		}
+		ticker, assetType := "", strings.ToLower(acct.kind)
+		switch acct.kind {
+		case typeBank, typeCash:
+			assetType = "cash"
+		case typeStock, typeMutual:
+			ticker, assetType = acct.commodity.id, "investment"
+		}
+		asset := types.NewAssetPosition(name, ticker, assetType, "", prices.ToMoney(value))
+		s.annotate(&asset.Position, b, acct, balance, priced)
+		portfolio.AddAsset(asset)
+	}
+	portfolio.Sort()
+	if unpriced > 0 {
+		logging.FromContext(ctx).WarnContext(ctx, "GnuCash accounts have no price", "accounts", unpriced)
+	}
+	span.SetAttributes(attribute.Int("assets", len(portfolio.Assets)), attribute.Int("debts", len(portfolio.Debts)))
+	logging.FromContext(ctx).DebugContext(ctx, "Computed GnuCash portfolio",
+		"assets", len(portfolio.Assets), "debts", len(portfolio.Debts))
+	return portfolio, nil
+}
+
+// debtType returns the type of the debt position of a liability account.
+func debtType(kind string) string {
+	if kind == typeCredit {
+		return "credit card"
+	}
+	return strings.ToLower(kind)
+}
+
+// annotate annotates a position with the full name of its account and, for commodities other than the base
+// currency, the quantity held.
+func (s *Source) annotate(p *types.Position, b *book, acct *account, balance *big.Rat, priced bool) {
+	p.Description = acct.description
+	p.Annotate("account", b.fullName(acct.guid))
+	if acct.commodity != s.base {
+		p.Annotate("quantity", prices.Format(balance, acct.commodity.id))
+	}
+	if !priced {
+		p.Annotate("note", "no price of "+acct.commodity.id+" in "+s.base.id+" is in the price database of the book")
+	}
+}
@@ -0,0 +1,59 @@
+package gnucash
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetPortfolio(t *testing.T) {
+	for _, path := range books {
+		t.Run(path, func(t *testing.T) {
+			src := NewSource(path, "USD")
+			src.now = func() time.Time { return time.Date(2024, 2, 15, 12, 0, 0, 0, time.UTC) }
+			portfolio, err := src.GetPortfolio(context.Background())
+			if err != nil {
+				t.Fatalf("GetPortfolio error: %v", err)
+			}
+
+			if len(portfolio.Assets) != 3 || len(portfolio.Debts) != 1 {
+				t.Fatalf("portfolio = %+v; want checking, euro savings, brokerage and the card", portfolio)
+			}
+			for _, asset := range portfolio.Assets {
+				switch asset.Name {
+				case "Current Assets:Checking":
+					if asset.Value != 68465600 || asset.Type != "cash" || asset.Description != "Joint checking" ||
+						asset.Annotations["account"] != "Assets:Current Assets:Checking" {
+						t.Errorf("checking = %+v", asset)
+					}
+				case "Current Assets:Euro Savings":
+					if asset.Value != 5076000 || asset.Annotations["quantity"] != "470 EUR" {
+						t.Errorf("euro savings = %+v; want 470 EUR at the latest price of 1.08", asset)
+					}
+				case "Investments:Brokerage":
+					if asset.Value != 10400000 || asset.Ticker != "VTI" || asset.Type != "investment" {
+						t.Errorf("brokerage = %+v; want 4 VTI at the latest price of 260", asset)
+					}
+				default:
+					t.Errorf("unexpected asset %+v", asset)
+				}
+			}
+			if card := portfolio.Debts[0]; card.Name != "Credit Card" || card.Type != "credit card" || card.Value != 200000 {
+				t.Errorf("debt = %+v; want the card balance of 20", card)
+			}
+		})
+	}
+}
+
+func TestGetPortfolio_Unpriced(t *testing.T) {
+	src := NewSource(books[0], "GBP")
+	portfolio, err := src.GetPortfolio(context.Background())
+	if err != nil {
+		t.Fatalf("GetPortfolio error: %v", err)
+	}
+	for _, asset := range portfolio.Assets {
+		if asset.Value != 0 || asset.Annotations["note"] == "" {
+			t.Errorf("asset = %+v; want zero value with a note without a price in GBP", asset)
+		}
+	}
+}
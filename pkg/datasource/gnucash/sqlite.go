@@ -0,0 +1,136 @@
+package gnucash
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/prices"
+	"github.com/wyvernzora/personal-finance-mcp/internal/sqlite"
+)
+
+// readSQLite reads a book in the SQLite format.
+func readSQLite(db *sqlite.DB) (*book, error) {
+	for _, name := range []string{"books", "accounts", "commodities", "transactions", "splits"} {
+		if !db.HasTable(name) {
+			return nil, fmt.Errorf("not a GnuCash book, it has no %s table", name)
+		}
+	}
+
+	books, err := db.Rows("books")
+	if err != nil {
+		return nil, err
+	}
+	if len(books) != 1 {
+		return nil, fmt.Errorf("expected one book, found %d", len(books))
+	}
+	b := &book{root: books[0].String("root_account_guid"), accounts: make(map[string]*account), prices: make(prices.DB)}
+
+	rows, err := db.Rows("commodities")
+	if err != nil {
+		return nil, err
+	}
+	commodities := make(map[string]commodity)
+	for _, row := range rows {
+		commodities[row.String("guid")] = newCommodity(row.String("namespace"), row.String("mnemonic"))
+	}
+
+	if rows, err = db.Rows("accounts"); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		acct := &account{
+			guid:        row.String("guid"),
+			name:        row.String("name"),
+			kind:        row.String("account_type"),
+			commodity:   commodities[row.String("commodity_guid")],
+			parent:      row.String("parent_guid"),
+			description: row.String("description"),
+		}
+		b.accounts[acct.guid] = acct
+	}
+
+	// Notes of transactions are kept in slots
+	notes := make(map[string]string)
+	if db.HasTable("slots") {
+		if rows, err = db.Rows("slots"); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if row.String("name") == "notes" {
+				notes[row.String("obj_guid")] = row.String("string_val")
+			}
+		}
+	}
+
+	if rows, err = db.Rows("transactions"); err != nil {
+		return nil, err
+	}
+	transactions := make(map[string]*transaction, len(rows))
+	for _, row := range rows {
+		guid := row.String("guid")
+		date, err := parseDate(row.String("post_date"))
+		if err != nil {
+			return nil, fmt.Errorf("transaction %q: %w", row.String("description"), err)
+		}
+		txn := &transaction{
+			date:        date,
+			currency:    commodities[row.String("currency_guid")],
+			description: row.String("description"),
+			notes:       notes[guid],
+		}
+		transactions[guid] = txn
+		b.transactions = append(b.transactions, txn)
+	}
+
+	if rows, err = db.Rows("splits"); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		txn := transactions[row.String("tx_guid")]
+		if txn == nil {
+			continue
+		}
+		value, err := rational(row, "value")
+		if err != nil {
+			return nil, fmt.Errorf("transaction %q of %s: %w", txn.description, txn.date, err)
+		}
+		quantity, err := rational(row, "quantity")
+		if err != nil {
+			return nil, fmt.Errorf("transaction %q of %s: %w", txn.description, txn.date, err)
+		}
+		txn.splits = append(txn.splits, &split{
+			account:  row.String("account_guid"),
+			memo:     row.String("memo"),
+			value:    value,
+			quantity: quantity,
+		})
+	}
+
+	if db.HasTable("prices") {
+		if rows, err = db.Rows("prices"); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			date, err := parseDate(row.String("date"))
+			if err != nil {
+				return nil, fmt.Errorf("price %s: %w", row.String("guid"), err)
+			}
+			value, err := rational(row, "value")
+			if err != nil {
+				return nil, fmt.Errorf("price %s: %w", row.String("guid"), err)
+			}
+			b.prices.Add(date, commodities[row.String("commodity_guid")].String(), commodities[row.String("currency_guid")].String(), value)
+		}
+	}
+	return b, nil
+}
+
+// rational reads an amount stored as a numerator and a denominator in the columns <prefix>_num and
+// <prefix>_denom.
+func rational(row sqlite.Row, prefix string) (*big.Rat, error) {
+	denom := row.Int(prefix + "_denom")
+	if denom == 0 {
+		return nil, fmt.Errorf("invalid %s with a zero denominator", prefix)
+	}
+	return big.NewRat(row.Int(prefix+"_num"), denom), nil
+}
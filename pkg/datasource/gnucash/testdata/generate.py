@@ -0,0 +1,209 @@
+# Generates the same household book in both formats GnuCash saves: household.gnucash, gzipped XML, and
+# household.sqlite.gnucash, SQLite. Regenerate them with: python3 generate.py
+import gzip, os, sqlite3
+from fractions import Fraction
+
+COMMODITIES = {
+    "usd": ("CURRENCY", "USD", 100),
+    "eur": ("CURRENCY", "EUR", 100),
+    "vti": ("NASDAQ", "VTI", 10000),
+}
+
+# guid, name, type, commodity, parent, placeholder, description
+ROOT, TEMPLATE_ROOT = "root", "template-root"
+ACCOUNTS = [
+    ("assets", "Assets", "ASSET", "usd", ROOT, True, ""),
+    ("current", "Current Assets", "ASSET", "usd", "assets", True, ""),
+    ("checking", "Checking", "BANK", "usd", "current", False, "Joint checking"),
+    ("euro", "Euro Savings", "BANK", "eur", "current", False, ""),
+    ("investments", "Investments", "ASSET", "usd", "assets", True, ""),
+    ("brokerage", "Brokerage", "STOCK", "vti", "investments", False, ""),
+    ("liabilities", "Liabilities", "LIABILITY", "usd", ROOT, True, ""),
+    ("card", "Credit Card", "CREDIT", "usd", "liabilities", False, ""),
+    ("income", "Income", "INCOME", "usd", ROOT, True, ""),
+    ("salary", "Salary", "INCOME", "usd", "income", False, ""),
+    ("expenses", "Expenses", "EXPENSE", "usd", ROOT, True, ""),
+    ("groceries", "Groceries", "EXPENSE", "usd", "expenses", False, ""),
+    ("dining", "Dining", "EXPENSE", "usd", "expenses", False, ""),
+    ("travel", "Travel", "EXPENSE", "eur", "expenses", False, ""),
+    ("equity", "Equity", "EQUITY", "usd", ROOT, True, ""),
+    ("opening", "Opening Balances", "EQUITY", "usd", "equity", False, ""),
+]
+TEMPLATE_ACCOUNTS = [("template-1", "template-1", "BANK", "usd", TEMPLATE_ROOT, False, "")]
+
+# guid, date, currency, description, notes, splits of (account, value, quantity, memo)
+F = Fraction
+TRANSACTIONS = [
+    ("t-opening", "2023-12-31", "usd", "Opening Balance", "", [
+        ("checking", F(5000), F(5000), ""), ("euro", F(550), F(500), ""), ("opening", F(-5550), F(-5550), "")]),
+    ("t-jita", "2024-01-05", "usd", "Jita Market", "receipt in drawer", [
+        ("groceries", F("123.45"), F("123.45"), "weekly"), ("checking", F("-123.45"), F("-123.45"), "")]),
+    ("t-split", "2024-01-10", "usd", "Dinner and groceries", "", [
+        ("groceries", F(30), F(30), ""), ("dining", F(20), F(20), "cake"), ("card", F(-50), F(-50), "")]),
+    ("t-salary", "2024-01-15", "usd", "ACME Payroll", "January", [
+        ("checking", F(3000), F(3000), ""), ("salary", F(-3000), F(-3000), "")]),
+    ("t-payment", "2024-01-20", "usd", "Card payment", "", [
+        ("card", F(30), F(30), ""), ("checking", F(-30), F(-30), "")]),
+    ("t-refund", "2024-01-22", "usd", "Jita Market", "", [
+        ("groceries", F(-10), F(-10), "refund"), ("checking", F(10), F(10), "")]),
+    ("t-vti", "2024-01-25", "usd", "Buy VTI", "", [
+        ("brokerage", F(1000), F(4), ""), ("checking", F(-1000), F(-1000), "")]),
+    ("t-bakery", "2024-01-26", "eur", "Paris bakery", "", [
+        ("travel", F(20), F(20), ""), ("euro", F(-20), F(-20), "")]),
+    ("t-hotel", "2024-01-27", "eur", "Hotel bar", "", [
+        ("dining", F(10), F("10.80"), ""), ("euro", F(-10), F(-10), "")]),
+    ("t-february", "2024-02-01", "usd", "Jita Market", "", [
+        ("groceries", F("9.99"), F("9.99"), ""), ("checking", F("-9.99"), F("-9.99"), "")]),
+]
+# Scheduled transactions keep their splits in template accounts, they must not be read as transactions
+TEMPLATE_TRANSACTIONS = [
+    ("t-template", "2024-01-01", "usd", "Monthly rent", "", [("template-1", F(0), F(0), "")]),
+]
+
+# commodity, currency, date, value
+PRICES = [
+    ("vti", "usd", "2024-01-25", F(250)),
+    ("vti", "usd", "2024-01-31", F(260)),
+    ("eur", "usd", "2024-01-01", F("1.10")),
+    ("eur", "usd", "2024-01-20", F("1.08")),
+]
+
+
+def num(f, denom=100):
+    n = f * denom
+    assert n.denominator == 1, f
+    return n.numerator, denom
+
+
+def xml_cmdty(tag, c):
+    space, mnemonic, _ = COMMODITIES[c]
+    return f"<{tag}>\n  <cmdty:space>{space}</cmdty:space>\n  <cmdty:id>{mnemonic}</cmdty:id>\n</{tag}>\n"
+
+
+def xml_num(f, denom=100):
+    n, d = num(f, denom)
+    return f"{n}/{d}"
+
+
+def xml_account(guid, name, kind, c, parent, placeholder, description):
+    out = f'<gnc:account version="2.0.0">\n<act:name>{name}</act:name>\n<act:id type="guid">{guid}</act:id>\n'
+    out += f"<act:type>{kind}</act:type>\n"
+    if c:
+        out += xml_cmdty("act:commodity", c) + f"<act:commodity-scu>{COMMODITIES[c][2]}</act:commodity-scu>\n"
+    if description:
+        out += f"<act:description>{description}</act:description>\n"
+    if placeholder:
+        out += ('<act:slots>\n  <slot>\n    <slot:key>placeholder</slot:key>\n'
+                '    <slot:value type="string">true</slot:value>\n  </slot>\n</act:slots>\n')
+    if parent:
+        out += f'<act:parent type="guid">{parent}</act:parent>\n'
+    return out + "</gnc:account>\n"
+
+
+def xml_transaction(guid, date, currency, description, notes, splits):
+    out = f'<gnc:transaction version="2.0.0">\n<trn:id type="guid">{guid}</trn:id>\n'
+    out += xml_cmdty("trn:currency", currency)
+    out += f"<trn:date-posted>\n  <ts:date>{date} 10:59:00 +0000</ts:date>\n</trn:date-posted>\n"
+    out += f"<trn:date-entered>\n  <ts:date>{date} 18:00:00 +0000</ts:date>\n</trn:date-entered>\n"
+    out += f"<trn:description>{description.replace('&', '&amp;')}</trn:description>\n"
+    if notes:
+        out += ('<trn:slots>\n  <slot>\n    <slot:key>notes</slot:key>\n'
+                f'    <slot:value type="string">{notes}</slot:value>\n  </slot>\n</trn:slots>\n')
+    out += "<trn:splits>\n"
+    for i, (acct, value, quantity, memo) in enumerate(splits):
+        out += f'  <trn:split>\n    <split:id type="guid">{guid}-{i}</split:id>\n'
+        if memo:
+            out += f"    <split:memo>{memo}</split:memo>\n"
+        out += "    <split:reconciled-state>n</split:reconciled-state>\n"
+        out += f"    <split:value>{xml_num(value)}</split:value>\n"
+        out += f"    <split:quantity>{xml_num(quantity, 10000)}</split:quantity>\n"
+        out += f'    <split:account type="guid">{acct}</split:account>\n  </trn:split>\n'
+    return out + "</trn:splits>\n</gnc:transaction>\n"
+
+
+def write_xml(path):
+    out = '<?xml version="1.0" encoding="utf-8" ?>\n<gnc-v2\n'
+    for ns in ["gnc", "act", "book", "cd", "cmdty", "price", "slot", "split", "sx", "trn", "ts", "fs", "bgt",
+               "recurrence", "lot", "addr", "billterm", "bt-days", "bt-prox", "cust", "employee", "entry",
+               "invoice", "job", "order", "owner", "taxtable", "tte", "vendor"]:
+        out += f'     xmlns:{ns}="http://www.gnucash.org/XML/{ns}"\n'
+    out += '>\n<gnc:count-data cd:type="book">1</gnc:count-data>\n<gnc:book version="2.0.0">\n'
+    out += '<book:id type="guid">book</book:id>\n'
+    out += f'<gnc:count-data cd:type="commodity">{len(COMMODITIES)}</gnc:count-data>\n'
+    out += f'<gnc:count-data cd:type="account">{len(ACCOUNTS) + 1}</gnc:count-data>\n'
+    out += f'<gnc:count-data cd:type="transaction">{len(TRANSACTIONS)}</gnc:count-data>\n'
+    for c, (space, mnemonic, fraction) in COMMODITIES.items():
+        if space != "CURRENCY":
+            out += (f'<gnc:commodity version="2.0.0">\n  <cmdty:space>{space}</cmdty:space>\n'
+                    f"  <cmdty:id>{mnemonic}</cmdty:id>\n  <cmdty:fraction>{fraction}</cmdty:fraction>\n"
+                    "</gnc:commodity>\n")
+    out += '<gnc:pricedb version="1">\n'
+    for i, (c, currency, date, value) in enumerate(PRICES):
+        out += f'  <price>\n    <price:id type="guid">price-{i}</price:id>\n'
+        out += xml_cmdty("price:commodity", c) + xml_cmdty("price:currency", currency)
+        out += f"    <price:time>\n      <ts:date>{date} 10:59:00 +0000</ts:date>\n    </price:time>\n"
+        out += f"    <price:source>user:price-editor</price:source>\n    <price:type>last</price:type>\n"
+        out += f"    <price:value>{xml_num(value, 10000)}</price:value>\n  </price>\n"
+    out += "</gnc:pricedb>\n"
+    out += xml_account(ROOT, "Root Account", "ROOT", "usd", None, False, "")
+    for a in ACCOUNTS:
+        out += xml_account(*a)
+    for t in TRANSACTIONS:
+        out += xml_transaction(*t)
+    out += "<gnc:template-transactions>\n"
+    out += xml_account(TEMPLATE_ROOT, "Template Root", "ROOT", None, None, False, "")
+    for a in TEMPLATE_ACCOUNTS:
+        out += xml_account(*a)
+    for t in TEMPLATE_TRANSACTIONS:
+        out += xml_transaction(*t)
+    out += "</gnc:template-transactions>\n</gnc:book>\n</gnc-v2>\n"
+    with gzip.GzipFile(path, "wb", mtime=0) as f:
+        f.write(out.encode())
+
+
+SCHEMA = """
+CREATE TABLE versions(table_name text(50) PRIMARY KEY NOT NULL, table_version integer NOT NULL);
+CREATE TABLE books(guid text(32) PRIMARY KEY NOT NULL, root_account_guid text(32) NOT NULL, root_template_guid text(32) NOT NULL);
+CREATE TABLE commodities(guid text(32) PRIMARY KEY NOT NULL, namespace text(2048) NOT NULL, mnemonic text(2048) NOT NULL, fullname text(2048), cusip text(2048), fraction integer NOT NULL, quote_flag integer NOT NULL, quote_source text(2048), quote_tz text(2048));
+CREATE TABLE accounts(guid text(32) PRIMARY KEY NOT NULL, name text(2048) NOT NULL, account_type text(2048) NOT NULL, commodity_guid text(32), commodity_scu integer NOT NULL, non_std_scu integer NOT NULL, parent_guid text(32), code text(2048), description text(2048), hidden integer, placeholder integer);
+CREATE TABLE prices(guid text(32) PRIMARY KEY NOT NULL, commodity_guid text(32) NOT NULL, currency_guid text(32) NOT NULL, date text(19) NOT NULL, source text(2048), type text(2048), value_num bigint NOT NULL, value_denom bigint NOT NULL);
+CREATE TABLE transactions(guid text(32) PRIMARY KEY NOT NULL, currency_guid text(32) NOT NULL, num text(2048) NOT NULL, post_date text(19), enter_date text(19), description text(2048));
+CREATE INDEX tx_post_date_index ON transactions(post_date);
+CREATE TABLE splits(guid text(32) PRIMARY KEY NOT NULL, tx_guid text(32) NOT NULL, account_guid text(32) NOT NULL, memo text(2048) NOT NULL, action text(2048) NOT NULL, reconcile_state text(1) NOT NULL, reconcile_date text(19), value_num bigint NOT NULL, value_denom bigint NOT NULL, quantity_num bigint NOT NULL, quantity_denom bigint NOT NULL, lot_guid text(32));
+CREATE INDEX splits_tx_guid_index ON splits(tx_guid);
+CREATE TABLE slots(id integer PRIMARY KEY AUTOINCREMENT NOT NULL, obj_guid text(32) NOT NULL, name text(4096) NOT NULL, slot_type integer NOT NULL, int64_val bigint, string_val text(4096), double_val float8, timespec_val text(19), guid_val text(32), numeric_val_num bigint, numeric_val_denom bigint, gdate_val text(8), guid text(32));
+"""
+
+
+def write_sqlite(path):
+    if os.path.exists(path):
+        os.remove(path)
+    db = sqlite3.connect(path)
+    db.executescript(SCHEMA)
+    db.execute("INSERT INTO books VALUES ('book', ?, ?)", (ROOT, TEMPLATE_ROOT))
+    for c, (space, mnemonic, fraction) in COMMODITIES.items():
+        db.execute("INSERT INTO commodities VALUES (?, ?, ?, ?, '', ?, 0, NULL, NULL)", (c, space, mnemonic, mnemonic, fraction))
+    accounts = [(ROOT, "Root Account", "ROOT", "usd", None, False, ""), (TEMPLATE_ROOT, "Template Root", "ROOT", None, None, False, "")]
+    for guid, name, kind, c, parent, placeholder, description in accounts + ACCOUNTS + TEMPLATE_ACCOUNTS:
+        scu = COMMODITIES[c][2] if c else 0
+        db.execute("INSERT INTO accounts VALUES (?, ?, ?, ?, ?, 0, ?, '', ?, 0, ?)",
+                   (guid, name, kind, c, scu, parent, description, int(placeholder)))
+    for i, (c, currency, date, value) in enumerate(PRICES):
+        db.execute("INSERT INTO prices VALUES (?, ?, ?, ?, 'user:price-editor', 'last', ?, ?)",
+                   (f"price-{i}", c, currency, f"{date} 10:59:00", *num(value, 10000)))
+    for guid, date, currency, description, notes, splits in TRANSACTIONS + TEMPLATE_TRANSACTIONS:
+        db.execute("INSERT INTO transactions VALUES (?, ?, '', ?, ?, ?)",
+                   (guid, currency, f"{date} 10:59:00", f"{date} 18:00:00", description))
+        if notes:
+            db.execute("INSERT INTO slots (obj_guid, name, slot_type, string_val) VALUES (?, 'notes', 4, ?)", (guid, notes))
+        for i, (acct, value, quantity, memo) in enumerate(splits):
+            db.execute("INSERT INTO splits VALUES (?, ?, ?, ?, '', 'n', NULL, ?, ?, ?, ?, NULL)",
+                       (f"{guid}-{i}", guid, acct, memo, *num(value), *num(quantity, 10000)))
+    db.commit()
+    db.execute("VACUUM")
+    db.close()
+
+
+os.chdir(os.path.dirname(os.path.abspath(__file__)))
+write_xml("household.gnucash")
+write_sqlite("household.sqlite.gnucash")
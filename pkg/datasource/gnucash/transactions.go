@@ -0,0 +1,222 @@
+// Package gnucash implements a data source that reads GnuCash books saved in the XML or the SQLite format.
+// Income and expense accounts become categories of transactions, while asset and liability accounts make up the
+// portfolio.
+package gnucash
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/prices"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Source reads a GnuCash book. The book is re-read on every call, so changes are picked up without a restart.
+type Source struct {
+	path string
+	base commodity
+	now  func() time.Time
+}
+
+// NewSource creates a Source that reads the book at path. Amounts in other commodities are converted into the
+// base currency using the price database of the book.
+func NewSource(path, baseCurrency string) *Source {
+	return &Source{path: path, base: currency(baseCurrency), now: time.Now}
+}
+
+// Account classes, which group the account types of GnuCash.
+const (
+	classAsset     = "asset"
+	classLiability = "liability"
+	classIncome    = "income"
+	classExpense   = "expense"
+	classEquity    = "equity"
+)
+
+// accountClass returns the class of an account type. Trading accounts, which balance transactions between
+// currencies, have no class.
+func accountClass(kind string) string {
+	switch kind {
+	case typeBank, typeCash, typeAsset, typeStock, typeMutual, typeCurrency, typeReceivable:
+		return classAsset
+	case typeCredit, typeLiability, typePayable:
+		return classLiability
+	case typeIncome:
+		return classIncome
+	case typeExpense:
+		return classExpense
+	case typeEquity:
+		return classEquity
+	default:
+		return ""
+	}
+}
+
+// categoryPath returns the category of an income or expense account: the names of the account and its ancestors,
+// without the conventional top-level Income and Expenses accounts, so Expenses:Food:Groceries becomes
+// Food/Groceries.
+func categoryPath(names []string) []string {
+	switch strings.ToLower(names[0]) {
+	case "income", "expenses", "expense":
+		names = names[1:]
+	}
+	if len(names) == 0 {
+		return []string{types.UncategorizedName}
+	}
+	return names
+}
+
+// GetCategorizedTransactions implements ds.GetCategorizedTransactionsFunc. Every split of an income or expense
+// account becomes a transaction in the category named by the account, with the sign of GnuCash: debits to
+// expense accounts are expenses and credits to income accounts are income. Transactions between asset and
+// liability accounts are reported as ignored transfers, while those involving equity accounts, such as opening
+// balances, are left out.
+func (s *Source) GetCategorizedTransactions(ctx context.Context, interval ds.DateRange) (result *types.Categories, err error) {
+	b, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(ctx, "gnucash.categorize", attribute.Int("transactions", len(b.transactions)))
+	defer func() { tracing.End(span, err) }()
+
+	result = types.NewCategories()
+	for _, txn := range b.transactions {
+		if txn.date.Before(interval.StartDate) || interval.EndDate.Before(txn.date) {
+			continue
+		}
+		if err := s.categorize(result, b, txn); err != nil {
+			return nil, err
+		}
+	}
+	logging.FromContext(ctx).DebugContext(ctx, "Categorized GnuCash transactions", "transactions", result.TransactionCount())
+	return result, nil
+}
+
+// categorize adds the splits of a book transaction to result.
+func (s *Source) categorize(result *types.Categories, b *book, txn *transaction) error {
+	var account string
+	transfer, involvesEquity := true, false
+	for _, sp := range txn.splits {
+		switch accountClass(b.accounts[sp.account].kind) {
+		case classAsset, classLiability:
+			if account == "" {
+				account = b.fullName(sp.account)
+			}
+		case classIncome, classExpense:
+			transfer = false
+		case classEquity:
+			involvesEquity = true
+		}
+	}
+
+	newTransaction := func(sp *split, amount types.Money) *types.Transaction {
+		tx := types.NewTransaction(txn.date, txn.description, amount)
+		tx.Description = txn.notes
+		if sp != nil && sp.memo != "" {
+			tx.Description = sp.memo
+		}
+		if account != "" {
+			tx.Annotate("account", account)
+		}
+		if sp != nil && txn.currency != s.base {
+			tx.Annotate("original_amount", prices.Format(sp.value, txn.currency.id))
+		}
+		return tx
+	}
+
+	if transfer {
+		if involvesEquity || account == "" {
+			return nil
+		}
+		// A transfer is reported once, with the total amount moved.
+		total := new(big.Rat)
+		for _, sp := range txn.splits {
+			if accountClass(b.accounts[sp.account].kind) == "" {
+				continue
+			}
+			value, err := s.value(b, txn, sp)
+			if err != nil {
+				return err
+			}
+			if value.Sign() > 0 {
+				total.Add(total, value)
+			}
+		}
+		return result.Ignored.GetOrCreatePath("Transfers").AddTransaction(newTransaction(nil, prices.ToMoney(total)))
+	}
+
+	for _, sp := range txn.splits {
+		var bucket *types.Category
+		switch accountClass(b.accounts[sp.account].kind) {
+		case classIncome:
+			bucket = result.Income
+		case classExpense:
+			bucket = result.Expenses
+		default:
+			continue
+		}
+		// Debits are positive in GnuCash, so expenses are positive and income is negative, which matches the
+		// domain convention.
+		value, err := s.value(b, txn, sp)
+		if err != nil {
+			return err
+		}
+		names, _ := b.path(sp.account)
+		if err := bucket.GetOrCreatePath(categoryPath(names)...).AddTransaction(newTransaction(sp, prices.ToMoney(value))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// value returns the value of a split in the base currency: its value if the transaction is in the base currency,
+// its quantity if the account is, or else its value converted using the price closest to the date of the
+// transaction.
+func (s *Source) value(b *book, txn *transaction, sp *split) (*big.Rat, error) {
+	if txn.currency == s.base {
+		return sp.value, nil
+	}
+	if b.accounts[sp.account].commodity == s.base {
+		return sp.quantity, nil
+	}
+	value, ok := b.prices.Convert(sp.value, txn.currency.String(), s.base.String(), txn.date)
+	if !ok {
+		return nil, ds.Errorf(ds.ErrUpstreamUnavailable, "no price of %s in %s for transaction %q of %s",
+			txn.currency.id, s.base.id, txn.description, txn.date).
+			WithHint("the book on the server lacks a price; ask the user to add it to the price database")
+	}
+	return value, nil
+}
+
+// Check implements ds.CheckFunc by verifying that the book can be read.
+func (s *Source) Check(ctx context.Context) error {
+	_, err := s.read(ctx)
+	return err
+}
+
+// read reads the book.
+func (s *Source) read(ctx context.Context) (b *book, err error) {
+	ctx, span := tracing.Start(ctx, "gnucash.read")
+	defer func() { tracing.End(span, err) }()
+
+	b, err = readBook(s.path)
+	if err != nil {
+		hint := "the book on the server cannot be read; ask the user to check that it is a GnuCash book"
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			hint = "the book configured on the server cannot be read; ask the user to fix it"
+		}
+		return nil, ds.Errorf(ds.ErrUpstreamUnavailable, "failed to read book: %w", err).WithHint(hint)
+	}
+	span.SetAttributes(attribute.Int("accounts", len(b.accounts)), attribute.Int("transactions", len(b.transactions)))
+	logging.FromContext(ctx).DebugContext(ctx, "Read GnuCash book", "transactions", len(b.transactions))
+	return b, nil
+}
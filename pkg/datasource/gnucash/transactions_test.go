@@ -0,0 +1,139 @@
+package gnucash
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// books are the same household book in both formats, generated by testdata/generate.py.
+var books = []string{"testdata/household.gnucash", "testdata/household.sqlite.gnucash"}
+
+func january(t *testing.T) ds.DateRange {
+	t.Helper()
+	start, _ := types.ParseDate("2024-01-01")
+	end, _ := types.ParseDate("2024-01-31")
+	return ds.DateRange{StartDate: start, EndDate: end}
+}
+
+func TestGetCategorizedTransactions(t *testing.T) {
+	for _, path := range books {
+		t.Run(path, func(t *testing.T) {
+			result, err := NewSource(path, "USD").GetCategorizedTransactions(context.Background(), january(t))
+			if err != nil {
+				t.Fatalf("GetCategorizedTransactions error: %v", err)
+			}
+
+			groceries := result.Expenses.GetOrCreatePath("Groceries").Transactions
+			if len(groceries) != 3 {
+				t.Fatalf("groceries = %+v; want the market, the split and the refund", groceries)
+			}
+			if tx := groceries[0]; tx.Payee != "Jita Market" || tx.Amount != 1234500 || tx.Description != "weekly" ||
+				tx.Date.String() != "2024-01-05" || tx.Annotations["account"] != "Assets:Current Assets:Checking" {
+				t.Errorf("market = %+v", tx)
+			}
+			if tx := groceries[1]; tx.Payee != "Dinner and groceries" || tx.Amount != 300000 || tx.Annotations["account"] != "Liabilities:Credit Card" {
+				t.Errorf("split = %+v; want the groceries part on the card", tx)
+			}
+			if tx := groceries[2]; tx.Amount != -100000 || tx.Description != "refund" {
+				t.Errorf("refund = %+v; want a negative expense", tx)
+			}
+			if total := result.Expenses.GetOrCreatePath("Groceries").TotalAmount; total != 1234500+300000-100000 {
+				t.Errorf("groceries total = %d", total)
+			}
+
+			dining := result.Expenses.GetOrCreatePath("Dining").Transactions
+			if len(dining) != 2 || dining[0].Description != "cake" || dining[0].Amount != 200000 {
+				t.Fatalf("dining = %+v; want the split part and the hotel bar", dining)
+			}
+			// The account is in the base currency, so its quantity is used
+			if hotel := dining[1]; hotel.Amount != 108000 || hotel.Annotations["original_amount"] != "10 EUR" {
+				t.Errorf("hotel = %+v; want 10.80 converted by the transaction", hotel)
+			}
+			// Both the transaction and the account are in euros, so the price database is used
+			travel := result.Expenses.GetOrCreatePath("Travel").Transactions
+			if len(travel) != 1 || travel[0].Amount != 216000 || travel[0].Annotations["original_amount"] != "20 EUR" {
+				t.Errorf("travel = %+v; want 20 EUR at 1.08", travel)
+			}
+
+			salary := result.Income.GetOrCreatePath("Salary").Transactions
+			// The notes of the transaction describe splits without a memo
+			if len(salary) != 1 || salary[0].Amount != -30000000 || salary[0].Description != "January" {
+				t.Errorf("salary = %+v; want the paycheck as negative income", salary)
+			}
+
+			transfers := result.Ignored.GetOrCreatePath("Transfers").Transactions
+			if len(transfers) != 2 || transfers[0].Amount != 300000 || transfers[1].Amount != 10000000 {
+				t.Errorf("transfers = %+v; want the card payment and the purchase of VTI", transfers)
+			}
+			if n := result.TransactionCount(); n != 9 {
+				t.Errorf("TransactionCount() = %d; want 9 without the opening balance and templates", n)
+			}
+		})
+	}
+}
+
+func TestGetCategorizedTransactions_NoPrice(t *testing.T) {
+	for _, path := range books {
+		_, err := NewSource(path, "JPY").GetCategorizedTransactions(context.Background(), january(t))
+		if !errors.Is(err, ds.ErrUpstreamUnavailable) {
+			t.Errorf("%s: err = %v; want upstream unavailable without a price of USD in JPY", path, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	// Books may also be saved as uncompressed XML
+	f, err := os.Open(books[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := filepath.Join(t.TempDir(), "household.gnucash")
+	if err := os.WriteFile(plain, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range append(books, plain) {
+		if err := NewSource(path, "USD").Check(context.Background()); err != nil {
+			t.Errorf("%s: Check error: %v", path, err)
+		}
+	}
+	if err := NewSource("testdata/generate.py", "USD").Check(context.Background()); !errors.Is(err, ds.ErrUpstreamUnavailable) {
+		t.Errorf("err = %v; want upstream unavailable for a file that is not a book", err)
+	}
+	if err := NewSource("testdata/missing.gnucash", "USD").Check(context.Background()); !errors.Is(err, ds.ErrUpstreamUnavailable) {
+		t.Errorf("err = %v; want upstream unavailable for a missing book", err)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	for ts, want := range map[string]string{
+		"2024-01-05 10:59:00 +0000": "2024-01-05",
+		"2024-01-05 00:00:00 -0500": "2024-01-05",
+		"2024-01-05 10:59:00":       "2024-01-05",
+		"20240105105900":            "2024-01-05",
+	} {
+		if d, err := parseDate(ts); err != nil || d.String() != want {
+			t.Errorf("parseDate(%q) = %v, %v; want %s", ts, d, err, want)
+		}
+	}
+	if _, err := parseDate("2024"); err == nil {
+		t.Error("expected an error for a truncated timestamp")
+	}
+}
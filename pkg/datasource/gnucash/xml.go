@@ -0,0 +1,133 @@
+package gnucash
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/prices"
+)
+
+// xmlDocument is a book in the XML format. Elements are matched by their local names, without namespaces. The
+// template accounts and transactions of scheduled transactions are nested in a separate element of the book, so
+// they are not read.
+type xmlDocument struct {
+	Books []struct {
+		Accounts     []xmlAccount     `xml:"account"`
+		Transactions []xmlTransaction `xml:"transaction"`
+		Prices       []xmlPrice       `xml:"pricedb>price"`
+	} `xml:"book"`
+}
+
+type xmlCommodity struct {
+	Space string `xml:"space"`
+	Id    string `xml:"id"`
+}
+
+type xmlSlot struct {
+	Key   string `xml:"key"`
+	Value string `xml:"value"`
+}
+
+type xmlAccount struct {
+	Name        string       `xml:"name"`
+	Id          string       `xml:"id"`
+	Type        string       `xml:"type"`
+	Commodity   xmlCommodity `xml:"commodity"`
+	Description string       `xml:"description"`
+	Parent      string       `xml:"parent"`
+}
+
+type xmlTransaction struct {
+	Currency    xmlCommodity `xml:"currency"`
+	DatePosted  string       `xml:"date-posted>date"`
+	Description string       `xml:"description"`
+	Slots       []xmlSlot    `xml:"slots>slot"`
+	Splits      []xmlSplit   `xml:"splits>split"`
+}
+
+type xmlSplit struct {
+	Memo     string `xml:"memo"`
+	Value    string `xml:"value"`
+	Quantity string `xml:"quantity"`
+	Account  string `xml:"account"`
+}
+
+type xmlPrice struct {
+	Commodity xmlCommodity `xml:"commodity"`
+	Currency  xmlCommodity `xml:"currency"`
+	Time      string       `xml:"time>date"`
+	Value     string       `xml:"value"`
+}
+
+// readXML reads a book in the XML format.
+func readXML(r io.Reader) (*book, error) {
+	var doc xmlDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid GnuCash XML: %w", err)
+	}
+	if len(doc.Books) != 1 {
+		return nil, fmt.Errorf("expected one book, found %d", len(doc.Books))
+	}
+	xb := doc.Books[0]
+
+	b := &book{accounts: make(map[string]*account), prices: make(prices.DB)}
+	for _, xa := range xb.Accounts {
+		if xa.Type == typeRoot && xa.Parent == "" {
+			b.root = xa.Id
+		}
+		b.accounts[xa.Id] = &account{
+			guid:        xa.Id,
+			name:        xa.Name,
+			kind:        xa.Type,
+			commodity:   newCommodity(xa.Commodity.Space, xa.Commodity.Id),
+			parent:      xa.Parent,
+			description: xa.Description,
+		}
+	}
+	if b.root == "" {
+		return nil, fmt.Errorf("book has no root account")
+	}
+
+	for _, xt := range xb.Transactions {
+		date, err := parseDate(xt.DatePosted)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %q: %w", xt.Description, err)
+		}
+		txn := &transaction{
+			date:        date,
+			currency:    newCommodity(xt.Currency.Space, xt.Currency.Id),
+			description: xt.Description,
+		}
+		for _, slot := range xt.Slots {
+			if slot.Key == "notes" {
+				txn.notes = strings.TrimSpace(slot.Value)
+			}
+		}
+		for _, xs := range xt.Splits {
+			value, ok1 := new(big.Rat).SetString(xs.Value)
+			quantity, ok2 := new(big.Rat).SetString(xs.Quantity)
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("transaction %q of %s: invalid split amount %q", xt.Description, date, xs.Value)
+			}
+			txn.splits = append(txn.splits, &split{account: xs.Account, memo: xs.Memo, value: value, quantity: quantity})
+		}
+		b.transactions = append(b.transactions, txn)
+	}
+
+	for _, xp := range xb.Prices {
+		date, err := parseDate(xp.Time)
+		if err != nil {
+			return nil, fmt.Errorf("price of %s: %w", xp.Commodity.Id, err)
+		}
+		value, ok := new(big.Rat).SetString(xp.Value)
+		if !ok {
+			return nil, fmt.Errorf("price of %s on %s: invalid value %q", xp.Commodity.Id, date, xp.Value)
+		}
+		b.prices.Add(date, newCommodity(xp.Commodity.Space, xp.Commodity.Id).String(),
+			newCommodity(xp.Currency.Space, xp.Currency.Id).String(), value)
+	}
+	return b, nil
+}
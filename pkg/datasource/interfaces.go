@@ -19,3 +19,18 @@ type GetCategorizedTransactionsFunc func(ctx context.Context, interval DateRange
 // GetPortfolioFunc is the signature of a data source method that retrieves the current portfolio,
 // including all asset and debt positions.
 type GetPortfolioFunc func(ctx context.Context) (*types.Portfolio, error)
+
+// SelectPortfolioFunc is the signature of a data source method that retrieves the portfolios named by selector,
+// for data sources that keep several portfolios. Several selected portfolios are combined into one.
+type SelectPortfolioFunc func(ctx context.Context, selector string) (*types.Portfolio, error)
+
+// ListCategoriesFunc is the signature of a data source method that retrieves the category hierarchy,
+// without any transactions, as a list of root-level categories.
+type ListCategoriesFunc func(ctx context.Context) ([]*types.Category, error)
+
+// ListTagsFunc is the signature of a data source method that retrieves all tags that can be attached to transactions.
+type ListTagsFunc func(ctx context.Context) ([]*types.Tag, error)
+
+// CheckFunc is the signature of a data source method that verifies, as cheaply as possible, that the
+// data source is reachable and its credentials are valid.
+type CheckFunc func(ctx context.Context) error
@@ -0,0 +1,28 @@
+package kubera
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/clients/kubera"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+)
+
+// CheckCredentials verifies that Kubera is reachable and accepts the API credentials by listing the portfolios,
+// and that the configured portfolio, if any, is among them.
+var CheckCredentials ds.CheckFunc = func(ctx context.Context) error {
+	client, err := clientFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	summaries, err := client.ListPortfolios(ctx)
+	if err != nil {
+		return err
+	}
+	id := client.PortfolioID()
+	if id != "" && !slices.ContainsFunc(summaries, func(s *kubera.PortfolioSummary) bool { return s.Id == id }) {
+		return fmt.Errorf("Kubera portfolio %s not found", id)
+	}
+	return nil
+}
@@ -2,33 +2,28 @@ package kubera
 
 import (
 	"context"
-	"fmt"
-	"log"
 	"net/http"
-	"os"
 
 	"github.com/wyvernzora/personal-finance-mcp/internal/clients/kubera"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/secrets"
 )
 
-// InjectCredentialsFromEnvironment loads Kubera API credentials (API key, secret, and portfolio ID)
-// from environment variables and returns an HTTPContextFunc that injects the configured Kubera client into the request context.
-func InjectCredentialsFromEnvironment() func(ctx context.Context, req *http.Request) context.Context {
-	apiKey := requireEnv("KUBERA_API_KEY")
-	apiSecret := requireEnv("KUBERA_API_SECRET")
-	portfolioId := requireEnv("KUBERA_PORTFOLIO_ID")
-
-	return kubera.WithKuberaCredentials(apiKey, apiSecret, portfolioId)
+// InjectCredentials returns an HTTPContextFunc that injects a Kubera client configured with the given
+// API credentials, portfolio ID and client options into the request context. The current values of the
+// credentials are used for every request, so rotated credentials take effect immediately.
+func InjectCredentials(apiKey, apiSecret *secrets.Secret, portfolioId string, opts ...kubera.Option) func(ctx context.Context, req *http.Request) context.Context {
+	return func(ctx context.Context, req *http.Request) context.Context {
+		return kubera.WithKuberaCredentials(apiKey.Value(), apiSecret.Value(), portfolioId, opts...)(ctx, req)
+	}
 }
 
-// requireEnv retrieves the value of the named environment variable.
-// It panics if the variable is not set or empty, enforcing required configuration.
-// Used by InjectCredentialsFromEnvironment to ensure all credentials are present.
-func requireEnv(name string) string {
-	val := os.Getenv(name)
-	if val == "" {
-		msg := fmt.Sprintf("environment variable %q must be set", name)
-		log.Print(msg)
-		panic(msg)
+// clientFromContext returns the Kubera client injected by InjectCredentials, or an ErrNotConfigured error if
+// there is none.
+func clientFromContext(ctx context.Context) (kubera.Client, error) {
+	client, ok := kubera.FromContext(ctx)
+	if !ok {
+		return nil, ds.Errorf(ds.ErrNotConfigured, "no Kubera client in the request context")
 	}
-	return val
+	return client, nil
 }
@@ -2,41 +2,146 @@ package kubera
 
 import (
 	"context"
+	"fmt"
 	"iter"
-	"log"
 	"maps"
+	"slices"
+	"strings"
 
 	"github.com/bobg/seqs"
 	"github.com/wyvernzora/personal-finance-mcp/internal/clients/kubera"
 	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
 	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-// GetPortfolio fetches portfolio data using the Kubera client stored in context.
-// It retrieves raw Kubera assets and debts, transforms them into domain AssetPosition and DebtPosition types,
-// and aggregates them into a types.Portfolio.
+// AllPortfolios is the selector of every Kubera portfolio.
+const AllPortfolios = "all"
+
+// GetPortfolio fetches the default portfolio using the Kubera client stored in context: the configured portfolio,
+// or every portfolio combined if none is configured. See SelectPortfolio.
 var GetPortfolio ds.GetPortfolioFunc = func(ctx context.Context) (*types.Portfolio, error) {
-	client := kubera.FromContext(ctx)
+	return SelectPortfolio(ctx, "")
+}
 
-	// Fetch raw data
-	kbPortfolio, err := client.GetPortfolio(ctx)
+// SelectPortfolio fetches the portfolios named by selector using the Kubera client stored in context.
+// The selector is a comma-separated list of portfolio names or IDs, matched regardless of case, or AllPortfolios.
+// An empty selector selects the default portfolio, see GetPortfolio.
+// It retrieves raw Kubera assets and debts, transforms them into domain AssetPosition and DebtPosition types,
+// and aggregates them into a types.Portfolio with positions in a stable order. When several portfolios are
+// selected, their positions are summed into one portfolio and annotated with the portfolio they belong to.
+var SelectPortfolio ds.SelectPortfolioFunc = func(ctx context.Context, selector string) (*types.Portfolio, error) {
+	client, err := clientFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if selector == "" {
+		selector = client.PortfolioID()
+	}
+	if selector == "" {
+		selector = AllPortfolios
+	}
+
+	// The configured portfolio is fetched directly, without listing the portfolios first
+	selected := []*kubera.PortfolioSummary{{Id: selector}}
+	if selector != client.PortfolioID() {
+		summaries, err := client.ListPortfolios(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if selected, err = selectPortfolios(summaries, selector); err != nil {
+			return nil, err
+		}
+	}
 
-	// Start processing data
 	portfolio := types.NewPortfolio()
-	log.Printf("%v", portfolio)
-	for asset := range constructAssets(kbPortfolio.Assets) {
-		portfolio.AddAsset(asset)
+	var names []string
+	for _, summary := range selected {
+		// Fetch raw data
+		kbPortfolio, err := client.GetPortfolio(ctx, summary.Id)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, kbPortfolio.Name)
+
+		// Start processing data
+		_, span := tracing.Start(ctx, "kubera.transform", attribute.String("portfolio", kbPortfolio.Id),
+			attribute.Int("assets", len(kbPortfolio.Assets)), attribute.Int("debts", len(kbPortfolio.Debts)))
+		for asset := range constructAssets(kbPortfolio.Assets) {
+			if len(selected) > 1 {
+				asset.Annotate("portfolio", kbPortfolio.Name)
+			}
+			portfolio.AddAsset(asset)
+		}
+		for debt := range constructDebts(kbPortfolio.Debts) {
+			if len(selected) > 1 {
+				debt.Annotate("portfolio", kbPortfolio.Name)
+			}
+			portfolio.AddDebt(debt)
+		}
+		span.End()
 	}
-	for debt := range constructDebts(kbPortfolio.Debts) {
-		portfolio.AddDebt(debt)
+	if len(selected) > 1 {
+		portfolio.Annotate("portfolios", strings.Join(names, ", "))
 	}
+	portfolio.Sort()
+	logging.FromContext(ctx).DebugContext(ctx, "Fetched Kubera portfolio",
+		"portfolios", len(selected), "assets", len(portfolio.Assets), "debts", len(portfolio.Debts))
 
 	return portfolio, nil
 }
 
+// selectPortfolios returns the portfolios among summaries named by selector, in the order they are named and
+// without repetitions. Portfolios can only be combined when their values are in the same currency.
+func selectPortfolios(summaries []*kubera.PortfolioSummary, selector string) ([]*kubera.PortfolioSummary, error) {
+	var selected []*kubera.PortfolioSummary
+	add := func(summary *kubera.PortfolioSummary) {
+		if !slices.Contains(selected, summary) {
+			selected = append(selected, summary)
+		}
+	}
+	for _, name := range strings.Split(selector, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			continue
+		case strings.EqualFold(name, AllPortfolios):
+			for _, summary := range summaries {
+				add(summary)
+			}
+			continue
+		}
+		i := slices.IndexFunc(summaries, func(s *kubera.PortfolioSummary) bool {
+			return s.Id == name || strings.EqualFold(s.Name, name)
+		})
+		if i < 0 {
+			return nil, ds.Errorf(ds.ErrInvalidInput, "unknown Kubera portfolio %q", name).WithHint(portfoliosHint(summaries))
+		}
+		add(summaries[i])
+	}
+	if len(selected) == 0 {
+		return nil, ds.Errorf(ds.ErrInvalidInput, "no Kubera portfolio selected").WithHint(portfoliosHint(summaries))
+	}
+	for _, summary := range selected[1:] {
+		if summary.Currency != selected[0].Currency {
+			return nil, ds.Errorf(ds.ErrInvalidInput, "cannot combine Kubera portfolios %s in %s and %s in %s",
+				selected[0].Name, selected[0].Currency, summary.Name, summary.Currency)
+		}
+	}
+	return selected, nil
+}
+
+// portfoliosHint suggests the names of the portfolios that can be selected.
+func portfoliosHint(summaries []*kubera.PortfolioSummary) string {
+	names := make([]string, len(summaries))
+	for i, summary := range summaries {
+		names[i] = summary.Name
+	}
+	return fmt.Sprintf("select one or more of %s separated by commas, or %s", strings.Join(names, ", "), AllPortfolios)
+}
+
 // constructAssets converts Kubera asset positions into a sequence of domain AssetPosition.
 // It tombstones non-leaf nodes, filters out parent positions, and annotates each asset with metadata.
 func constructAssets(kbAssets []*kubera.AssetPosition) iter.Seq[*types.AssetPosition] {
@@ -2,9 +2,13 @@ package kubera
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	clients "github.com/wyvernzora/personal-finance-mcp/internal/clients/kubera"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
 	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
 )
 
@@ -187,7 +191,8 @@ func TestConstructDebts_NoTombstone(t *testing.T) {
 // TestGetPortfolio_Success verifies GetPortfolio properly transforms assets and debts.
 func TestGetPortfolio_Success(t *testing.T) {
 	// Inject fake client into context
-	ctx := clients.WithKuberaClient(&fakeClient{})(context.Background(), nil)
+	client := &fakeClient{portfolioId: "test-id"}
+	ctx := clients.WithKuberaClient(client)(context.Background(), nil)
 	// Call under test
 	p, err := GetPortfolio(ctx)
 	if err != nil {
@@ -210,14 +215,137 @@ func TestGetPortfolio_Success(t *testing.T) {
 	if len(p.Debts) != 1 || p.Debts[0].Name != "Debt1" {
 		t.Errorf("Debts = %v; want single Debt1", p.Debts)
 	}
+	if len(client.fetched) != 1 || p.Annotations["portfolios"] != "" || p.Assets[0].Annotations["portfolio"] != "" {
+		t.Errorf("fetched = %v, annotations = %v; want only the configured portfolio", client.fetched, p.Annotations)
+	}
 }
 
-// TestGetPortfolio_Panic_NoClient verifies that GetPortfolio panics without a client in context.
-func TestGetPortfolio_Panic_NoClient(t *testing.T) {
-	defer func() {
-		if r := recover(); r == nil {
-			t.Fatal("expected panic when no Kubera client in context")
+// TestSelectPortfolio verifies that portfolios are selected by name or ID and combined.
+func TestSelectPortfolio(t *testing.T) {
+	ctx := clients.WithKuberaClient(&fakeClient{portfolioId: "test-id"})(context.Background(), nil)
+
+	p, err := SelectPortfolio(ctx, "estate")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.NetWorth != types.Money(5000) || len(p.Assets) != 1 || p.Assets[0].Type != "real estate" {
+		t.Errorf("portfolio = %+v; want the estate alone", p)
+	}
+
+	p, err = SelectPortfolio(ctx, "Test Portfolio, estate-id, Estate")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.TotalAssets != types.Money(5100) || p.TotalDebts != types.Money(40) || p.NetWorth != types.Money(5060) {
+		t.Errorf("totals = %v, %v, %v; want both portfolios summed once", p.TotalAssets, p.TotalDebts, p.NetWorth)
+	}
+	if p.Annotations["portfolios"] != "Test Portfolio, Estate" {
+		t.Errorf("portfolios = %q", p.Annotations["portfolios"])
+	}
+	for _, asset := range p.Assets {
+		if want := map[string]string{"House": "Estate", "Asset1": "Test Portfolio"}[asset.Name]; asset.Annotations["portfolio"] != want {
+			t.Errorf("%s portfolio = %q; want %q", asset.Name, asset.Annotations["portfolio"], want)
+		}
+	}
+
+	for _, selector := range []string{"Brokerage", "all", " , "} {
+		if _, err := SelectPortfolio(ctx, selector); !errors.Is(err, ds.ErrInvalidInput) {
+			t.Errorf("SelectPortfolio(%q) error = %v; want invalid input", selector, err)
 		}
-	}()
-	_, _ = GetPortfolio(context.Background())
+	}
+}
+
+// TestGetPortfolio_AllByDefault verifies that every portfolio is combined when none is configured.
+func TestGetPortfolio_AllByDefault(t *testing.T) {
+	client := &fakeClient{}
+	ctx := clients.WithKuberaClient(client)(context.Background(), nil)
+	_, err := GetPortfolio(ctx)
+	if !strings.Contains(fmt.Sprint(err), "cannot combine") {
+		t.Errorf("err = %v; want portfolios in different currencies refused", err)
+	}
+	if len(client.fetched) != 0 {
+		t.Errorf("fetched = %v; want nothing fetched", client.fetched)
+	}
+}
+
+// TestGetPortfolio_NoClient verifies that GetPortfolio reports a data source that is not configured without a
+// client in context.
+func TestGetPortfolio_NoClient(t *testing.T) {
+	if _, err := GetPortfolio(context.Background()); !errors.Is(err, ds.ErrNotConfigured) {
+		t.Errorf("err = %v; want not configured", err)
+	}
+	if err := CheckCredentials(context.Background()); !errors.Is(err, ds.ErrNotConfigured) {
+		t.Errorf("CheckCredentials error = %v; want not configured", err)
+	}
+}
+
+// fakeClient implements the Kubera Client interface for testing, with a personal portfolio, an estate managed
+// for someone else in the same currency, and a portfolio in another currency.
+type fakeClient struct {
+	portfolioId string
+	// fetched are the IDs of the portfolios fetched so far.
+	fetched []string
+}
+
+// PortfolioID returns the configured portfolio ID.
+func (f *fakeClient) PortfolioID() string {
+	return f.portfolioId
+}
+
+// ListPortfolios returns the predetermined portfolios.
+func (f *fakeClient) ListPortfolios(ctx context.Context) ([]*clients.PortfolioSummary, error) {
+	return []*clients.PortfolioSummary{
+		{Id: "test-id", Name: "Test Portfolio", Currency: "USD"},
+		{Id: "estate-id", Name: "Estate", Currency: "USD"},
+		{Id: "euro-id", Name: "Euro", Currency: "EUR"},
+	}, nil
+}
+
+// GetPortfolio returns a predetermined portfolio for testing.
+func (f *fakeClient) GetPortfolio(ctx context.Context, id string) (*clients.Portfolio, error) {
+	if id == "" {
+		id = f.portfolioId
+	}
+	f.fetched = append(f.fetched, id)
+	switch id {
+	case "estate-id":
+		return &clients.Portfolio{
+			Id:   "estate-id",
+			Name: "Estate",
+			Assets: []*clients.AssetPosition{
+				{Position: clients.Position{Id: "e1", Name: "House", Type: "other", Subtype: "home", Value: clients.Value{Amount: 5000, Currency: "USD"}}},
+			},
+		}, nil
+	case "euro-id":
+		return &clients.Portfolio{Id: "euro-id", Name: "Euro"}, nil
+	}
+	return &clients.Portfolio{
+		Id:   "test-id",
+		Name: "Test Portfolio",
+		Assets: []*clients.AssetPosition{
+			{
+				Position: clients.Position{
+					Id:      "a1",
+					Name:    "Asset1",
+					Ticker:  "AST1",
+					Type:    "investment",
+					Subtype: "stock",
+					Value:   clients.Value{Amount: 100, Currency: "USD"},
+				},
+				Investable: "yes",
+				Liquidity:  "high",
+				AssetClass: "equity",
+			},
+		},
+		Debts: []*clients.DebtPosition{
+			{
+				Position: clients.Position{
+					Id:    "d1",
+					Name:  "Debt1",
+					Type:  "loan",
+					Value: clients.Value{Amount: 40, Currency: "USD"},
+				},
+			},
+		},
+	}, nil
 }
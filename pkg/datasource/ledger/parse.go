@@ -0,0 +1,597 @@
+package ledger
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/prices"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// journal is the content of a journal file and the files it includes.
+type journal struct {
+	transactions []*transaction
+	prices       prices.DB
+}
+
+// transaction is a dated entry with balanced postings.
+type transaction struct {
+	date types.Date
+	// flag is the status of the transaction: * for cleared, ! for pending or empty.
+	flag      string
+	payee     string
+	narration string
+	postings  []*posting
+}
+
+// posting moves an amount of a commodity into or out of an account.
+type posting struct {
+	account string
+	// amount is nil while the amount is elided, until the transaction is balanced.
+	amount *amount
+	// cost and price are the optional per-unit cost and price of the amount, in another commodity.
+	cost, price *amount
+}
+
+// amount is a quantity of a commodity.
+type amount struct {
+	quantity  *big.Rat
+	commodity string
+}
+
+// weight returns the amount that counts towards the balance of the transaction: the amount at its cost or
+// price if it has one, or else the amount itself.
+func (p *posting) weight() *amount {
+	unit := p.cost
+	if unit == nil {
+		unit = p.price
+	}
+	if unit == nil {
+		return p.amount
+	}
+	return &amount{quantity: new(big.Rat).Mul(p.amount.quantity, unit.quantity), commodity: unit.commodity}
+}
+
+// readJournal parses the journal file at path and every file it includes. Files named *.beancount or *.bean
+// use the Beancount syntax; all others use the ledger and hledger syntax. Amounts without a commodity are in
+// the base currency.
+func readJournal(path, base string) (*journal, error) {
+	j := &journal{prices: make(prices.DB)}
+	if err := j.readFile(path, base, make(map[string]bool)); err != nil {
+		return nil, err
+	}
+	j.prices.Sort()
+	return j, nil
+}
+
+// readFile parses a single journal file. Files that were already read are skipped, so that include cycles
+// terminate.
+func (j *journal) readFile(path, base string, visited map[string]bool) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if visited[abs] {
+		return nil
+	}
+	visited[abs] = true
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ext := strings.ToLower(filepath.Ext(path))
+	p := &parser{
+		journal:   j,
+		path:      path,
+		base:      base,
+		beancount: ext == ".beancount" || ext == ".bean",
+		visited:   visited,
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		p.line++
+		if err := p.parseLine(strings.TrimRight(scanner.Text(), "\r")); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, p.line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if err := p.finish(); err != nil {
+		return fmt.Errorf("%s:%d: %w", path, p.txnLine, err)
+	}
+	return nil
+}
+
+// parser reads the lines of a single journal file.
+type parser struct {
+	journal   *journal
+	path      string
+	base      string
+	beancount bool
+	visited   map[string]bool
+
+	line int
+	// txn is the transaction whose postings are being read, which started at txnLine.
+	txn     *transaction
+	txnLine int
+}
+
+// parseLine parses a single line. Indented lines are postings or metadata of the preceding directive; any other
+// line starts a new directive.
+func (p *parser) parseLine(line string) error {
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+	if line[0] == ' ' || line[0] == '\t' {
+		if p.txn == nil {
+			// Metadata of another directive, or a posting of a ledger automated or periodic transaction.
+			return nil
+		}
+		return p.parsePosting(strings.TrimSpace(line))
+	}
+
+	if err := p.finish(); err != nil {
+		return err
+	}
+	fields := strings.Fields(stripComment(line))
+	switch {
+	case len(fields) == 0:
+		return nil
+	case line[0] >= '0' && line[0] <= '9':
+		if p.beancount {
+			return p.parseBeancountEntry(line)
+		}
+		return p.parseLedgerEntry(line)
+	case fields[0] == "include" || fields[0] == "!include":
+		return p.include(strings.TrimSpace(strings.Join(fields[1:], " ")))
+	case fields[0] == "P" && !p.beancount:
+		if len(fields) < 4 {
+			return errors.New("price directive needs a date, a commodity and a price")
+		}
+		// The date may be followed by a time, which is ignored.
+		rest := fields[2:]
+		if len(rest) > 2 && strings.Contains(rest[0], ":") {
+			rest = rest[1:]
+		}
+		return p.addPrice(fields[1], rest[0], strings.Join(rest[1:], " "))
+	}
+	// Any other directive, such as options, account declarations or ledger automated transactions.
+	return nil
+}
+
+// finish balances the transaction being read, if any, and adds it to the journal.
+func (p *parser) finish() error {
+	txn := p.txn
+	if txn == nil {
+		return nil
+	}
+	p.txn = nil
+	if err := txn.balance(); err != nil {
+		p.line = p.txnLine
+		return err
+	}
+	p.journal.transactions = append(p.journal.transactions, txn)
+	return nil
+}
+
+// include reads the files matching a path or glob pattern relative to the directory of the current file.
+func (p *parser) include(pattern string) error {
+	if unquoted, err := strconv.Unquote(pattern); err == nil {
+		pattern = unquoted
+	}
+	if pattern == "" {
+		return errors.New("include needs a path")
+	}
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(p.path), pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("included file %s does not exist", pattern)
+	}
+	for _, path := range matches {
+		if err := p.journal.readFile(path, p.base, p.visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseBeancountEntry parses a dated Beancount directive such as
+//
+//	2024-01-05 * "Payee" "Narration"
+//	2024-01-05 price VTI 250.00 USD
+//
+// Directives other than transactions and prices are ignored.
+func (p *parser) parseBeancountEntry(line string) error {
+	tokens := tokenize(stripComment(line))
+	if len(tokens) < 2 {
+		return nil
+	}
+	switch tokens[1] {
+	case "price":
+		if len(tokens) < 4 {
+			return errors.New("price directive needs a commodity and a price")
+		}
+		return p.addPrice(tokens[0], tokens[2], strings.Join(tokens[3:], " "))
+	case "*", "!", "txn":
+	default:
+		return nil
+	}
+
+	date, err := parseDate(tokens[0])
+	if err != nil {
+		return err
+	}
+	txn := &transaction{date: date}
+	if tokens[1] != "txn" {
+		txn.flag = tokens[1]
+	}
+	var strs []string
+	for _, token := range tokens[2:] {
+		if s, err := strconv.Unquote(token); err == nil && strings.HasPrefix(token, `"`) {
+			strs = append(strs, s)
+		}
+	}
+	switch len(strs) {
+	case 0:
+	case 1:
+		txn.narration = strs[0]
+	default:
+		txn.payee, txn.narration = strs[0], strs[1]
+	}
+	p.txn, p.txnLine = txn, p.line
+	return nil
+}
+
+// parseLedgerEntry parses a ledger or hledger transaction header such as
+//
+//	2024/01/05=2024/01/07 * (1042) Payee | Note ; comment
+func (p *parser) parseLedgerEntry(line string) error {
+	line = stripComment(line)
+	dates, rest, _ := strings.Cut(line, " ")
+	if i := strings.IndexByte(dates, '\t'); i >= 0 {
+		dates, rest = dates[:i], dates[i+1:]+" "+rest
+	}
+	primary, _, _ := strings.Cut(dates, "=")
+	date, err := parseDate(primary)
+	if err != nil {
+		return err
+	}
+	txn := &transaction{date: date}
+
+	rest = strings.TrimSpace(rest)
+	if rest != "" && (rest[0] == '*' || rest[0] == '!') {
+		txn.flag, rest = rest[:1], strings.TrimSpace(rest[1:])
+	}
+	if strings.HasPrefix(rest, "(") {
+		if i := strings.IndexByte(rest, ')'); i >= 0 {
+			rest = strings.TrimSpace(rest[i+1:])
+		}
+	}
+	// hledger separates the payee from a note with a pipe.
+	if payee, note, ok := strings.Cut(rest, "|"); ok {
+		txn.payee, txn.narration = strings.TrimSpace(payee), strings.TrimSpace(note)
+	} else {
+		txn.payee = rest
+	}
+	p.txn, p.txnLine = txn, p.line
+	return nil
+}
+
+// metadataPattern matches Beancount metadata lines, such as `receipt: "receipts/2024-01-05.pdf"`.
+var metadataPattern = regexp.MustCompile(`^[a-z][a-zA-Z0-9_-]*:(\s|$)`)
+
+// parsePosting parses an indented line of a transaction. Postings to virtual accounts in parentheses, which
+// ledger does not balance, are skipped; virtual accounts in brackets are treated like regular accounts.
+func (p *parser) parsePosting(line string) error {
+	line = strings.TrimSpace(stripComment(line))
+	if line == "" || (p.beancount && metadataPattern.MatchString(line)) {
+		return nil
+	}
+	if len(line) > 1 && (line[0] == '*' || line[0] == '!') && (line[1] == ' ' || line[1] == '\t') {
+		line = strings.TrimSpace(line[1:])
+	}
+
+	var account, rest string
+	if p.beancount {
+		account, rest, _ = strings.Cut(strings.Replace(line, "\t", " ", 1), " ")
+	} else {
+		// Ledger account names may contain single spaces, so the amount is separated by two spaces or a tab.
+		end := len(line)
+		if i := strings.Index(line, "  "); i >= 0 {
+			end = i
+		}
+		if i := strings.IndexByte(line, '\t'); i >= 0 && i < end {
+			end = i
+		}
+		account, rest = line[:end], line[end:]
+	}
+	switch {
+	case strings.HasPrefix(account, "(") && strings.HasSuffix(account, ")"):
+		return nil
+	case strings.HasPrefix(account, "[") && strings.HasSuffix(account, "]"):
+		account = account[1 : len(account)-1]
+	}
+
+	post, err := p.parsePostingAmount(rest)
+	if err != nil {
+		return err
+	}
+	post.account = account
+	p.txn.postings = append(p.txn.postings, post)
+	// Like ledger, and Beancount with its implicit prices plugin, record the prices of postings.
+	if unit := cmp.Or(post.price, post.cost); unit != nil {
+		p.journal.prices.Add(p.txn.date, post.amount.commodity, unit.commodity, unit.quantity)
+	}
+	return nil
+}
+
+// parsePostingAmount parses the amount of a posting together with its optional cost in braces and price after
+// @ (per unit) or @@ (total). Ledger balance assertions following = are ignored.
+func (p *parser) parsePostingAmount(s string) (*posting, error) {
+	if i := strings.IndexByte(s, '='); i >= 0 {
+		s = s[:i]
+	}
+	var priceStr, costStr string
+	priceTotal, costTotal := false, false
+	if i := strings.Index(s, "@@"); i >= 0 {
+		s, priceStr, priceTotal = s[:i], s[i+2:], true
+	} else if i := strings.IndexByte(s, '@'); i >= 0 {
+		s, priceStr = s[:i], s[i+1:]
+	}
+	if i := strings.IndexByte(s, '{'); i >= 0 {
+		j := strings.LastIndexByte(s, '}')
+		if j < i {
+			return nil, fmt.Errorf("unterminated cost in %q", s)
+		}
+		costStr = s[i+1 : j]
+		if strings.HasPrefix(costStr, "{") && strings.HasSuffix(costStr, "}") {
+			costStr, costTotal = costStr[1:len(costStr)-1], true
+		}
+		// A cost may be followed by the acquisition date and a label.
+		costStr, _, _ = strings.Cut(costStr, ",")
+		s = s[:i]
+	}
+
+	post := &posting{}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return post, nil
+	}
+	amt, err := p.parseAmount(s)
+	if err != nil {
+		return nil, err
+	}
+	post.amount = amt
+	if post.cost, err = p.parseUnit(costStr, costTotal, amt); err != nil {
+		return nil, err
+	}
+	if post.price, err = p.parseUnit(priceStr, priceTotal, amt); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// parseUnit parses a cost or price of amt, converting a total into a per-unit value. It returns nil if s is
+// blank.
+func (p *parser) parseUnit(s string, total bool, amt *amount) (*amount, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	unit, err := p.parseAmount(s)
+	if err != nil {
+		return nil, err
+	}
+	if total {
+		if amt.quantity.Sign() == 0 {
+			return nil, errors.New("total price of a zero amount")
+		}
+		unit.quantity.Quo(unit.quantity, new(big.Rat).Abs(amt.quantity))
+	}
+	return unit, nil
+}
+
+// addPrice records the price of commodity declared on date.
+func (p *parser) addPrice(date, commodity, price string) error {
+	d, err := parseDate(date)
+	if err != nil {
+		return err
+	}
+	value, err := p.parseAmount(price)
+	if err != nil {
+		return err
+	}
+	p.journal.prices.Add(d, p.normalize(commodity), value.commodity, value.quantity)
+	return nil
+}
+
+// currencySymbols maps common currency symbols used as ledger commodities to ISO 4217 codes.
+var currencySymbols = map[string]string{"$": "USD", "€": "EUR", "£": "GBP"}
+
+// normalize returns the commodity code for a commodity as written in a journal.
+func (p *parser) normalize(commodity string) string {
+	if unquoted, err := strconv.Unquote(commodity); err == nil {
+		commodity = unquoted
+	}
+	if code, ok := currencySymbols[commodity]; ok {
+		return code
+	}
+	if commodity == "" {
+		return p.base
+	}
+	return commodity
+}
+
+// parseAmount parses an amount such as 12.50 USD, $-12.50, -$12.50, 1,000 "VANGUARD 500" or 12.50, the last
+// of which is in the base currency.
+func (p *parser) parseAmount(s string) (*amount, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	negative := false
+	if strings.HasPrefix(s, "-") {
+		negative, s = true, strings.TrimSpace(s[1:])
+	} else if strings.HasPrefix(s, "+") {
+		s = strings.TrimSpace(s[1:])
+	}
+
+	var commodity string
+	if s != "" && !isNumber(s[0]) {
+		commodity, s = readCommodity(s, true)
+		s = strings.TrimSpace(s)
+	}
+	end := 0
+	for end < len(s) && (isNumber(s[end]) || (end == 0 && (s[end] == '-' || s[end] == '+'))) {
+		end++
+	}
+	number, rest := strings.ReplaceAll(s[:end], ",", ""), strings.TrimSpace(s[end:])
+	if rest != "" {
+		if commodity != "" {
+			return nil, fmt.Errorf("invalid amount %q", strings.TrimSpace(orig))
+		}
+		commodity, rest = readCommodity(rest, false)
+		if strings.TrimSpace(rest) != "" {
+			return nil, fmt.Errorf("invalid amount %q", strings.TrimSpace(orig))
+		}
+	}
+	quantity, ok := new(big.Rat).SetString(strings.TrimPrefix(number, "+"))
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", strings.TrimSpace(orig))
+	}
+	if negative {
+		quantity.Neg(quantity)
+	}
+	return &amount{quantity: quantity, commodity: p.normalize(commodity)}, nil
+}
+
+// readCommodity reads a commodity from the start of s, which is either quoted or ends at whitespace. A commodity
+// preceding the number also ends where the number starts.
+func readCommodity(s string, prefix bool) (commodity, rest string) {
+	if strings.HasPrefix(s, `"`) {
+		if i := strings.IndexByte(s[1:], '"'); i >= 0 {
+			return s[:i+2], s[i+2:]
+		}
+	}
+	end := 0
+	for end < len(s) && s[end] != ' ' && s[end] != '\t' && !(prefix && (isNumber(s[end]) || s[end] == '-')) {
+		end++
+	}
+	return s[:end], s[end:]
+}
+
+func isNumber(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '.' || c == ','
+}
+
+// balance replaces an elided posting amount by the amounts that balance the transaction, one per commodity.
+// Transactions that do not balance are accepted as is.
+func (t *transaction) balance() error {
+	elided := -1
+	sums := make(map[string]*big.Rat)
+	for i, post := range t.postings {
+		if post.amount == nil {
+			if elided >= 0 {
+				return errors.New("transaction has more than one posting without an amount")
+			}
+			elided = i
+			continue
+		}
+		w := post.weight()
+		if sums[w.commodity] == nil {
+			sums[w.commodity] = new(big.Rat)
+		}
+		sums[w.commodity].Add(sums[w.commodity], w.quantity)
+	}
+	if elided < 0 {
+		return nil
+	}
+
+	account := t.postings[elided].account
+	t.postings = slices.Delete(t.postings, elided, elided+1)
+	commodities := make([]string, 0, len(sums))
+	for commodity := range sums {
+		commodities = append(commodities, commodity)
+	}
+	slices.Sort(commodities)
+	for _, commodity := range commodities {
+		if sums[commodity].Sign() == 0 {
+			continue
+		}
+		t.postings = append(t.postings, &posting{
+			account: account,
+			amount:  &amount{quantity: new(big.Rat).Neg(sums[commodity]), commodity: commodity},
+		})
+	}
+	return nil
+}
+
+// parseDate parses a date such as 2024-01-05, 2024/01/05 or 2024.1.5.
+func parseDate(s string) (types.Date, error) {
+	t, err := time.Parse("2006-1-2", strings.NewReplacer("/", "-", ".", "-").Replace(s))
+	if err != nil {
+		return types.Date{}, fmt.Errorf("invalid date %q", s)
+	}
+	return types.Date(t), nil
+}
+
+// stripComment removes a trailing comment starting with a semicolon outside of a quoted string.
+func stripComment(s string) string {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case ';':
+			if !quoted {
+				return s[:i]
+			}
+		}
+	}
+	return s
+}
+
+// tokenize splits s at whitespace, keeping quoted strings with their quotes as single tokens.
+func tokenize(s string) []string {
+	var tokens []string
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return tokens
+		}
+		end := strings.IndexAny(s, " \t")
+		if s[0] == '"' {
+			end = -1
+			for i := 1; i < len(s); i++ {
+				if s[i] == '\\' {
+					i++
+				} else if s[i] == '"' {
+					end = i + 1
+					break
+				}
+			}
+		}
+		if end < 0 {
+			end = len(s)
+		}
+		tokens = append(tokens, s[:end])
+		s = s[end:]
+	}
+}
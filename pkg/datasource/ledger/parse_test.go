@@ -0,0 +1,114 @@
+package ledger
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/prices"
+)
+
+func TestReadJournal_Beancount(t *testing.T) {
+	j, err := readJournal("testdata/beancount/main.beancount", "USD")
+	if err != nil {
+		t.Fatalf("readJournal error: %v", err)
+	}
+	if len(j.transactions) != 7 {
+		t.Fatalf("len(transactions) = %d; want 7 from main file and included files", len(j.transactions))
+	}
+
+	supplies := j.transactions[1]
+	if supplies.payee != "Jita 4-4 Market" || supplies.narration != "Ammo & fuel" || supplies.flag != "*" {
+		t.Errorf("transaction = %+v; want payee and narration", supplies)
+	}
+	fuel := supplies.postings[2]
+	if fuel.account != "Expenses:Ships:Fuel" || fuel.amount.quantity.Cmp(big.NewRat(75, 2)) != 0 {
+		t.Errorf("elided posting = %s %v; want 37.50 USD balancing the transaction", fuel.account, fuel.amount)
+	}
+	if coffee := j.transactions[2]; coffee.flag != "!" || coffee.narration != "Amarr Coffee" || coffee.postings[1].amount.commodity != "EUR" {
+		t.Errorf("transaction = %+v; want pending with narration only and elided amount in EUR", coffee)
+	}
+	if price, ok := j.prices.Lookup("VTI", "USD", mustDate(t, "2024-01-20")); !ok || price.Cmp(big.NewRat(200, 1)) != 0 {
+		t.Errorf("price of VTI = %v, %v; want 200 from the price directive", price, ok)
+	}
+}
+
+func TestReadJournal_Ledger(t *testing.T) {
+	j, err := readJournal("testdata/household.journal", "USD")
+	if err != nil {
+		t.Fatalf("readJournal error: %v", err)
+	}
+	if len(j.transactions) != 4 {
+		t.Fatalf("len(transactions) = %d; want 4 without the automated transaction", len(j.transactions))
+	}
+
+	bounty := j.transactions[0]
+	if bounty.payee != "Concord Navy" || bounty.narration != "January bounty" || bounty.flag != "*" {
+		t.Errorf("transaction = %+v; want payee and note split at the pipe", bounty)
+	}
+	if income := bounty.postings[1]; income.amount.quantity.Cmp(big.NewRat(-5000, 1)) != 0 || income.amount.commodity != "USD" {
+		t.Errorf("elided posting = %v; want -5000 USD", income.amount)
+	}
+
+	supplies := j.transactions[1]
+	if supplies.date.String() != "2024-01-10" || supplies.payee != "Jita 4-4 Market" {
+		t.Errorf("transaction = %+v; want primary date and payee without comment", supplies)
+	}
+	if len(supplies.postings) != 3 {
+		t.Fatalf("postings = %d; want 3 without the unbalanced virtual posting", len(supplies.postings))
+	}
+	if post := supplies.postings[0]; post.account != "Expenses:Ships and Fuel" {
+		t.Errorf("account = %q; want name with spaces", post.account)
+	}
+	if card := supplies.postings[2]; card.account != "Liabilities:Credit Card" || card.amount.quantity.Cmp(big.NewRat(-112, 1)) != 0 {
+		t.Errorf("posting = %s %v; want balanced virtual account without balance assertion", card.account, card.amount)
+	}
+	if w := supplies.postings[1].weight(); w.commodity != "USD" || w.quantity.Cmp(big.NewRat(12, 1)) != 0 {
+		t.Errorf("weight = %v; want 12 USD at the per-unit price", w)
+	}
+	if price, ok := j.prices.Lookup("VTI", "USD", mustDate(t, "2024-01-15")); !ok || price.Cmp(big.NewRat(200, 1)) != 0 {
+		t.Errorf("price of VTI = %v, %v; want 200 from the total price of the purchase", price, ok)
+	}
+}
+
+func TestReadJournal_Errors(t *testing.T) {
+	for name, content := range map[string]string{
+		"two elided postings": "2024-01-01 * \"x\"\n  Assets:Cash\n  Expenses:Food\n",
+		"invalid amount":      "2024-01-01 * \"x\"\n  Assets:Cash  twelve dollars\n",
+		"invalid date":        "2024-13-01 * \"x\"\n",
+		"missing include":     "include \"missing.beancount\"\n",
+	} {
+		path := filepath.Join(t.TempDir(), "main.beancount")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		_, err := readJournal(path, "USD")
+		if err == nil || !strings.Contains(err.Error(), "main.beancount:") {
+			t.Errorf("%s: err = %v; want error with location", name, err)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	p := &parser{base: "USD"}
+	for in, want := range map[string]string{
+		"12.50 USD":         "12.5 USD",
+		"$-12.50":           "-12.5 USD",
+		"-$1,234.5":         "-1234.5 USD",
+		"EUR 3":             "3 EUR",
+		"-7":                "-7 USD",
+		`10 "VANGUARD 500"`: "10 VANGUARD 500",
+		"0.123456 BTC":      "0.123456 BTC",
+		"£5":                "5 GBP",
+	} {
+		got, err := p.parseAmount(in)
+		if err != nil || prices.Format(got.quantity, got.commodity) != want {
+			t.Errorf("parseAmount(%q) = %v, %v; want %s", in, got, err, want)
+		}
+	}
+	if _, err := p.parseAmount("12 USD EUR"); err == nil {
+		t.Error("expected error for amount with two commodities")
+	}
+}
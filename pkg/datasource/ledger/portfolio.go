@@ -0,0 +1,112 @@
+package ledger
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/prices"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// holding is the balance of a commodity in an account.
+type holding struct {
+	account   string
+	commodity string
+}
+
+// GetPortfolio implements ds.GetPortfolioFunc. The balance of every commodity held in an asset or liability
+// account as of today becomes a position valued in the base currency at the latest declared price. Balances in
+// the base currency are cash, while other commodities are investments with the commodity as their ticker.
+// Holdings without a price are included with a zero value and a note, so that they are not silently lost.
+func (s *Source) GetPortfolio(ctx context.Context) (portfolio *types.Portfolio, err error) {
+	j, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(ctx, "ledger.portfolio")
+	defer func() { tracing.End(span, err) }()
+
+	now := s.now()
+	today := types.Date(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	balances := make(map[holding]*big.Rat)
+	for _, txn := range j.transactions {
+		if today.Before(txn.date) {
+			continue
+		}
+		for _, post := range txn.postings {
+			if t := accountType(post.account); t != assets && t != liabilities {
+				continue
+			}
+			h := holding{account: post.account, commodity: post.amount.commodity}
+			if balances[h] == nil {
+				balances[h] = new(big.Rat)
+			}
+			balances[h].Add(balances[h], post.amount.quantity)
+		}
+	}
+
+	portfolio = types.NewPortfolio()
+	unpriced := 0
+	for h, balance := range balances {
+		if balance.Sign() == 0 {
+			continue
+		}
+		amt := &amount{quantity: balance, commodity: h.commodity}
+		value, priced := j.prices.Convert(amt.quantity, amt.commodity, s.base, today)
+		if !priced {
+			unpriced++
+			value = new(big.Rat)
+		}
+		name := positionName(h.account)
+		if accountType(h.account) == liabilities {
+			// Liabilities have credit balances, which are negative in the journal.
+			debt := types.NewDebtPosition(name, "liability", -prices.ToMoney(value))
+			annotateHolding(&debt.Position, h, amt, s.base, priced)
+			portfolio.AddDebt(debt)
+			continue
+		}
+		ticker, assetType := "", "cash"
+		if h.commodity != s.base {
+			ticker, assetType = h.commodity, "investment"
+		}
+		asset := types.NewAssetPosition(name, ticker, assetType, "", prices.ToMoney(value))
+		annotateHolding(&asset.Position, h, amt, s.base, priced)
+		portfolio.AddAsset(asset)
+	}
+	portfolio.Sort()
+	if unpriced > 0 {
+		logging.FromContext(ctx).WarnContext(ctx, "Journal holdings have no price", "holdings", unpriced)
+	}
+	span.SetAttributes(attribute.Int("assets", len(portfolio.Assets)), attribute.Int("debts", len(portfolio.Debts)))
+	logging.FromContext(ctx).DebugContext(ctx, "Computed journal portfolio",
+		"assets", len(portfolio.Assets), "debts", len(portfolio.Debts))
+	return portfolio, nil
+}
+
+// positionName returns the name of the position of an account: its components below the root, so that
+// Assets:Bank:Checking becomes Bank:Checking.
+func positionName(account string) string {
+	_, name, ok := strings.Cut(account, ":")
+	if !ok {
+		return account
+	}
+	return name
+}
+
+// annotateHolding annotates a position with the account and, for commodities other than the base currency, the
+// quantity held.
+func annotateHolding(p *types.Position, h holding, amt *amount, base string, priced bool) {
+	p.Annotate("account", h.account)
+	if h.commodity != base {
+		p.Annotate("quantity", prices.Format(amt.quantity, amt.commodity))
+	}
+	if !priced {
+		p.Annotate("note", "no price of "+h.commodity+" in "+base+" is declared in the journal")
+	}
+}
@@ -0,0 +1,55 @@
+package ledger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func TestGetPortfolio(t *testing.T) {
+	src := NewSource("testdata/beancount/main.beancount", "USD")
+	src.now = func() time.Time { return time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC) }
+
+	portfolio, err := src.GetPortfolio(context.Background())
+	if err != nil {
+		t.Fatalf("GetPortfolio error: %v", err)
+	}
+
+	if len(portfolio.Assets) != 2 {
+		t.Fatalf("assets = %+v; want checking and brokerage", portfolio.Assets)
+	}
+	checking, fund := portfolio.Assets[0], portfolio.Assets[1]
+	if fund.Name != "Brokerage" || fund.Ticker != "VTI" || fund.Type != "investment" || fund.Value != 25000000 ||
+		fund.Annotations["quantity"] != "10 VTI" {
+		t.Errorf("asset = %+v; want 10 VTI at the latest price of 250.00", fund)
+	}
+	if checking.Name != "Bank:Checking" || checking.Type != "cash" || checking.Value != 40000000 {
+		t.Errorf("asset = %+v; want 4000.00 cash without the future transaction", checking)
+	}
+
+	var debts types.Money
+	for _, debt := range portfolio.Debts {
+		debts += debt.Value
+	}
+	if len(portfolio.Debts) != 2 || debts != 1578900 {
+		t.Errorf("debts = %+v; want card balances in USD and EUR totaling 157.89", portfolio.Debts)
+	}
+	if portfolio.NetWorth != 65000000-1578900 {
+		t.Errorf("NetWorth = %d; want assets minus debts", portfolio.NetWorth)
+	}
+}
+
+func TestGetPortfolio_Unpriced(t *testing.T) {
+	src := NewSource("testdata/household.journal", "EUR")
+	portfolio, err := src.GetPortfolio(context.Background())
+	if err != nil {
+		t.Fatalf("GetPortfolio error: %v", err)
+	}
+	for _, asset := range portfolio.Assets {
+		if asset.Ticker == "VTI" && (asset.Value != 0 || asset.Annotations["note"] == "") {
+			t.Errorf("asset = %+v; want zero value with a note for a holding without a price in EUR", asset)
+		}
+	}
+}
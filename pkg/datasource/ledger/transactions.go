@@ -0,0 +1,215 @@
+// Package ledger implements a data source that reads plain-text accounting journals kept with Beancount,
+// ledger or hledger. Income and expense accounts become categories of transactions, while asset and liability
+// accounts make up the portfolio.
+package ledger
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io/fs"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/internal/prices"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Source reads a journal file and the files it includes. The journal is re-read on every call, so changes are
+// picked up without a restart.
+type Source struct {
+	path string
+	base string
+	now  func() time.Time
+}
+
+// NewSource creates a Source that reads the journal at path. Amounts in other commodities are converted into
+// the base currency using the prices declared in the journal.
+func NewSource(path, baseCurrency string) *Source {
+	return &Source{path: path, base: baseCurrency, now: time.Now}
+}
+
+// Account types, identified by the first component of account names.
+const (
+	assets      = "assets"
+	liabilities = "liabilities"
+	equity      = "equity"
+	income      = "income"
+	expenses    = "expenses"
+)
+
+// accountType returns the type of an account. The root names of Beancount are matched case-insensitively,
+// together with the common singular forms and Revenue for income.
+func accountType(account string) string {
+	root, _, _ := strings.Cut(account, ":")
+	switch strings.ToLower(root) {
+	case "assets", "asset":
+		return assets
+	case "liabilities", "liability":
+		return liabilities
+	case "equity":
+		return equity
+	case "income", "revenue", "revenues":
+		return income
+	case "expenses", "expense":
+		return expenses
+	default:
+		return ""
+	}
+}
+
+// categoryPath returns the category of an income or expense account: its components below the root.
+func categoryPath(account string) []string {
+	parts := strings.Split(account, ":")[1:]
+	if len(parts) == 0 {
+		return []string{types.UncategorizedName}
+	}
+	return parts
+}
+
+// GetCategorizedTransactions implements ds.GetCategorizedTransactionsFunc. Every posting to an income or
+// expense account becomes a transaction in the category named by the account below its root, so
+// Expenses:Food:Groceries becomes Food/Groceries. Transactions between asset and liability accounts are
+// reported as ignored transfers, while those involving equity accounts, such as opening balances, are left out.
+func (s *Source) GetCategorizedTransactions(ctx context.Context, interval ds.DateRange) (result *types.Categories, err error) {
+	j, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(ctx, "ledger.categorize", attribute.Int("transactions", len(j.transactions)))
+	defer func() { tracing.End(span, err) }()
+
+	result = types.NewCategories()
+	for _, txn := range j.transactions {
+		if txn.date.Before(interval.StartDate) || interval.EndDate.Before(txn.date) {
+			continue
+		}
+		if err := s.categorize(result, j, txn); err != nil {
+			return nil, err
+		}
+	}
+	logging.FromContext(ctx).DebugContext(ctx, "Categorized ledger transactions", "transactions", result.TransactionCount())
+	return result, nil
+}
+
+// categorize adds the postings of a journal transaction to result.
+func (s *Source) categorize(result *types.Categories, j *journal, txn *transaction) error {
+	payee, description := txn.payee, txn.narration
+	if payee == "" {
+		payee, description = txn.narration, ""
+	}
+	var account string
+	transfer, involvesEquity := true, false
+	for _, post := range txn.postings {
+		switch accountType(post.account) {
+		case assets, liabilities:
+			if account == "" {
+				account = post.account
+			}
+		case income, expenses:
+			transfer = false
+		case equity:
+			involvesEquity = true
+		}
+	}
+
+	newTransaction := func(post *posting, amount types.Money) *types.Transaction {
+		tx := types.NewTransaction(txn.date, payee, amount)
+		tx.Description = description
+		if account != "" {
+			tx.Annotate("account", account)
+		}
+		if txn.flag == "!" {
+			tx.Annotate("pending", "true")
+		}
+		if post != nil && post.amount.commodity != s.base {
+			tx.Annotate("original_amount", prices.Format(post.amount.quantity, post.amount.commodity))
+		}
+		return tx
+	}
+
+	if transfer {
+		if involvesEquity || account == "" {
+			return nil
+		}
+		// A transfer is reported once, with the total amount moved.
+		total := new(big.Rat)
+		for _, post := range txn.postings {
+			value, err := s.value(j, txn, post)
+			if err != nil {
+				return err
+			}
+			if value.Sign() > 0 {
+				total.Add(total, value)
+			}
+		}
+		return result.Ignored.GetOrCreatePath("Transfers").AddTransaction(newTransaction(nil, prices.ToMoney(total)))
+	}
+
+	for _, post := range txn.postings {
+		var bucket *types.Category
+		switch accountType(post.account) {
+		case income:
+			bucket = result.Income
+		case expenses:
+			bucket = result.Expenses
+		default:
+			continue
+		}
+		// Journal amounts are positive for expenses and negative for income, which matches the domain convention.
+		value, err := s.value(j, txn, post)
+		if err != nil {
+			return err
+		}
+		tx := newTransaction(post, prices.ToMoney(value))
+		if err := bucket.GetOrCreatePath(categoryPath(post.account)...).AddTransaction(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// value returns the value of a posting in the base currency: its cost or price if it has one in the base
+// currency, or else its amount converted using the price closest to the date of the transaction.
+func (s *Source) value(j *journal, txn *transaction, post *posting) (*big.Rat, error) {
+	if w := post.weight(); w.commodity == s.base {
+		return w.quantity, nil
+	}
+	value, ok := j.prices.Convert(post.amount.quantity, post.amount.commodity, s.base, txn.date)
+	if !ok {
+		return nil, ds.Errorf(ds.ErrUpstreamUnavailable, "no price of %s in %s for transaction %q of %s",
+			post.amount.commodity, s.base, cmp.Or(txn.payee, txn.narration), txn.date).
+			WithHint("the journal on the server lacks a commodity price; ask the user to declare it")
+	}
+	return value, nil
+}
+
+// Check implements ds.CheckFunc by verifying that the journal can be read and parsed.
+func (s *Source) Check(ctx context.Context) error {
+	_, err := s.read(ctx)
+	return err
+}
+
+// read parses the journal.
+func (s *Source) read(ctx context.Context) (j *journal, err error) {
+	ctx, span := tracing.Start(ctx, "ledger.read")
+	defer func() { tracing.End(span, err) }()
+
+	j, err = readJournal(s.path, s.base)
+	if err != nil {
+		hint := "the journal on the server cannot be parsed; ask the user to fix it"
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			hint = "the journal configured on the server cannot be read; ask the user to fix it"
+		}
+		return nil, ds.Errorf(ds.ErrUpstreamUnavailable, "failed to read journal: %w", err).WithHint(hint)
+	}
+	span.SetAttributes(attribute.Int("transactions", len(j.transactions)), attribute.Int("prices", len(j.prices)))
+	logging.FromContext(ctx).DebugContext(ctx, "Read journal", "transactions", len(j.transactions))
+	return j, nil
+}
@@ -0,0 +1,101 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func mustDate(t *testing.T, s string) types.Date {
+	t.Helper()
+	d, err := types.ParseDate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestGetCategorizedTransactions_Beancount(t *testing.T) {
+	src := NewSource("testdata/beancount/main.beancount", "USD")
+	interval := ds.DateRange{StartDate: mustDate(t, "2024-01-01"), EndDate: mustDate(t, "2024-02-29")}
+
+	result, err := src.GetCategorizedTransactions(context.Background(), interval)
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+
+	bounties := result.Income.FindSubcategory("Bounties")
+	if bounties == nil || bounties.TotalAmount != -50000000 {
+		t.Errorf("Income/Bounties = %+v; want income of 5000.00 as negative amount", bounties)
+	}
+	ships := result.Expenses.FindSubcategory("Ships")
+	if ships == nil || ships.TotalAmount != 1375000 || len(ships.FindSubcategory("Fuel").Transactions) != 1 {
+		t.Fatalf("Expenses/Ships = %+v; want ammo and elided fuel postings totaling 137.50", ships)
+	}
+	ammo := ships.FindSubcategory("Ammo").Transactions[0]
+	if ammo.Payee != "Jita 4-4 Market" || ammo.Description != "Ammo & fuel" ||
+		ammo.Annotations["account"] != "Liabilities:CreditCard:Caldari" {
+		t.Errorf("transaction = %+v; want payee, narration and funding account", ammo)
+	}
+
+	food := result.Expenses.FindSubcategory("Food")
+	if food == nil || food.TotalAmount != 44000 {
+		t.Fatalf("Expenses/Food = %+v; want 4.00 EUR converted at 1.10", food)
+	}
+	coffee := food.Transactions[0]
+	if coffee.Payee != "Amarr Coffee" || coffee.Annotations["pending"] != "true" || coffee.Annotations["original_amount"] != "4 EUR" {
+		t.Errorf("transaction = %+v; want pending transaction with original amount", coffee)
+	}
+
+	transfers := result.Ignored.FindSubcategory("Transfers")
+	if transfers == nil || len(transfers.Transactions) != 1 || transfers.TotalAmount != 20000000 {
+		t.Errorf("Ignored/Transfers = %+v; want the fund purchase at cost, without opening balances", transfers)
+	}
+	if streaming := result.Expenses.GetOrCreatePath("Subscriptions", "Streaming"); streaming.TotalAmount != 159900 {
+		t.Errorf("Subscriptions/Streaming = %+v; want Netflix", streaming)
+	}
+}
+
+func TestGetCategorizedTransactions_Ledger(t *testing.T) {
+	src := NewSource("testdata/household.journal", "USD")
+	interval := ds.DateRange{StartDate: mustDate(t, "2024-01-01"), EndDate: mustDate(t, "2024-01-31")}
+
+	result, err := src.GetCategorizedTransactions(context.Background(), interval)
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+	ships := result.Expenses.FindSubcategory("Ships and Fuel")
+	if ships == nil || ships.TotalAmount != 1120000 || len(ships.Transactions) != 2 {
+		t.Errorf("Expenses/Ships and Fuel = %+v; want two postings totaling 112.00", ships)
+	}
+	if got := result.Ignored.TransactionCount(); got != 2 {
+		t.Errorf("Ignored transactions = %d; want fund purchase and card payment", got)
+	}
+}
+
+func TestGetCategorizedTransactions_MissingPrice(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.beancount")
+	content := "2024-01-01 * \"Jita\"\n  Assets:Cash  -5 ISK\n  Expenses:Food\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	interval := ds.DateRange{StartDate: mustDate(t, "2024-01-01"), EndDate: mustDate(t, "2024-01-31")}
+	_, err := NewSource(path, "USD").GetCategorizedTransactions(context.Background(), interval)
+	if !errors.Is(err, ds.ErrUpstreamUnavailable) {
+		t.Errorf("err = %v; want upstream unavailable for a commodity without price", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := NewSource("testdata/household.journal", "USD").Check(context.Background()); err != nil {
+		t.Errorf("Check error: %v", err)
+	}
+	if err := NewSource("testdata/missing.journal", "USD").Check(context.Background()); err == nil {
+		t.Error("expected error for missing journal")
+	}
+}
@@ -0,0 +1,73 @@
+package lunchmoney
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+
+	lmapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/lunch_money"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// ListCategories is a DataSource function that fetches the category hierarchy from LunchMoney API and converts
+// it into domain Category trees, with category groups as roots. Income and exclusion flags are attached as
+// annotations, and archived categories are skipped.
+var ListCategories ds.ListCategoriesFunc = func(ctx context.Context) ([]*types.Category, error) {
+	client, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lmCats, err := client.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Categories nested under a group are also present at the top level of the index; only keep actual roots
+	children := make(map[int64]bool)
+	for _, lmCat := range lmCats {
+		for _, child := range lmCat.Children {
+			children[child.Id] = true
+		}
+	}
+	roots := make([]*lmapi.Category, 0, len(lmCats))
+	for id, lmCat := range lmCats {
+		if !children[id] {
+			roots = append(roots, lmCat)
+		}
+	}
+
+	return buildCategoryTrees(roots), nil
+}
+
+// buildCategoryTrees converts LunchMoney categories and their children into domain Categories,
+// ordered the same way LunchMoney orders them.
+func buildCategoryTrees(lmCats []*lmapi.Category) []*types.Category {
+	sorted := slices.SortedFunc(slices.Values(lmCats), func(a, b *lmapi.Category) int {
+		return cmp.Or(cmp.Compare(a.Order, b.Order), cmp.Compare(a.Name, b.Name))
+	})
+
+	result := make([]*types.Category, 0, len(sorted))
+	for _, lmCat := range sorted {
+		if lmCat.IsArchived {
+			continue
+		}
+		cat := types.NewCategory(lmCat.Name)
+		cat.Description = lmCat.Description
+		annotateCategoryFlags(cat, lmCat)
+		for _, sub := range buildCategoryTrees(lmCat.Children) {
+			_ = cat.AddSubcategory(sub)
+		}
+		result = append(result, cat)
+	}
+	return result
+}
+
+// annotateCategoryFlags records the LunchMoney category flags that affect how its transactions are reported.
+func annotateCategoryFlags(cat *types.Category, lmCat *lmapi.Category) {
+	cat.Annotate("is_income", strconv.FormatBool(lmCat.IsIncome))
+	cat.Annotate("exclude_from_budget", strconv.FormatBool(lmCat.ExcludeFromBudget))
+	cat.Annotate("exclude_from_totals", strconv.FormatBool(lmCat.ExcludeFromTotals))
+}
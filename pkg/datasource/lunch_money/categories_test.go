@@ -0,0 +1,41 @@
+package lunchmoney
+
+import (
+	"testing"
+
+	lmapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/lunch_money"
+)
+
+func TestListCategories_BuildsTreeFromGroups(t *testing.T) {
+	food := &lmapi.Category{Id: 2, Name: "Food", Description: "groceries", Order: 2}
+	rent := &lmapi.Category{Id: 3, Name: "Rent", Order: 1, ExcludeFromBudget: true}
+	old := &lmapi.Category{Id: 4, Name: "Old", IsArchived: true}
+	group := &lmapi.Category{Id: 1, Name: "Living", Order: 1, Children: []*lmapi.Category{food, rent, old}}
+	salary := &lmapi.Category{Id: 5, Name: "Salary", Order: 0, IsIncome: true}
+	client := &fakeClient{cats: lmapi.Categories{1: group, 2: food, 3: rent, 4: old, 5: salary}}
+
+	roots, err := ListCategories(contextWithClient(client))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(roots) != 2 || roots[0].Name != "Salary" || roots[1].Name != "Living" {
+		t.Fatalf("roots = %v; want [Salary Living]", roots)
+	}
+	if roots[0].Annotations["is_income"] != "true" {
+		t.Errorf("Salary annotations = %v; want is_income=true", roots[0].Annotations)
+	}
+
+	living := roots[1]
+	if len(living.Subcategories) != 2 {
+		t.Fatalf("Living.Subcategories = %v; want Rent and Food", living.Subcategories)
+	}
+	if got := living.Subcategories[0]; got.Name != "Rent" || got.Annotations["exclude_from_budget"] != "true" {
+		t.Errorf("Subcategories[0] = %+v; want Rent excluded from budget", got)
+	}
+	if got := living.Subcategories[1]; got.Name != "Food" || got.Description != "groceries" {
+		t.Errorf("Subcategories[1] = %+v; want Food with description", got)
+	}
+	if living.Subcategories[1].Parent != living {
+		t.Errorf("Food.Parent = %v; want Living", living.Subcategories[1].Parent)
+	}
+}
@@ -0,0 +1,18 @@
+package lunchmoney
+
+import (
+	"context"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+)
+
+// CheckCredentials verifies that LunchMoney is reachable and accepts the API token by listing tags,
+// which is the cheapest authenticated call.
+var CheckCredentials ds.CheckFunc = func(ctx context.Context) error {
+	client, err := clientFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.ListTags(ctx)
+	return err
+}
@@ -2,32 +2,28 @@ package lunchmoney
 
 import (
 	"context"
-	"fmt"
-	"log"
 	"net/http"
-	"os"
 
 	lmapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/lunch_money"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/secrets"
 )
 
-// InjectCredentialsFromEnvironment returns an HTTP context injector function that
-// loads the LunchMoney API token from the environment variable "LUNCHMONEY_TOKEN".
-// It returns a function that injects a LunchMoney client configured with the token
-// into the context of incoming HTTP requests. Panics if the environment variable
-// is not set or empty.
-func InjectCredentialsFromEnvironment() func(ctx context.Context, req *http.Request) context.Context {
-	token := requireEnv("LUNCHMONEY_TOKEN")
-	return lmapi.WithLunchMoneyCredentials(token)
+// InjectCredentials returns an HTTP context injector function that injects a LunchMoney
+// client configured with the given API token and client options into the context of incoming HTTP requests.
+// The current value of the token is used for every request, so rotated tokens take effect immediately.
+func InjectCredentials(token *secrets.Secret, opts ...lmapi.Option) func(ctx context.Context, req *http.Request) context.Context {
+	return func(ctx context.Context, req *http.Request) context.Context {
+		return lmapi.WithLunchMoneyCredentials(token.Value(), opts...)(ctx, req)
+	}
 }
 
-// requireEnv retrieves the value of the named environment variable.
-// It panics if the variable is not set or is empty, logging the error message.
-func requireEnv(name string) string {
-	val := os.Getenv(name)
-	if val == "" {
-		msg := fmt.Sprintf("environment variable %q must be set", name)
-		log.Print(msg)
-		panic(msg)
+// clientFromContext returns the Lunch Money client injected by InjectCredentials, or an ErrNotConfigured error if
+// there is none.
+func clientFromContext(ctx context.Context) (lmapi.Client, error) {
+	client, ok := lmapi.FromContext(ctx)
+	if !ok {
+		return nil, ds.Errorf(ds.ErrNotConfigured, "no Lunch Money client in the request context")
 	}
-	return val
+	return client, nil
 }
@@ -0,0 +1,39 @@
+package lunchmoney
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+
+	lmapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/lunch_money"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// ListTags is a DataSource function that fetches all tags from LunchMoney API and converts them into
+// domain Tags sorted by name. Archived tags are skipped, consistent with how transactions are annotated.
+var ListTags ds.ListTagsFunc = func(ctx context.Context) ([]*types.Tag, error) {
+	client, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lmTags, err := client.ListTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := slices.SortedFunc(maps.Values(lmTags), func(a, b *lmapi.Tag) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	result := make([]*types.Tag, 0, len(sorted))
+	for _, lmTag := range sorted {
+		if lmTag.IsArchived {
+			continue
+		}
+		result = append(result, types.NewTag(lmTag.Name, lmTag.Description))
+	}
+	return result, nil
+}
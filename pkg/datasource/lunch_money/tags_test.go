@@ -0,0 +1,29 @@
+package lunchmoney
+
+import (
+	"testing"
+
+	lmapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/lunch_money"
+)
+
+func TestListTags_SortedSkipsArchived(t *testing.T) {
+	client := &fakeClient{tags: lmapi.Tags{
+		1: {Id: 1, Name: "travel", Description: "trips"},
+		2: {Id: 2, Name: "business", Description: "reimbursable"},
+		3: {Id: 3, Name: "legacy", IsArchived: true},
+	}}
+
+	tags, err := ListTags(contextWithClient(client))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags) != 2 {
+		t.Fatalf("len(tags) = %d; want 2", len(tags))
+	}
+	if tags[0].Name != "business" || tags[0].Description != "reimbursable" {
+		t.Errorf("tags[0] = %+v; want business", tags[0])
+	}
+	if tags[1].Name != "travel" {
+		t.Errorf("tags[1] = %+v; want travel", tags[1])
+	}
+}
@@ -3,11 +3,13 @@ package lunchmoney
 import (
 	"context"
 	"fmt"
-	"log"
 
 	lmapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/lunch_money"
 	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
 	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // GetCategorizedTransactions is a DataSource function that fetches transactions from LunchMoney API,
@@ -15,24 +17,48 @@ import (
 // types enriched with annotations from tags and error metadata. It handles missing categories and groups,
 // placing uncategorized transactions accordingly and adds error annotations when inconsistencies occur.
 var GetCategorizedTransactions ds.GetCategorizedTransactionsFunc = func(ctx context.Context, interval ds.DateRange) (*types.Categories, error) {
-	client := lmapi.FromContext(ctx)
-
-	// Grab raw data from LunchMoney
-	lmCats, err := client.ListCategories(ctx)
+	client, err := clientFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	lmTags, err := client.ListTags(ctx)
+
+	// Grab raw data from LunchMoney
+	lmCats, lmTags, lmTxs, err := fetchTransactions(ctx, client, interval)
 	if err != nil {
 		return nil, err
 	}
-	lmTxs, err := client.ListTransactions(ctx, interval.StartDate.String(), interval.EndDate.String())
-	if err != nil {
-		return nil, err
+	return categorizeTransactions(ctx, lmCats, lmTags, lmTxs)
+}
+
+// fetchTransactions retrieves categories, tags and the transactions within interval from LunchMoney.
+func fetchTransactions(ctx context.Context, client lmapi.Client, interval ds.DateRange) (
+	lmCats lmapi.Categories, lmTags lmapi.Tags, lmTxs lmapi.Transactions, err error,
+) {
+	ctx, span := tracing.Start(ctx, "lunch_money.fetch")
+	defer func() { tracing.End(span, err) }()
+
+	if lmCats, err = client.ListCategories(ctx); err != nil {
+		return nil, nil, nil, err
+	}
+	if lmTags, err = client.ListTags(ctx); err != nil {
+		return nil, nil, nil, err
 	}
+	if lmTxs, err = client.ListTransactions(ctx, interval.StartDate.String(), interval.EndDate.String()); err != nil {
+		return nil, nil, nil, err
+	}
+	return lmCats, lmTags, lmTxs, nil
+}
+
+// categorizeTransactions converts LunchMoney transactions into domain types and sorts them into category trees.
+func categorizeTransactions(ctx context.Context, lmCats lmapi.Categories, lmTags lmapi.Tags, lmTxs lmapi.Transactions) (
+	result *types.Categories, err error,
+) {
+	ctx, span := tracing.Start(ctx, "lunch_money.categorize", attribute.Int("transactions", len(lmTxs)))
+	defer func() { tracing.End(span, err) }()
 
 	// Maps to keep track of categories as we add stuff to them
-	result := types.NewCategories()
+	result = types.NewCategories()
+	logger := logging.FromContext(ctx)
 
 	// Start processing transactions
 	for _, lmtx := range lmTxs {
@@ -45,10 +71,10 @@ var GetCategorizedTransactions ds.GetCategorizedTransactionsFunc = func(ctx cont
 		for _, tag := range lmtx.Tags {
 			lmtag, ok := lmTags[tag.Id]
 			if !ok {
-				log.Printf("missing tag from LunchMoney response: %d", tag.Id)
+				logger.WarnContext(ctx, "Missing tag from LunchMoney response", "tag_id", tag.Id)
 				continue
 			}
-			addTransactionTag(tx, lmtag)
+			addTransactionTag(ctx, tx, lmtag)
 		}
 
 		// Determine which "bucket" does the transaction fall under
@@ -64,7 +90,7 @@ var GetCategorizedTransactions ds.GetCategorizedTransactionsFunc = func(ctx cont
 
 		// Utility function to set transaction as uncategorized
 		setAsUncategorized := func() error {
-			unc := getOrCreateCategoryByName(bucket, "Uncategorized")
+			unc := getOrCreateCategoryByName(bucket, types.UncategorizedName)
 			return unc.AddTransaction(tx)
 		}
 
@@ -79,7 +105,7 @@ var GetCategorizedTransactions ds.GetCategorizedTransactionsFunc = func(ctx cont
 		if lmtx.CategoryGroupId != 0 {
 			lmCatGroup, ok := lmCats[lmtx.CategoryGroupId]
 			if !ok {
-				log.Printf("missing category group from LunchMoney response: %d\n", lmtx.CategoryGroupId)
+				logger.WarnContext(ctx, "Missing category group from LunchMoney response", "category_group_id", lmtx.CategoryGroupId)
 				tx.Annotate("category_error", "uncategorized due to invalid category group id")
 				if err := setAsUncategorized(); err != nil {
 					return nil, err
@@ -94,7 +120,7 @@ var GetCategorizedTransactions ds.GetCategorizedTransactionsFunc = func(ctx cont
 		// Add transaction to bucket
 		lmCat, ok := lmCats[lmtx.CategoryId]
 		if !ok {
-			log.Printf("missing category from LunchMoney response: %d\n", lmtx.CategoryId)
+			logger.WarnContext(ctx, "Missing category from LunchMoney response", "category_id", lmtx.CategoryId)
 			tx.Annotate("category_error", "uncategorized due to invalid category id")
 			if err := setAsUncategorized(); err != nil {
 				return nil, err
@@ -123,9 +149,9 @@ func buildTransaction(lmtx *lmapi.Transaction) (*types.Transaction, error) {
 
 // addTransactionTag annotates a Transaction with the given LunchMoney Tag providing
 // key-value metadata. Skips archived tags.
-func addTransactionTag(tx *types.Transaction, tag *lmapi.Tag) {
+func addTransactionTag(ctx context.Context, tx *types.Transaction, tag *lmapi.Tag) {
 	if tag.IsArchived {
-		log.Printf("skipping archived tag: %d - %s\n", tag.Id, tag.Name)
+		logging.FromContext(ctx).DebugContext(ctx, "Skipping archived tag", "tag_id", tag.Id, "tag", tag.Name)
 		return
 	}
 	key := fmt.Sprintf("tag:%d", tag.Id)
@@ -2,6 +2,7 @@ package lunchmoney
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
 
@@ -69,7 +70,7 @@ func TestBuildTransaction_InvalidDate(t *testing.T) {
 func TestAddTransactionTag_SkipsArchived(t *testing.T) {
 	tx := types.NewTransaction(types.Date{}, "p", 0)
 	archived := &lmapi.Tag{Id: 1, Name: "old", Description: "desc", IsArchived: true}
-	addTransactionTag(tx, archived)
+	addTransactionTag(context.Background(), tx, archived)
 	if len(tx.Annotations) != 0 {
 		t.Errorf("expected no annotations for archived tag, got %v", tx.Annotations)
 	}
@@ -78,7 +79,7 @@ func TestAddTransactionTag_SkipsArchived(t *testing.T) {
 func TestAddTransactionTag_AnnotationKeyValue(t *testing.T) {
 	tx := types.NewTransaction(types.Date{}, "p", 0)
 	tag := &lmapi.Tag{Id: 2, Name: "food", Description: "lunch", IsArchived: false}
-	addTransactionTag(tx, tag)
+	addTransactionTag(context.Background(), tx, tag)
 	wantKey := "tag:2"
 	wantVal := "food: lunch"
 	if got, ok := tx.Annotations[wantKey]; !ok || got != wantVal {
@@ -265,3 +266,19 @@ func TestGetCategorizedTransactions_MissingTagSkipped(t *testing.T) {
 		t.Errorf("expected no annotations for missing tag, got %v", res.Expenses.Subcategories[0].Transactions[0].Annotations)
 	}
 }
+
+// TestNoClient verifies that the data source functions report a data source that is not configured without a
+// client in context.
+func TestNoClient(t *testing.T) {
+	ctx := context.Background()
+	for name, call := range map[string]func() error{
+		"GetCategorizedTransactions": func() error { _, err := GetCategorizedTransactions(ctx, ds.DateRange{}); return err },
+		"ListCategories":             func() error { _, err := ListCategories(ctx); return err },
+		"ListTags":                   func() error { _, err := ListTags(ctx); return err },
+		"CheckCredentials":           func() error { return CheckCredentials(ctx) },
+	} {
+		if err := call(); !errors.Is(err, ds.ErrNotConfigured) {
+			t.Errorf("%s error = %v; want not configured", name, err)
+		}
+	}
+}
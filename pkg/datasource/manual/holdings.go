@@ -0,0 +1,87 @@
+package manual
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// holding is an asset or debt in the holdings file. Values and dates are kept as written and parsed by
+// validate, so that errors can name the holding.
+type holding struct {
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"`
+	Class       string `yaml:"class"`
+	Liquidity   string `yaml:"liquidity"`
+	Ticker      string `yaml:"ticker"`
+	Description string `yaml:"description"`
+	Value       string `yaml:"value"`
+	Currency    string `yaml:"currency"`
+	AsOf        string `yaml:"as_of"`
+
+	value types.Money
+	asOf  types.Date
+}
+
+// holdings is the content of a holdings file.
+type holdings struct {
+	// Rates are the values of a unit of other currencies in the base currency, such as EUR: 1.08.
+	Rates  map[string]float64 `yaml:"rates"`
+	Assets []*holding         `yaml:"assets"`
+	Debts  []*holding         `yaml:"debts"`
+}
+
+// readHoldings reads and validates the holdings file at path.
+func readHoldings(path string) (*holdings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var h holdings
+	if err := yaml.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("invalid holdings file: %w", err)
+	}
+
+	var errs []error
+	for i, a := range h.Assets {
+		if err := a.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("assets[%d]: %w", i, err))
+		}
+	}
+	for i, d := range h.Debts {
+		if err := d.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("debts[%d]: %w", i, err))
+		}
+	}
+	for code, rate := range h.Rates {
+		if rate <= 0 {
+			errs = append(errs, fmt.Errorf("rates.%s: must be positive", code))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// validate checks the holding for errors and parses its value and date.
+func (h *holding) validate() error {
+	if h.Name == "" {
+		return fmt.Errorf("name must be set")
+	}
+	value, err := strconv.ParseFloat(strings.ReplaceAll(h.Value, ",", ""), 64)
+	if err != nil {
+		return fmt.Errorf("%s: invalid value %q", h.Name, h.Value)
+	}
+	h.value = types.Money(math.Round(value * types.FACTOR))
+	if h.asOf, err = types.ParseDate(h.AsOf); err != nil {
+		return fmt.Errorf("%s: invalid as_of date %q, expected YYYY-MM-DD", h.Name, h.AsOf)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Package manual implements a data source that reads assets and debts kept by hand in a YAML file, for holdings
+// that no other data source tracks, such as a stake in a private company, a car or a college savings plan.
+package manual
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"time"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Source reads a holdings file. The file is re-read on every call, so changes are picked up without a restart.
+type Source struct {
+	path       string
+	base       string
+	staleAfter time.Duration
+	now        func() time.Time
+}
+
+// NewSource creates a Source that reads the holdings file at path. Holdings whose value is older than
+// staleAfter are annotated as stale; zero disables the annotation.
+func NewSource(path, baseCurrency string, staleAfter time.Duration) *Source {
+	return &Source{path: path, base: baseCurrency, staleAfter: staleAfter, now: time.Now}
+}
+
+// Check implements ds.CheckFunc by verifying that the holdings file can be read and is valid.
+func (s *Source) Check(ctx context.Context) error {
+	_, err := s.read(ctx)
+	return err
+}
+
+// read reads the holdings file.
+func (s *Source) read(ctx context.Context) (h *holdings, err error) {
+	ctx, span := tracing.Start(ctx, "manual.read")
+	defer func() { tracing.End(span, err) }()
+
+	h, err = readHoldings(s.path)
+	if err != nil {
+		hint := "the holdings file on the server is invalid; ask the user to fix it"
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			hint = "the holdings file configured on the server cannot be read; ask the user to fix it"
+		}
+		return nil, ds.Errorf(ds.ErrUpstreamUnavailable, "failed to read holdings: %w", err).WithHint(hint)
+	}
+	span.SetAttributes(attribute.Int("assets", len(h.Assets)), attribute.Int("debts", len(h.Debts)))
+	return h, nil
+}
+
+// GetPortfolio implements ds.GetPortfolioFunc. Every holding in the file becomes a position, valued in the base
+// currency with the rates of the file. Holdings are annotated like Kubera positions, with their liquidity and
+// asset class, so that the two can be merged, and with the date of their value.
+func (s *Source) GetPortfolio(ctx context.Context) (portfolio *types.Portfolio, err error) {
+	h, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio = types.NewPortfolio()
+	for _, a := range h.Assets {
+		asset := types.NewAssetPosition(a.Name, a.Ticker, a.Type, a.Class, 0)
+		s.value(&asset.Position, h, a)
+		if a.Class != "" {
+			asset.Annotate("asset_class", a.Class)
+		}
+		portfolio.AddAsset(asset)
+	}
+	for _, d := range h.Debts {
+		debt := types.NewDebtPosition(d.Name, d.Type, 0)
+		s.value(&debt.Position, h, d)
+		portfolio.AddDebt(debt)
+	}
+	portfolio.Sort()
+	logging.FromContext(ctx).DebugContext(ctx, "Read manual holdings",
+		"assets", len(portfolio.Assets), "debts", len(portfolio.Debts))
+	return portfolio, nil
+}
+
+// value fills in the value and annotations of the position of a holding. Values in currencies without a rate
+// are left at zero with a note, so that the holding is not silently lost.
+func (s *Source) value(p *types.Position, h *holdings, hd *holding) {
+	p.Description = hd.Description
+	p.Value = hd.value
+	p.Annotate("as_of", hd.asOf.String())
+	if hd.Liquidity != "" {
+		p.Annotate("liquidity", hd.Liquidity)
+	}
+	if hd.Currency != "" && hd.Currency != s.base {
+		p.Annotate("original_value", fmt.Sprintf("%.2f %s", float64(hd.value)/types.FACTOR, hd.Currency))
+		rate, ok := h.Rates[hd.Currency]
+		if !ok {
+			p.Value = 0
+			p.Annotate("note", fmt.Sprintf("no rate of %s in %s is in the holdings file", hd.Currency, s.base))
+		} else {
+			p.Value = types.Money(math.Round(float64(hd.value) * rate))
+		}
+	}
+
+	if s.staleAfter > 0 {
+		age := s.now().Sub(time.Time(hd.asOf))
+		if age > s.staleAfter {
+			days := int(age.Hours() / 24)
+			p.Annotate("stale", fmt.Sprintf("value as of %s is %d days old and may be out of date", hd.asOf, days))
+		}
+	}
+}
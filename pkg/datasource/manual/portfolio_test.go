@@ -0,0 +1,100 @@
+package manual
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func newSource(path string) *Source {
+	src := NewSource(path, "USD", 90*24*time.Hour)
+	src.now = func() time.Time { return time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC) }
+	return src
+}
+
+func TestGetPortfolio(t *testing.T) {
+	portfolio, err := newSource("testdata/holdings.yaml").GetPortfolio(context.Background())
+	if err != nil {
+		t.Fatalf("GetPortfolio error: %v", err)
+	}
+
+	assets := make(map[string]*types.AssetPosition)
+	for _, asset := range portfolio.Assets {
+		assets[asset.Name] = asset
+	}
+	if len(assets) != 5 || len(portfolio.Debts) != 1 {
+		t.Fatalf("portfolio = %+v; want 5 assets and a debt", portfolio)
+	}
+
+	stake := assets["Acme Corp stake"]
+	if stake.Value != 2500000000 || stake.Type != "private equity" || stake.Description != "2% of common stock" ||
+		stake.Annotations["asset_class"] != "stock" || stake.Annotations["liquidity"] != "illiquid" ||
+		stake.Annotations["as_of"] != "2023-06-30" {
+		t.Errorf("stake = %+v", stake)
+	}
+	if !strings.Contains(stake.Annotations["stale"], "337 days old") {
+		t.Errorf("stake annotations = %v; want a staleness annotation", stake.Annotations)
+	}
+	if car := assets["Car"]; car.Value != 185000000 || car.Annotations["stale"] != "" || car.Annotations["original_value"] != "" {
+		t.Errorf("car = %+v; want a recent value in the base currency", car)
+	}
+	if plan := assets["529 plan"]; plan.Value != 420005000 || plan.Annotations["liquidity"] != "restricted" {
+		t.Errorf("529 plan = %+v", plan)
+	}
+	if deposit := assets["Paris flat deposit"]; deposit.Value != 108000000 || deposit.Annotations["original_value"] != "10000.00 EUR" {
+		t.Errorf("deposit = %+v; want 10000 EUR at 1.08", deposit)
+	}
+	if yen := assets["Tokyo account"]; yen.Value != 0 || yen.Annotations["note"] == "" {
+		t.Errorf("yen account = %+v; want zero value with a note without a rate", yen)
+	}
+
+	if loan := portfolio.Debts[0]; loan.Name != "Car loan" || loan.Value != 120000000 || loan.Type != "loan" {
+		t.Errorf("debt = %+v", loan)
+	}
+	if portfolio.NetWorth != 2500000000+185000000+420005000+108000000-120000000 {
+		t.Errorf("NetWorth = %d; want assets minus debts", portfolio.NetWorth)
+	}
+}
+
+func TestGetPortfolio_NotStale(t *testing.T) {
+	src := newSource("testdata/holdings.yaml")
+	src.staleAfter = 0
+	portfolio, err := src.GetPortfolio(context.Background())
+	if err != nil {
+		t.Fatalf("GetPortfolio error: %v", err)
+	}
+	for _, asset := range portfolio.Assets {
+		if asset.Annotations["stale"] != "" {
+			t.Errorf("asset = %+v; want no staleness annotation when disabled", asset)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := newSource("testdata/holdings.yaml").Check(context.Background()); err != nil {
+		t.Errorf("Check error: %v", err)
+	}
+	if err := newSource("testdata/missing.yaml").Check(context.Background()); !errors.Is(err, ds.ErrUpstreamUnavailable) {
+		t.Errorf("err = %v; want upstream unavailable for a missing file", err)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "holdings.yaml")
+	content := "rates:\n  EUR: 0\nassets:\n  - value: 1\n    as_of: 2024-01-01\n  - name: Car\n    value: lots\n    as_of: 2024-01-01\n" +
+		"debts:\n  - name: Loan\n    value: 1\n    as_of: 01/01/2024\n"
+	if err := os.WriteFile(invalid, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err := newSource(invalid).Check(context.Background())
+	for _, want := range []string{"assets[0]: name must be set", "assets[1]: Car: invalid value", "debts[0]: Loan: invalid as_of", "rates.EUR"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v; want %q", err, want)
+		}
+	}
+}
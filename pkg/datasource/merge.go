@@ -0,0 +1,277 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// MergePolicy decides what a merged data source does when some of the data sources it merges fail.
+type MergePolicy string
+
+const (
+	// MergeFail fails the whole call when any data source fails.
+	MergeFail MergePolicy = "fail"
+	// MergePartial returns the data of the data sources that succeeded, annotated with a warning that names
+	// those that failed. The call fails only when every data source fails.
+	MergePartial MergePolicy = "partial"
+)
+
+// ParseMergePolicy parses a merge policy name: fail or partial.
+func ParseMergePolicy(name string) (MergePolicy, error) {
+	switch policy := MergePolicy(strings.ToLower(name)); policy {
+	case MergeFail, MergePartial:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown merge policy %q, expected fail or partial", name)
+	}
+}
+
+// Named pairs a data source function with the name of its data source, such as ofx, which merged results are
+// annotated with.
+type Named[F any] struct {
+	Name string
+	Fn   F
+}
+
+// MergeTransactions combines several data sources of transactions into one. Their category trees are merged by
+// the names along the path of each category, and every transaction is annotated with the source it came from.
+// A transaction reported by several data sources, with the same date, amount and payee, is kept once, in the
+// category of the first data source listing it, and annotated with all of them.
+func MergeTransactions(policy MergePolicy, sources ...Named[GetCategorizedTransactionsFunc]) GetCategorizedTransactionsFunc {
+	return func(ctx context.Context, interval DateRange) (*types.Categories, error) {
+		results, warning, err := gather(ctx, policy, sources, func(fn GetCategorizedTransactionsFunc) (*types.Categories, error) {
+			return fn(ctx, interval)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		merged := types.NewCategories()
+		// reported holds the transactions of earlier data sources that later ones may report again.
+		reported := make(map[transactionKey][]*types.Transaction)
+		for _, r := range results {
+			added := make(map[transactionKey][]*types.Transaction)
+			for _, roots := range [][2]*types.Category{
+				{merged.Income, r.value.Income}, {merged.Expenses, r.value.Expenses}, {merged.Ignored, r.value.Ignored},
+			} {
+				if roots[1] == nil {
+					continue
+				}
+				if err := mergeCategory(roots[0], roots[1], r.name, reported, added); err != nil {
+					return nil, err
+				}
+			}
+			for key, txns := range added {
+				reported[key] = append(reported[key], txns...)
+			}
+		}
+		if warning != "" {
+			for _, root := range []*types.Category{merged.Income, merged.Expenses, merged.Ignored} {
+				root.Annotate("warning", warning)
+			}
+		}
+		return merged, nil
+	}
+}
+
+// transactionKey identifies a transaction across data sources.
+type transactionKey struct {
+	date   types.Date
+	amount types.Money
+	payee  string
+}
+
+// newTransactionKey returns the key of txn. Payees are compared regardless of case and spacing.
+func newTransactionKey(txn *types.Transaction) transactionKey {
+	return transactionKey{date: txn.Date, amount: txn.Amount, payee: normalize(txn.Payee)}
+}
+
+// mergeCategory moves the transactions of src, a category of the data source called name, and those of its
+// subcategories into dst, creating subcategories of dst as needed. Transactions that match one in reported are
+// dropped, and the match is annotated with name instead; the others are recorded in added.
+func mergeCategory(dst, src *types.Category, name string, reported, added map[transactionKey][]*types.Transaction) error {
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	for k, v := range src.Annotations {
+		if _, ok := dst.Annotations[k]; !ok {
+			dst.Annotate(k, v)
+		}
+	}
+	for _, txn := range src.Transactions {
+		key := newTransactionKey(txn)
+		if matches := reported[key]; len(matches) > 0 {
+			matches[0].Annotate("source", matches[0].Annotations["source"]+", "+name)
+			reported[key] = matches[1:]
+			continue
+		}
+		txn.Category = nil
+		txn.Annotate("source", name)
+		if err := dst.AddTransaction(txn); err != nil {
+			return err
+		}
+		added[key] = append(added[key], txn)
+	}
+	for _, sub := range src.Subcategories {
+		if err := mergeCategory(dst.GetOrCreatePath(sub.Name), sub, name, reported, added); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergePortfolios combines several data sources of assets and debts into one, holding the positions of all of
+// them annotated with the source they came from. A position reported by several data sources, with the same
+// name, ticker and value, is kept once and annotated with all of them.
+func MergePortfolios(policy MergePolicy, sources ...Named[GetPortfolioFunc]) GetPortfolioFunc {
+	return func(ctx context.Context) (*types.Portfolio, error) {
+		results, warning, err := gather(ctx, policy, sources, func(fn GetPortfolioFunc) (*types.Portfolio, error) {
+			return fn(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		merged := types.NewPortfolio()
+		// assets and debts hold the positions of earlier data sources that later ones may report again.
+		assets := make(map[positionKey][]*types.AssetPosition)
+		debts := make(map[positionKey][]*types.DebtPosition)
+		for _, r := range results {
+			for k, v := range r.value.Annotations {
+				if _, ok := merged.Annotations[k]; !ok {
+					merged.Annotate(k, v)
+				}
+			}
+			// Positions are matched only against those of other data sources, so identical positions of one data
+			// source are all kept.
+			addedAssets := make(map[positionKey][]*types.AssetPosition)
+			for _, asset := range r.value.Assets {
+				key := newPositionKey(&asset.Position, asset.Ticker)
+				if matches := assets[key]; len(matches) > 0 {
+					matches[0].Annotate("source", matches[0].Annotations["source"]+", "+r.name)
+					assets[key] = matches[1:]
+					continue
+				}
+				asset.Annotate("source", r.name)
+				merged.AddAsset(asset)
+				addedAssets[key] = append(addedAssets[key], asset)
+			}
+			addedDebts := make(map[positionKey][]*types.DebtPosition)
+			for _, debt := range r.value.Debts {
+				key := newPositionKey(&debt.Position, "")
+				if matches := debts[key]; len(matches) > 0 {
+					matches[0].Annotate("source", matches[0].Annotations["source"]+", "+r.name)
+					debts[key] = matches[1:]
+					continue
+				}
+				debt.Annotate("source", r.name)
+				merged.AddDebt(debt)
+				addedDebts[key] = append(addedDebts[key], debt)
+			}
+			for key, added := range addedAssets {
+				assets[key] = append(assets[key], added...)
+			}
+			for key, added := range addedDebts {
+				debts[key] = append(debts[key], added...)
+			}
+		}
+		merged.Sort()
+		if warning != "" {
+			merged.Annotate("warning", warning)
+		}
+		return merged, nil
+	}
+}
+
+// MergeSelectedPortfolios is MergePortfolios for a data source that keeps several portfolios, such as Kubera. The
+// returned function merges the portfolios selected from the data source named like selector with those of the
+// other data sources, in place of the default portfolio of the data source.
+func MergeSelectedPortfolios(policy MergePolicy, selector Named[SelectPortfolioFunc], sources ...Named[GetPortfolioFunc]) SelectPortfolioFunc {
+	return func(ctx context.Context, selection string) (*types.Portfolio, error) {
+		selected := slices.Clone(sources)
+		for i, src := range selected {
+			if src.Name == selector.Name {
+				selected[i].Fn = func(ctx context.Context) (*types.Portfolio, error) {
+					return selector.Fn(ctx, selection)
+				}
+			}
+		}
+		return MergePortfolios(policy, selected...)(ctx)
+	}
+}
+
+// positionKey identifies a position across data sources.
+type positionKey struct {
+	name   string
+	ticker string
+	value  types.Money
+}
+
+// newPositionKey returns the key of a position. Names and tickers are compared regardless of case and spacing.
+func newPositionKey(p *types.Position, ticker string) positionKey {
+	return positionKey{name: normalize(p.Name), ticker: normalize(ticker), value: p.Value}
+}
+
+// normalize lowercases s and collapses runs of whitespace, so that names differing only in either match.
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// gathered is the result of a data source that succeeded.
+type gathered[T any] struct {
+	name  string
+	value T
+}
+
+// gather calls every data source concurrently and returns the results of those that succeeded, in the order of
+// the data sources. Under MergePartial, failures are described by the returned warning; the call fails only if
+// every data source failed. Under MergeFail, the first failure fails the call.
+func gather[T, F any](ctx context.Context, policy MergePolicy, sources []Named[F], call func(F) (T, error)) ([]gathered[T], string, error) {
+	values := make([]T, len(sources))
+	errs := make([]error, len(sources))
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = call(src.Fn)
+		}()
+	}
+	wg.Wait()
+
+	var (
+		results  []gathered[T]
+		failures []string
+		first    error
+	)
+	for i, src := range sources {
+		if errs[i] == nil {
+			results = append(results, gathered[T]{name: src.Name, value: values[i]})
+			continue
+		}
+		err := fmt.Errorf("%s: %w", src.Name, errs[i])
+		if policy != MergePartial {
+			return nil, "", err
+		}
+		if first == nil {
+			first = err
+		}
+		logging.FromContext(ctx).WarnContext(ctx, "Data source failed, returning partial results",
+			"source", src.Name, "error", errs[i])
+		failures = append(failures, err.Error())
+	}
+	if len(results) == 0 {
+		return nil, "", first
+	}
+	var warning string
+	if len(failures) > 0 {
+		warning = "results are incomplete because some data sources failed: " + strings.Join(failures, "; ")
+	}
+	return results, warning, nil
+}
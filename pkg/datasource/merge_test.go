@@ -0,0 +1,253 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func TestMergeTransactions(t *testing.T) {
+	jan := func(t *testing.T) types.Date { return mustDate(t, "2024-01-05") }
+	bank := func(ctx context.Context, _ DateRange) (*types.Categories, error) {
+		cats := types.NewCategories()
+		food := cats.Expenses.GetOrCreatePath("Food", "Groceries")
+		_ = food.AddTransaction(types.NewTransaction(jan(t), "Whole Foods", 500000))
+		_ = food.AddTransaction(types.NewTransaction(jan(t), "Whole Foods", 500000))
+		_ = cats.Income.GetOrCreatePath("Salary").AddTransaction(types.NewTransaction(jan(t), "Acme", -30000000))
+		return cats, nil
+	}
+	card := func(ctx context.Context, _ DateRange) (*types.Categories, error) {
+		cats := types.NewCategories()
+		food := cats.Expenses.GetOrCreatePath("Food", "Groceries")
+		food.Description = "Supermarkets"
+		_ = food.AddTransaction(types.NewTransaction(jan(t), "WHOLE  FOODS", 500000))
+		_ = cats.Expenses.GetOrCreatePath("Food", "Restaurants").AddTransaction(types.NewTransaction(jan(t), "Cafe", 120000))
+		return cats, nil
+	}
+
+	fn := MergeTransactions(MergeFail, Named[GetCategorizedTransactionsFunc]{"bank", bank}, Named[GetCategorizedTransactionsFunc]{"card", card})
+	cats, err := fn(context.Background(), DateRange{})
+	if err != nil {
+		t.Fatalf("MergeTransactions error: %v", err)
+	}
+
+	food := cats.Expenses.FindSubcategory("Food")
+	groceries, restaurants := food.FindSubcategory("Groceries"), food.FindSubcategory("Restaurants")
+	if len(food.Subcategories) != 2 || groceries == nil || restaurants == nil {
+		t.Fatalf("Food = %+v; want categories of both sources merged by path", food)
+	}
+	if groceries.Description != "Supermarkets" {
+		t.Errorf("Groceries description = %q; want description of the second source", groceries.Description)
+	}
+	// The card reports one of the two identical bank transactions again.
+	if len(groceries.Transactions) != 2 || groceries.TotalAmount != 1000000 {
+		t.Errorf("Groceries = %+v; want the duplicate across sources dropped", groceries.Transactions)
+	}
+	if got := groceries.Transactions[0].Annotations["source"]; got != "bank, card" {
+		t.Errorf("source = %q; want both sources of the duplicate", got)
+	}
+	if got := groceries.Transactions[1].Annotations["source"]; got != "bank" {
+		t.Errorf("source = %q; want bank", got)
+	}
+	if got := restaurants.Transactions[0].Annotations["source"]; got != "card" {
+		t.Errorf("source = %q; want card", got)
+	}
+	if cats.Expenses.TotalAmount != 1120000 || cats.Income.TotalAmount != -30000000 || cats.TransactionCount() != 4 {
+		t.Errorf("totals = %v, %v across %d transactions", cats.Expenses.TotalAmount, cats.Income.TotalAmount, cats.TransactionCount())
+	}
+	if _, ok := cats.Expenses.Annotations["warning"]; ok {
+		t.Errorf("Expenses annotations = %v; want no warning", cats.Expenses.Annotations)
+	}
+}
+
+func TestMergeTransactions_PartialFailure(t *testing.T) {
+	ok := func(ctx context.Context, _ DateRange) (*types.Categories, error) {
+		cats := types.NewCategories()
+		_ = cats.Expenses.AddTransaction(types.NewTransaction(types.Date{}, "Payee", 100))
+		return cats, nil
+	}
+	failing := func(ctx context.Context, _ DateRange) (*types.Categories, error) {
+		return nil, Errorf(ErrUpstreamUnavailable, "connection refused")
+	}
+	sources := []Named[GetCategorizedTransactionsFunc]{{"ofx", ok}, {"ynab", failing}}
+
+	_, err := MergeTransactions(MergeFail, sources...)(context.Background(), DateRange{})
+	if !errors.Is(err, ErrUpstreamUnavailable) || !strings.HasPrefix(err.Error(), "ynab: ") {
+		t.Errorf("err = %v; want the failure of ynab", err)
+	}
+
+	cats, err := MergeTransactions(MergePartial, sources...)(context.Background(), DateRange{})
+	if err != nil {
+		t.Fatalf("MergeTransactions error: %v", err)
+	}
+	if cats.TransactionCount() != 1 || !strings.Contains(cats.Expenses.Annotations["warning"], "ynab: ") {
+		t.Errorf("result = %+v; want partial result with a warning", cats.Expenses)
+	}
+
+	_, err = MergeTransactions(MergePartial, Named[GetCategorizedTransactionsFunc]{"a", failing},
+		Named[GetCategorizedTransactionsFunc]{"b", failing})(context.Background(), DateRange{})
+	if !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("err = %v; want failure when every source fails", err)
+	}
+}
+
+func TestMergePortfolios(t *testing.T) {
+	kubera := func(ctx context.Context) (*types.Portfolio, error) {
+		p := types.NewPortfolio()
+		p.AddAsset(types.NewAssetPosition("Checking", "", "cash", "cash", 50000000))
+		p.AddAsset(types.NewAssetPosition("Brokerage", "VTI", "stock", "stock", 300000000))
+		p.AddDebt(types.NewDebtPosition("Mortgage", "loan", 2000000000))
+		return p, nil
+	}
+	bank := func(ctx context.Context) (*types.Portfolio, error) {
+		p := types.NewPortfolio()
+		p.AddAsset(types.NewAssetPosition("checking", "", "cash", "", 50000000))
+		p.AddAsset(types.NewAssetPosition("Savings", "", "cash", "", 100000000))
+		return p, nil
+	}
+	failing := func(ctx context.Context) (*types.Portfolio, error) {
+		return nil, ErrUnauthorized
+	}
+
+	fn := MergePortfolios(MergePartial, Named[GetPortfolioFunc]{"kubera", kubera}, Named[GetPortfolioFunc]{"simplefin", bank},
+		Named[GetPortfolioFunc]{"manual", failing})
+	p, err := fn(context.Background())
+	if err != nil {
+		t.Fatalf("MergePortfolios error: %v", err)
+	}
+	if len(p.Assets) != 3 || len(p.Debts) != 1 || p.NetWorth != 50000000+300000000+100000000-2000000000 {
+		t.Fatalf("portfolio = %+v; want the union of positions", p)
+	}
+	sources := make(map[string]string)
+	for _, asset := range p.Assets {
+		sources[asset.Name] = asset.Annotations["source"]
+	}
+	if sources["Checking"] != "kubera, simplefin" || sources["Brokerage"] != "kubera" || sources["Savings"] != "simplefin" {
+		t.Errorf("sources = %v", sources)
+	}
+	if p.Assets[0].Name != "Brokerage" {
+		t.Errorf("first asset = %s; want positions sorted by value", p.Assets[0].Name)
+	}
+	if !strings.Contains(p.Annotations["warning"], "manual: unauthorized") {
+		t.Errorf("annotations = %v; want a warning about manual", p.Annotations)
+	}
+}
+
+func TestMergePortfolios_IdenticalPositions(t *testing.T) {
+	positions := func(n int) GetPortfolioFunc {
+		return func(ctx context.Context) (*types.Portfolio, error) {
+			p := types.NewPortfolio()
+			for range n {
+				p.AddAsset(types.NewAssetPosition("Gift Card", "", "cash", "cash", 1000))
+				p.AddDebt(types.NewDebtPosition("Card", "credit", 500))
+			}
+			return p, nil
+		}
+	}
+	for _, tc := range []struct {
+		name    string
+		sources []Named[GetPortfolioFunc]
+		source  string
+	}{
+		{"single source", []Named[GetPortfolioFunc]{{"kubera", positions(2)}}, "kubera"},
+		{"reported again", []Named[GetPortfolioFunc]{{"kubera", positions(2)}, {"manual", positions(1)}}, "kubera, manual"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := MergePortfolios(MergeFail, tc.sources...)(context.Background())
+			if err != nil {
+				t.Fatalf("MergePortfolios error: %v", err)
+			}
+			// Both positions of kubera are kept, and manual reports one of them again.
+			if len(p.Assets) != 2 || len(p.Debts) != 2 || p.NetWorth != 1000 {
+				t.Fatalf("portfolio = %d assets, %d debts, net worth %v; want both positions of kubera",
+					len(p.Assets), len(p.Debts), p.NetWorth)
+			}
+			if got := p.Assets[0].Annotations["source"]; got != tc.source {
+				t.Errorf("source = %q; want %q", got, tc.source)
+			}
+			if got := p.Assets[1].Annotations["source"]; got != "kubera" {
+				t.Errorf("source = %q; want kubera", got)
+			}
+		})
+	}
+}
+
+func TestMergeSelectedPortfolios(t *testing.T) {
+	selector := Named[SelectPortfolioFunc]{"kubera", func(ctx context.Context, selection string) (*types.Portfolio, error) {
+		if selection == "missing" {
+			return nil, Errorf(ErrInvalidInput, "no portfolio %s", selection)
+		}
+		p := types.NewPortfolio()
+		p.AddAsset(types.NewAssetPosition(selection, "", "cash", "", 100))
+		return p, nil
+	}}
+	sources := []Named[GetPortfolioFunc]{
+		{"kubera", func(ctx context.Context) (*types.Portfolio, error) { return selector.Fn(ctx, "Default") }},
+		{"manual", func(ctx context.Context) (*types.Portfolio, error) {
+			p := types.NewPortfolio()
+			p.AddAsset(types.NewAssetPosition("Gold", "", "other", "", 50))
+			return p, nil
+		}},
+	}
+	fn := MergeSelectedPortfolios(MergePartial, selector, sources...)
+
+	p, err := fn(context.Background(), "Estate")
+	if err != nil {
+		t.Fatalf("MergeSelectedPortfolios error: %v", err)
+	}
+	if len(p.Assets) != 2 || p.Assets[0].Name != "Estate" || p.Assets[1].Name != "Gold" || p.NetWorth != 150 {
+		t.Errorf("assets = %+v; want the selected portfolio in place of the default one, with manual", p.Assets)
+	}
+	p, err = fn(context.Background(), "missing")
+	if err != nil || len(p.Assets) != 1 || !strings.Contains(p.Annotations["warning"], "kubera: ") {
+		t.Errorf("portfolio = %+v, %v; want the failed selection reported as a warning", p, err)
+	}
+}
+
+func TestMerge_SingleSource(t *testing.T) {
+	transactions := MergeTransactions(MergeFail, Named[GetCategorizedTransactionsFunc]{"ofx",
+		func(ctx context.Context, _ DateRange) (*types.Categories, error) {
+			cats := types.NewCategories()
+			_ = cats.Expenses.GetOrCreatePath("Food").AddTransaction(types.NewTransaction(mustDate(t, "2024-01-05"), "Cafe", 100))
+			return cats, nil
+		}})
+	cats, err := transactions(context.Background(), DateRange{})
+	if err != nil {
+		t.Fatalf("MergeTransactions error: %v", err)
+	}
+	if txns := cats.Expenses.FindSubcategory("Food").Transactions; len(txns) != 1 || txns[0].Annotations["source"] != "ofx" {
+		t.Errorf("transactions = %+v; want annotated with the single source", txns)
+	}
+
+	portfolio := MergePortfolios(MergeFail, Named[GetPortfolioFunc]{"kubera", func(ctx context.Context) (*types.Portfolio, error) {
+		p := types.NewPortfolio()
+		p.AddAsset(types.NewAssetPosition("Checking", "", "cash", "cash", 100))
+		return p, nil
+	}})
+	p, err := portfolio(context.Background())
+	if err != nil {
+		t.Fatalf("MergePortfolios error: %v", err)
+	}
+	if len(p.Assets) != 1 || p.Assets[0].Annotations["source"] != "kubera" {
+		t.Errorf("assets = %+v; want annotated with the single source", p.Assets)
+	}
+
+	_, err = MergePortfolios(MergePartial, Named[GetPortfolioFunc]{"manual", func(ctx context.Context) (*types.Portfolio, error) {
+		return nil, ErrUnauthorized
+	}})(context.Background())
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("err = %v; want the failure of the single source", err)
+	}
+}
+
+func TestParseMergePolicy(t *testing.T) {
+	if policy, err := ParseMergePolicy("Partial"); err != nil || policy != MergePartial {
+		t.Errorf("ParseMergePolicy = %v, %v; want partial", policy, err)
+	}
+	if _, err := ParseMergePolicy("ignore"); err == nil {
+		t.Error("ParseMergePolicy error = nil; want error for unknown policy")
+	}
+}
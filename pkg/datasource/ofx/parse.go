@@ -0,0 +1,181 @@
+package ofx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// statement is a bank or credit card statement read from an OFX file.
+type statement struct {
+	// account is the account number the statement belongs to.
+	account string
+	// currency is the ISO 4217 code of the default currency of the statement.
+	currency     string
+	transactions []*transaction
+}
+
+// transaction is a STMTTRN entry of a statement.
+type transaction struct {
+	// fitID is the identifier assigned by the financial institution, unique within the account.
+	fitID string
+	// kind is the transaction type, such as DEBIT, CREDIT or XFER.
+	kind   string
+	posted types.Date
+	// amount follows the OFX sign convention: negative amounts decrease the balance of the account.
+	amount types.Money
+	name   string
+	memo   string
+}
+
+// parse reads the statements from an OFX file. Both OFX 1.x, which is SGML where elements holding a value
+// need not be closed, and OFX 2.x, which is XML, are supported: the parser only relies on the opening tags of
+// values and on the closing tags of aggregates, which are mandatory in both versions.
+func parse(data []byte) ([]*statement, error) {
+	start := indexFold(data, "<OFX>")
+	if start < 0 {
+		return nil, errors.New("no OFX element found")
+	}
+
+	var (
+		result []*statement
+		stmt   *statement
+		txn    *transaction
+		// aggregates are the names of the enclosing aggregates of the current element.
+		aggregates []string
+	)
+	rest := data[start:]
+	for len(rest) > 0 {
+		open := bytes.IndexByte(rest, '<')
+		if open < 0 {
+			break
+		}
+		end := bytes.IndexByte(rest[open:], '>')
+		if end < 0 {
+			return nil, errors.New("unterminated tag")
+		}
+		tag := strings.ToUpper(strings.TrimSpace(string(rest[open+1 : open+end])))
+		rest = rest[open+end+1:]
+		next := bytes.IndexByte(rest, '<')
+		if next < 0 {
+			next = len(rest)
+		}
+		value := strings.TrimSpace(html.UnescapeString(string(rest[:next])))
+
+		switch {
+		case tag == "" || tag[0] == '?' || tag[0] == '!':
+			continue
+		case tag[0] == '/':
+			name := tag[1:]
+			// Pop up to and including the closed aggregate. Closing tags of values are not on the stack.
+			for i := len(aggregates) - 1; i >= 0; i-- {
+				if aggregates[i] == name {
+					aggregates = aggregates[:i]
+					break
+				}
+			}
+			switch name {
+			case "STMTTRN":
+				if stmt != nil && txn != nil {
+					stmt.transactions = append(stmt.transactions, txn)
+				}
+				txn = nil
+			case "STMTRS", "CCSTMTRS":
+				if stmt != nil {
+					result = append(result, stmt)
+				}
+				stmt = nil
+			}
+			continue
+		case value == "":
+			aggregates = append(aggregates, tag)
+			switch tag {
+			case "STMTRS", "CCSTMTRS":
+				stmt = &statement{}
+			case "STMTTRN":
+				txn = &transaction{}
+			}
+			continue
+		}
+
+		parent := ""
+		if len(aggregates) > 0 {
+			parent = aggregates[len(aggregates)-1]
+		}
+		switch {
+		case txn != nil:
+			if err := txn.set(tag, parent, value); err != nil {
+				return nil, err
+			}
+		case stmt != nil && tag == "CURDEF":
+			stmt.currency = value
+		case stmt != nil && tag == "ACCTID" && (parent == "BANKACCTFROM" || parent == "CCACCTFROM"):
+			stmt.account = value
+		}
+	}
+	return result, nil
+}
+
+// set assigns the value of an element within a STMTTRN aggregate.
+func (t *transaction) set(tag, parent, value string) (err error) {
+	switch tag {
+	case "FITID":
+		t.fitID = value
+	case "TRNTYPE":
+		t.kind = strings.ToUpper(value)
+	case "DTPOSTED":
+		if t.posted, err = parseDate(value); err != nil {
+			return fmt.Errorf("invalid DTPOSTED %q: %w", value, err)
+		}
+	case "TRNAMT":
+		if t.amount, err = parseAmount(value); err != nil {
+			return fmt.Errorf("invalid TRNAMT %q: %w", value, err)
+		}
+	case "NAME":
+		// The name may be given directly or within a PAYEE aggregate, but not within the account of a transfer.
+		if parent == "STMTTRN" || parent == "PAYEE" {
+			t.name = value
+		}
+	case "MEMO":
+		t.memo = value
+	}
+	return nil
+}
+
+// parseDate parses an OFX datetime, such as 20240131 or 20240131120000.000[-5:EST], into the date it falls on
+// in the timezone of the financial institution.
+func parseDate(s string) (types.Date, error) {
+	if len(s) < 8 {
+		return types.Date{}, errors.New("too short")
+	}
+	t, err := time.Parse("20060102", s[:8])
+	if err != nil {
+		return types.Date{}, err
+	}
+	return types.Date(t), nil
+}
+
+// parseAmount parses an OFX amount. Some institutions use a comma as the decimal separator.
+func parseAmount(s string) (types.Money, error) {
+	s = strings.TrimPrefix(s, "+")
+	if !strings.Contains(s, ".") {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return types.Money(math.Round(f * types.FACTOR)), nil
+}
+
+// indexFold returns the index of the first case-insensitive occurrence of the ASCII string s in data, or -1.
+func indexFold(data []byte, s string) int {
+	return bytes.Index(bytes.ToUpper(data), []byte(s))
+}
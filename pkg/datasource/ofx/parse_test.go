@@ -0,0 +1,80 @@
+package ofx
+
+import (
+	"os"
+	"testing"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParse_SGML(t *testing.T) {
+	statements, err := parse(readTestdata(t, "checking.ofx"))
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(statements) != 1 {
+		t.Fatalf("len(statements) = %d; want 1", len(statements))
+	}
+	stmt := statements[0]
+	if stmt.account != "000123456789" || stmt.currency != "USD" {
+		t.Errorf("account, currency = %q, %q; want 000123456789, USD", stmt.account, stmt.currency)
+	}
+	if len(stmt.transactions) != 3 {
+		t.Fatalf("len(transactions) = %d; want 3", len(stmt.transactions))
+	}
+
+	credit := stmt.transactions[0]
+	if credit.kind != "CREDIT" || credit.amount != 50000000 || credit.posted.String() != "2024-01-05" {
+		t.Errorf("credit = %+v; want CREDIT of 5000.00 on 2024-01-05", credit)
+	}
+	debit := stmt.transactions[1]
+	if debit.fitID != "2024011001" || debit.amount != -1255000 || debit.name != "JITA 4-4 MARKET" || debit.memo != "Ammo & fuel" {
+		t.Errorf("debit = %+v; want unescaped memo and negative amount", debit)
+	}
+	if xfer := stmt.transactions[2]; xfer.kind != "XFER" || xfer.name != "TRANSFER TO SAVINGS" {
+		t.Errorf("xfer = %+v; want name unaffected by the target account", xfer)
+	}
+}
+
+func TestParse_XML(t *testing.T) {
+	statements, err := parse(readTestdata(t, "cards/card.qfx"))
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(statements) != 1 || len(statements[0].transactions) != 2 {
+		t.Fatalf("statements = %+v; want one statement with two transactions", statements)
+	}
+	if statements[0].account != "4111111111111111" {
+		t.Errorf("account = %q; want credit card number", statements[0].account)
+	}
+	if txn := statements[0].transactions[0]; txn.name != "NETFLIX.COM" || txn.amount != -159900 {
+		t.Errorf("transaction = %+v; want payee from PAYEE aggregate", txn)
+	}
+}
+
+func TestParse_NotOFX(t *testing.T) {
+	if _, err := parse([]byte("Date,Payee,Amount\n")); err == nil {
+		t.Error("expected error for non-OFX content")
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	for in, want := range map[string]types.Money{"-12.34": -123400, "+7": 70000, "1,5": 15000, "0.00005": 1} {
+		got, err := parseAmount(in)
+		if err != nil || got != want {
+			t.Errorf("parseAmount(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	if _, err := parseAmount("twelve"); err == nil {
+		t.Error("expected error for invalid amount")
+	}
+}
@@ -0,0 +1,154 @@
+// Package ofx implements a data source that reads transactions from OFX and QFX statement files, such as
+// the ones downloaded from the websites of banks and credit card issuers.
+package ofx
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/rules"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Source reads statements from the OFX and QFX files in a directory and its subdirectories.
+// Files are re-read on every call, so statements added to the directory are picked up without a restart.
+type Source struct {
+	dir        string
+	categories *rules.Rules
+}
+
+// NewSource creates a Source that reads statements from dir and assigns transactions to categories by payee
+// according to categories. Transactions that match no rule are left uncategorized.
+func NewSource(dir string, categories *rules.Rules) *Source {
+	return &Source{dir: dir, categories: categories}
+}
+
+// GetCategorizedTransactions implements ds.GetCategorizedTransactionsFunc. Credits are income, transfers are
+// ignored and everything else is an expense. Transactions that appear in several overlapping statements of the
+// same account are included once.
+func (s *Source) GetCategorizedTransactions(ctx context.Context, interval ds.DateRange) (result *types.Categories, err error) {
+	statements, err := s.readStatements(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(ctx, "ofx.categorize", attribute.Int("statements", len(statements)))
+	defer func() { tracing.End(span, err) }()
+
+	result = types.NewCategories()
+	seen := make(map[string]bool)
+	for _, stmt := range statements {
+		for _, txn := range stmt.transactions {
+			if txn.posted.Before(interval.StartDate) || interval.EndDate.Before(txn.posted) {
+				continue
+			}
+			key := stmt.account + "\x00" + txn.fitID
+			if txn.fitID == "" {
+				key = fmt.Sprintf("%s\x00%s\x00%d\x00%s\x00%s", stmt.account, txn.posted, txn.amount, txn.name, txn.memo)
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			tx := buildTransaction(stmt, txn)
+			var bucket *types.Category
+			switch {
+			case txn.kind == "XFER":
+				bucket = result.Ignored
+			case txn.amount > 0:
+				bucket = result.Income
+			default:
+				bucket = result.Expenses
+			}
+			if err := s.categories.Categorize(bucket, tx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	logging.FromContext(ctx).DebugContext(ctx, "Categorized OFX transactions", "transactions", result.TransactionCount())
+	return result, nil
+}
+
+// Check implements ds.CheckFunc by verifying that the statement directory can be read.
+func (s *Source) Check(ctx context.Context) error {
+	_, err := os.ReadDir(s.dir)
+	if err != nil {
+		return ds.Errorf(ds.ErrUpstreamUnavailable, "failed to read OFX statement directory: %w", err).
+			WithHint("the statement directory configured on the server cannot be read; ask the user to fix it")
+	}
+	return nil
+}
+
+// readStatements parses every OFX and QFX file in the statement directory.
+func (s *Source) readStatements(ctx context.Context) (statements []*statement, err error) {
+	ctx, span := tracing.Start(ctx, "ofx.read")
+	defer func() { tracing.End(span, err) }()
+
+	files := 0
+	err = filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ofx", ".qfx":
+		default:
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		parsed, err := parse(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		files++
+		statements = append(statements, parsed...)
+		return nil
+	})
+	if err != nil {
+		return nil, ds.Errorf(ds.ErrUpstreamUnavailable, "failed to read OFX statements: %w", err).
+			WithHint("the statement files on the server cannot be read; ask the user to fix them")
+	}
+	span.SetAttributes(attribute.Int("files", files))
+	logging.FromContext(ctx).DebugContext(ctx, "Read OFX statements", "files", files, "statements", len(statements))
+	return statements, nil
+}
+
+// buildTransaction converts an OFX transaction into a domain Transaction. OFX amounts are negative for money
+// leaving the account, while domain amounts are positive for expenses, so the sign is flipped.
+func buildTransaction(stmt *statement, txn *transaction) *types.Transaction {
+	payee, description := txn.name, txn.memo
+	if payee == "" {
+		payee, description = txn.memo, ""
+	}
+	tx := types.NewTransaction(txn.posted, payee, -txn.amount)
+	tx.Description = description
+	if stmt.account != "" {
+		tx.Annotate("account", maskAccount(stmt.account))
+	}
+	if stmt.currency != "" {
+		tx.Annotate("currency", stmt.currency)
+	}
+	return tx
+}
+
+// maskAccount hides all but the last four characters of an account number.
+func maskAccount(account string) string {
+	if len(account) <= 4 {
+		return account
+	}
+	return strings.Repeat("*", len(account)-4) + account[len(account)-4:]
+}
@@ -0,0 +1,77 @@
+package ofx
+
+import (
+	"context"
+	"testing"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/rules"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func mustDate(t *testing.T, s string) types.Date {
+	t.Helper()
+	d, err := types.ParseDate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestGetCategorizedTransactions(t *testing.T) {
+	categories, err := rules.Compile([]rules.Rule{
+		{Payee: "netflix", Category: "Subscriptions/Streaming"},
+		{Payee: "bounty", Category: "Bounties"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := NewSource("testdata", categories)
+	interval := ds.DateRange{StartDate: mustDate(t, "2024-01-01"), EndDate: mustDate(t, "2024-02-29")}
+
+	result, err := src.GetCategorizedTransactions(context.Background(), interval)
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+
+	bounties := result.Income.FindSubcategory("Bounties")
+	if bounties == nil || bounties.TotalAmount != -50000000 {
+		t.Errorf("Income/Bounties = %+v; want credit of 5000.00 as negative amount", bounties)
+	}
+	if got := result.Ignored.TransactionCount(); got != 1 {
+		t.Errorf("Ignored transactions = %d; want the transfer", got)
+	}
+	streaming := result.Expenses.GetOrCreatePath("Subscriptions", "Streaming")
+	if len(streaming.Transactions) != 1 {
+		t.Fatalf("Subscriptions/Streaming = %+v; want a single Netflix transaction from overlapping statements", streaming)
+	}
+	if got := streaming.Transactions[0].Annotations["account"]; got != "************1111" {
+		t.Errorf("account annotation = %q; want masked card number", got)
+	}
+	unc := result.Expenses.FindSubcategory(types.UncategorizedName)
+	if unc == nil || len(unc.Transactions) != 3 {
+		t.Errorf("Uncategorized = %+v; want Jita, Amarr and Caldari transactions", unc)
+	}
+}
+
+func TestGetCategorizedTransactions_FiltersByDate(t *testing.T) {
+	src := NewSource("testdata", nil)
+	interval := ds.DateRange{StartDate: mustDate(t, "2024-02-01"), EndDate: mustDate(t, "2024-02-11")}
+
+	result, err := src.GetCategorizedTransactions(context.Background(), interval)
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+	if got := result.TransactionCount(); got != 1 {
+		t.Errorf("TransactionCount = %d; want only the transaction of 2024-02-10", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := NewSource("testdata", nil).Check(context.Background()); err != nil {
+		t.Errorf("Check error: %v", err)
+	}
+	if err := NewSource("testdata/missing", nil).Check(context.Background()); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
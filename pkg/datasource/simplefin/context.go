@@ -0,0 +1,29 @@
+package simplefin
+
+import (
+	"context"
+	"net/http"
+
+	sfapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/simplefin"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/secrets"
+)
+
+// InjectCredentials returns an HTTP context injector function that injects a SimpleFIN client configured with the
+// given access URL and client options into the context of incoming HTTP requests. The current value of the access
+// URL is used for every request, so rotated access URLs take effect immediately.
+func InjectCredentials(accessUrl *secrets.Secret, opts ...sfapi.Option) func(ctx context.Context, req *http.Request) context.Context {
+	return func(ctx context.Context, req *http.Request) context.Context {
+		return sfapi.WithSimpleFINCredentials(accessUrl.Value(), opts...)(ctx, req)
+	}
+}
+
+// clientFromContext returns the SimpleFIN client injected by InjectCredentials, or an ErrNotConfigured error if
+// there is none.
+func clientFromContext(ctx context.Context) (sfapi.Client, error) {
+	client, ok := sfapi.FromContext(ctx)
+	if !ok {
+		return nil, ds.Errorf(ds.ErrNotConfigured, "no SimpleFIN client in the request context")
+	}
+	return client, nil
+}
@@ -0,0 +1,82 @@
+package simplefin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sfapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/simplefin"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetPortfolio implements ds.GetPortfolioFunc. Every account becomes a position valued at its balance: accounts
+// with a negative balance, such as credit cards and loans, are debts and all others are assets. SimpleFIN does not
+// tell the kind of account, so assets are of unknown type. Balances in other currencies than the base currency are
+// included with a zero value and a note, so that they are not silently lost.
+func (s *Source) GetPortfolio(ctx context.Context) (*types.Portfolio, error) {
+	client, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	set, err := fetchBalances(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(ctx, "simplefin.transform", attribute.Int("accounts", len(set.Accounts)))
+	defer span.End()
+
+	portfolio := types.NewPortfolio()
+	for _, acct := range set.Accounts {
+		balance := acct.Balance
+		if acct.Currency != s.base {
+			logging.FromContext(ctx).WarnContext(ctx, "Account is not in the base currency",
+				"account", acct.Name, "currency", acct.Currency)
+			balance = 0
+		}
+		name := accountName(acct)
+		if balance < 0 {
+			debt := types.NewDebtPosition(name, "unknown", -balance)
+			s.annotateAccount(&debt.Position, acct)
+			portfolio.AddDebt(debt)
+			continue
+		}
+		asset := types.NewAssetPosition(name, "", "unknown", "", balance)
+		s.annotateAccount(&asset.Position, acct)
+		portfolio.AddAsset(asset)
+	}
+	portfolio.Sort()
+	logging.FromContext(ctx).DebugContext(ctx, "Fetched SimpleFIN portfolio",
+		"assets", len(portfolio.Assets), "debts", len(portfolio.Debts))
+
+	return portfolio, nil
+}
+
+// fetchBalances retrieves the accounts without transactions from the SimpleFIN server.
+func fetchBalances(ctx context.Context, client sfapi.Client) (set *sfapi.AccountSet, err error) {
+	ctx, span := tracing.Start(ctx, "simplefin.fetch")
+	defer func() { tracing.End(span, err) }()
+
+	if set, err = client.ListBalances(ctx); err != nil {
+		return nil, err
+	}
+	logServerErrors(ctx, set)
+	return set, nil
+}
+
+// annotateAccount annotates a position with the institution and balance date of its account.
+func (s *Source) annotateAccount(pos *types.Position, acct *sfapi.Account) {
+	if acct.Org.Name != "" {
+		pos.Annotate("institution", acct.Org.Name)
+	}
+	if acct.BalanceDate != 0 {
+		pos.Annotate("balance_date", time.Unix(acct.BalanceDate, 0).UTC().Format(time.DateOnly))
+	}
+	if acct.Currency != s.base {
+		balance := float64(acct.Balance) / types.FACTOR
+		pos.Annotate("note", fmt.Sprintf("balance of %.2f %s is not converted to the base currency", balance, acct.Currency))
+	}
+}
@@ -0,0 +1,145 @@
+// Package simplefin implements a data source that fetches the transactions and balances of bank accounts from a
+// SimpleFIN server, such as SimpleFIN Bridge.
+package simplefin
+
+import (
+	"context"
+	"time"
+
+	sfapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/simplefin"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/rules"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Source fetches data from a SimpleFIN server with the client in the request context.
+type Source struct {
+	base       string
+	categories *rules.Rules
+}
+
+// NewSource creates a Source that reports amounts in baseCurrency and assigns transactions to categories by payee
+// according to categories. Transactions that match no rule are left uncategorized.
+func NewSource(baseCurrency string, categories *rules.Rules) *Source {
+	return &Source{base: baseCurrency, categories: categories}
+}
+
+// CheckCredentials verifies that the SimpleFIN server is reachable and accepts the access URL by listing
+// balances, which is the cheapest call.
+var CheckCredentials ds.CheckFunc = func(ctx context.Context) error {
+	client, err := clientFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.ListBalances(ctx)
+	return err
+}
+
+// GetCategorizedTransactions implements ds.GetCategorizedTransactionsFunc. SimpleFIN does not categorize
+// transactions, so deposits are income, withdrawals are expenses, and both are assigned to categories by the
+// payee rules. Pending transactions are included and annotated.
+func (s *Source) GetCategorizedTransactions(ctx context.Context, interval ds.DateRange) (*types.Categories, error) {
+	client, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	set, err := fetchAccounts(ctx, client, interval)
+	if err != nil {
+		return nil, err
+	}
+	return s.categorizeTransactions(ctx, set.Accounts, interval)
+}
+
+// fetchAccounts retrieves the accounts with the transactions within interval from the SimpleFIN server. Problems
+// the server reports with individual institutions are logged, as the accounts of the others are still usable.
+func fetchAccounts(ctx context.Context, client sfapi.Client, interval ds.DateRange) (set *sfapi.AccountSet, err error) {
+	ctx, span := tracing.Start(ctx, "simplefin.fetch")
+	defer func() { tracing.End(span, err) }()
+
+	start := time.Time(interval.StartDate)
+	end := time.Time(interval.EndDate).AddDate(0, 0, 1)
+	if set, err = client.ListAccounts(ctx, start, end); err != nil {
+		return nil, err
+	}
+	logServerErrors(ctx, set)
+	return set, nil
+}
+
+// logServerErrors logs the errors in a SimpleFIN response.
+func logServerErrors(ctx context.Context, set *sfapi.AccountSet) {
+	for _, msg := range set.Errors {
+		logging.FromContext(ctx).WarnContext(ctx, "SimpleFIN server reported an error", "error", msg)
+	}
+}
+
+// categorizeTransactions converts SimpleFIN transactions into domain types and sorts them into category trees.
+func (s *Source) categorizeTransactions(ctx context.Context, accounts []*sfapi.Account, interval ds.DateRange) (
+	result *types.Categories, err error,
+) {
+	ctx, span := tracing.Start(ctx, "simplefin.categorize", attribute.Int("accounts", len(accounts)))
+	defer func() { tracing.End(span, err) }()
+
+	result = types.NewCategories()
+	logger := logging.FromContext(ctx)
+	for _, acct := range accounts {
+		if acct.Currency != s.base {
+			logger.WarnContext(ctx, "Account is not in the base currency", "account", acct.Name, "currency", acct.Currency)
+		}
+		for _, txn := range acct.Transactions {
+			tx := s.buildTransaction(acct, txn)
+			if tx.Date.Before(interval.StartDate) || interval.EndDate.Before(tx.Date) {
+				continue
+			}
+			bucket := result.Expenses
+			if txn.Amount > 0 {
+				bucket = result.Income
+			}
+			if err := s.categories.Categorize(bucket, tx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	logger.DebugContext(ctx, "Categorized SimpleFIN transactions", "transactions", result.TransactionCount())
+	return result, nil
+}
+
+// buildTransaction converts a SimpleFIN transaction into a domain Transaction. SimpleFIN amounts are negative for
+// money leaving the account, while domain amounts are positive for expenses, so the sign is flipped. The payee
+// falls back to the description, which is what the bank statement shows.
+func (s *Source) buildTransaction(acct *sfapi.Account, txn *sfapi.Transaction) *types.Transaction {
+	posted := txn.Posted
+	if posted == 0 {
+		posted = txn.TransactedAt
+	}
+	t := time.Unix(posted, 0).UTC()
+	date := types.Date(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+
+	payee, description := txn.Payee, txn.Description
+	if payee == "" {
+		payee, description = txn.Description, ""
+	}
+	if txn.Memo != "" {
+		description = txn.Memo
+	}
+	tx := types.NewTransaction(date, payee, -txn.Amount)
+	tx.Description = description
+	tx.Annotate("account", accountName(acct))
+	if acct.Currency != s.base {
+		tx.Annotate("currency", acct.Currency)
+	}
+	if txn.Pending {
+		tx.Annotate("pending", "true")
+	}
+	return tx
+}
+
+// accountName returns the name of an account qualified with its institution, such as "First Bank Checking".
+func accountName(acct *sfapi.Account) string {
+	if acct.Org.Name == "" {
+		return acct.Name
+	}
+	return acct.Org.Name + " " + acct.Name
+}
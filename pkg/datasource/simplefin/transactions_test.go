@@ -0,0 +1,144 @@
+package simplefin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	sfapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/simplefin"
+	"github.com/wyvernzora/personal-finance-mcp/internal/clients/simplefin/simplefintest"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/rules"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// Unix times of days in January 2024.
+const (
+	jan5  = 1704412800
+	jan6  = 1704499200
+	jan31 = 1706659200
+	feb1  = 1706745600
+)
+
+// newServer starts a stand-in SimpleFIN server with a checking account, a credit card and a euro account.
+func newServer(t *testing.T) *simplefintest.Server {
+	t.Helper()
+	srv := simplefintest.NewServer("user", "secret")
+	t.Cleanup(srv.Close)
+
+	srv.PutAccount(&sfapi.Account{
+		Org: sfapi.Organization{Name: "First Bank"}, Id: "checking", Name: "Checking", Currency: "USD",
+		Balance: money(2500), BalanceDate: feb1,
+		Transactions: []*sfapi.Transaction{
+			{Id: "t1", Posted: jan5, Amount: money(-12.34), Description: "JITA MARKET #12", Payee: "Jita Market"},
+			{Id: "t2", Posted: jan6, Amount: money(3000), Description: "ACME PAYROLL"},
+			{Id: "t3", TransactedAt: jan31 + 3600, Amount: money(-4.5), Description: "COFFEE", Memo: "latte", Pending: true},
+			{Id: "t4", Posted: feb1, Amount: money(-99), Description: "NEXT MONTH"},
+		},
+	})
+	srv.PutAccount(&sfapi.Account{
+		Org: sfapi.Organization{Name: "Card Co"}, Id: "card", Name: "Card", Currency: "USD", Balance: money(-320.5),
+	})
+	srv.PutAccount(&sfapi.Account{
+		Org: sfapi.Organization{Name: "Euro Bank"}, Id: "euro", Name: "Savings", Currency: "EUR", Balance: money(1000),
+		Transactions: []*sfapi.Transaction{{Id: "e1", Posted: jan6, Amount: money(-20), Description: "BAKERY"}},
+	})
+	return srv
+}
+
+func contextWithClient(srv *simplefintest.Server) context.Context {
+	return sfapi.WithSimpleFINClient(srv.NewClient())(context.Background(), &http.Request{})
+}
+
+func money(units float64) types.Money {
+	return types.Money(units * types.FACTOR)
+}
+
+func TestGetCategorizedTransactions(t *testing.T) {
+	srv := newServer(t)
+	categories, err := rules.Compile([]rules.Rule{
+		{Payee: "jita", Category: "Food/Groceries"},
+		{Payee: "payroll", Category: "Salary"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, _ := types.ParseDate("2024-01-01")
+	end, _ := types.ParseDate("2024-01-31")
+
+	result, err := NewSource("USD", categories).GetCategorizedTransactions(contextWithClient(srv), ds.DateRange{StartDate: start, EndDate: end})
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+
+	groceries := result.Expenses.GetOrCreatePath("Food", "Groceries")
+	if len(groceries.Transactions) != 1 {
+		t.Fatalf("groceries = %+v; want the market", groceries.Transactions)
+	}
+	tx := groceries.Transactions[0]
+	if tx.Amount != money(12.34) || tx.Payee != "Jita Market" || tx.Description != "JITA MARKET #12" || tx.Date.String() != "2024-01-05" {
+		t.Errorf("market = %+v", tx)
+	}
+	if tx.Annotations["account"] != "First Bank Checking" {
+		t.Errorf("market annotations = %v", tx.Annotations)
+	}
+
+	salary := result.Income.GetOrCreatePath("Salary")
+	if len(salary.Transactions) != 1 || salary.Transactions[0].Amount != money(-3000) {
+		t.Errorf("salary = %+v; want the payroll deposit", salary.Transactions)
+	}
+
+	unc := result.Expenses.GetOrCreatePath(types.UncategorizedName).Transactions
+	if len(unc) != 2 {
+		t.Fatalf("uncategorized = %+v; want the coffee and the bakery", unc)
+	}
+	if coffee := unc[0]; coffee.Payee != "COFFEE" || coffee.Description != "latte" || coffee.Annotations["pending"] != "true" {
+		t.Errorf("coffee = %+v; want the pending transaction with its memo", coffee)
+	}
+	if bakery := unc[1]; bakery.Annotations["currency"] != "EUR" {
+		t.Errorf("bakery = %+v; want the currency annotated", bakery)
+	}
+
+	q := srv.Requests()[0].URL.Query()
+	if q.Get("start-date") != "1704067200" || q.Get("end-date") != "1706745600" {
+		t.Errorf("query = %v; want January 2024", q)
+	}
+}
+
+func TestGetPortfolio(t *testing.T) {
+	srv := newServer(t)
+	portfolio, err := NewSource("USD", nil).GetPortfolio(contextWithClient(srv))
+	if err != nil {
+		t.Fatalf("GetPortfolio error: %v", err)
+	}
+	if len(portfolio.Assets) != 2 || len(portfolio.Debts) != 1 {
+		t.Fatalf("portfolio = %+v; want 2 assets and a debt", portfolio)
+	}
+	checking := portfolio.Assets[0]
+	if checking.Name != "First Bank Checking" || checking.Value != money(2500) || checking.Annotations["balance_date"] != "2024-02-01" {
+		t.Errorf("first asset = %+v; want checking", checking)
+	}
+	if euro := portfolio.Assets[1]; euro.Value != 0 || euro.Annotations["note"] == "" {
+		t.Errorf("second asset = %+v; want euro savings without a value and a note", euro)
+	}
+	if card := portfolio.Debts[0]; card.Name != "Card Co Card" || card.Value != money(320.5) {
+		t.Errorf("debt = %+v; want the card", card)
+	}
+	if len(srv.Requests()[0].URL.Query().Get("balances-only")) == 0 {
+		t.Error("expected a balances-only request")
+	}
+}
+
+func TestCheckCredentials(t *testing.T) {
+	srv := newServer(t)
+	if err := CheckCredentials(contextWithClient(srv)); err != nil {
+		t.Errorf("CheckCredentials error: %v", err)
+	}
+	revoked := srv.AccessURL()
+	srv.Password = "rotated"
+	ctx := sfapi.WithSimpleFINCredentials(revoked)(context.Background(), &http.Request{})
+	if err := CheckCredentials(ctx); !errors.Is(err, ds.ErrUnauthorized) {
+		t.Errorf("err = %v; want unauthorized for revoked access URL", err)
+	}
+}
@@ -0,0 +1,233 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// Capability is a kind of data that a data source provides. Tools are offered when a configured data source has
+// the capability they require.
+type Capability string
+
+const (
+	// CapabilityTransactions is provided by data sources of categorized transactions, see TransactionSource.
+	CapabilityTransactions Capability = "transactions"
+	// CapabilityPortfolio is provided by data sources of assets and debts, see PortfolioSource.
+	CapabilityPortfolio Capability = "portfolio"
+	// CapabilityBudgets is provided by data sources of budgeted amounts per category.
+	CapabilityBudgets Capability = "budgets"
+	// CapabilityAccounts is provided by data sources that list accounts and their balances.
+	CapabilityAccounts Capability = "accounts"
+	// CapabilityWrites is provided by data sources that accept changes, such as recategorized transactions.
+	CapabilityWrites Capability = "writes"
+)
+
+// Source is a configured data source.
+type Source interface {
+	// Name identifies the data source by the name of its configuration, such as ofx.
+	Name() string
+	// Capabilities lists the kinds of data the data source provides.
+	Capabilities() []Capability
+}
+
+// TransactionSource is a Source with CapabilityTransactions.
+type TransactionSource interface {
+	Source
+	GetCategorizedTransactions(ctx context.Context, interval DateRange) (*types.Categories, error)
+}
+
+// PortfolioSource is a Source with CapabilityPortfolio.
+type PortfolioSource interface {
+	Source
+	GetPortfolio(ctx context.Context) (*types.Portfolio, error)
+}
+
+// Checker is implemented by sources that can verify that they are reachable, see CheckFunc.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// Funcs is a Source made of the functions of a data source. Its capabilities are those whose functions are set,
+// and the ones it declares.
+type Funcs struct {
+	SourceName       string
+	TransactionsFunc GetCategorizedTransactionsFunc
+	PortfolioFunc    GetPortfolioFunc
+	CheckFunc        CheckFunc
+	// ExtraCapabilities are the capabilities of the data source that have no function of Funcs, such as
+	// CapabilityBudgets. CapabilityTransactions and CapabilityPortfolio follow their functions and are ignored here.
+	ExtraCapabilities []Capability
+}
+
+// Name implements Source.
+func (f *Funcs) Name() string {
+	return f.SourceName
+}
+
+// Capabilities implements Source.
+func (f *Funcs) Capabilities() []Capability {
+	var capabilities []Capability
+	if f.TransactionsFunc != nil {
+		capabilities = append(capabilities, CapabilityTransactions)
+	}
+	if f.PortfolioFunc != nil {
+		capabilities = append(capabilities, CapabilityPortfolio)
+	}
+	for _, c := range f.ExtraCapabilities {
+		if c != CapabilityTransactions && c != CapabilityPortfolio && !slices.Contains(capabilities, c) {
+			capabilities = append(capabilities, c)
+		}
+	}
+	return capabilities
+}
+
+// GetCategorizedTransactions implements TransactionSource.
+func (f *Funcs) GetCategorizedTransactions(ctx context.Context, interval DateRange) (*types.Categories, error) {
+	return f.TransactionsFunc(ctx, interval)
+}
+
+// GetPortfolio implements PortfolioSource.
+func (f *Funcs) GetPortfolio(ctx context.Context) (*types.Portfolio, error) {
+	return f.PortfolioFunc(ctx)
+}
+
+// Check implements Checker. A data source without a check is always reachable.
+func (f *Funcs) Check(ctx context.Context) error {
+	if f.CheckFunc == nil {
+		return nil
+	}
+	return f.CheckFunc(ctx)
+}
+
+// Registry holds the configured data sources, in the order they were registered.
+type Registry struct {
+	sources []Source
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds src to the registry. Names must be unique, and sources must implement the interfaces of the
+// capabilities they claim.
+func (r *Registry) Register(src Source) error {
+	if _, ok := r.Get(src.Name()); ok {
+		return fmt.Errorf("data source %s is already registered", src.Name())
+	}
+	for _, c := range src.Capabilities() {
+		var ok bool
+		switch c {
+		case CapabilityTransactions:
+			_, ok = src.(TransactionSource)
+		case CapabilityPortfolio:
+			_, ok = src.(PortfolioSource)
+		default:
+			ok = true
+		}
+		if !ok {
+			return fmt.Errorf("data source %s claims capability %s without implementing it", src.Name(), c)
+		}
+	}
+	r.sources = append(r.sources, src)
+	return nil
+}
+
+// Get returns the data source with the given name.
+func (r *Registry) Get(name string) (Source, bool) {
+	for _, src := range r.sources {
+		if src.Name() == name {
+			return src, true
+		}
+	}
+	return nil, false
+}
+
+// Sources returns every data source in the order they were registered.
+func (r *Registry) Sources() []Source {
+	return slices.Clone(r.sources)
+}
+
+// Names returns the names of every data source in the order they were registered.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.sources))
+	for _, src := range r.sources {
+		names = append(names, src.Name())
+	}
+	return names
+}
+
+// Has reports whether any data source has capability c.
+func (r *Registry) Has(c Capability) bool {
+	for _, src := range r.sources {
+		if slices.Contains(src.Capabilities(), c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Transactions returns the data sources with CapabilityTransactions, ready to be merged, see MergeTransactions.
+func (r *Registry) Transactions() []Named[GetCategorizedTransactionsFunc] {
+	var result []Named[GetCategorizedTransactionsFunc]
+	for _, src := range r.sources {
+		if slices.Contains(src.Capabilities(), CapabilityTransactions) {
+			result = append(result, Named[GetCategorizedTransactionsFunc]{
+				Name: src.Name(), Fn: src.(TransactionSource).GetCategorizedTransactions,
+			})
+		}
+	}
+	return result
+}
+
+// Portfolios returns the data sources with CapabilityPortfolio, ready to be merged, see MergePortfolios.
+func (r *Registry) Portfolios() []Named[GetPortfolioFunc] {
+	var result []Named[GetPortfolioFunc]
+	for _, src := range r.sources {
+		if slices.Contains(src.Capabilities(), CapabilityPortfolio) {
+			result = append(result, Named[GetPortfolioFunc]{Name: src.Name(), Fn: src.(PortfolioSource).GetPortfolio})
+		}
+	}
+	return result
+}
+
+// Checks returns the checks of the data sources that implement Checker, by data source name.
+func (r *Registry) Checks() map[string]CheckFunc {
+	checks := make(map[string]CheckFunc)
+	for _, src := range r.sources {
+		if checker, ok := src.(Checker); ok {
+			checks[src.Name()] = checker.Check
+		}
+	}
+	return checks
+}
+
+// Check runs the checks of every data source concurrently, each bounded by timeout, and returns their results by
+// data source name.
+func (r *Registry) Check(ctx context.Context, timeout time.Duration) map[string]error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]error)
+	)
+	for name, check := range r.Checks() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := check(ctx)
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,71 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// portfolioOnly claims CapabilityTransactions without implementing TransactionSource.
+type portfolioOnly struct{}
+
+func (portfolioOnly) Name() string               { return "broken" }
+func (portfolioOnly) Capabilities() []Capability { return []Capability{CapabilityTransactions} }
+
+func TestRegistry(t *testing.T) {
+	transactions := func(ctx context.Context, _ DateRange) (*types.Categories, error) { return types.NewCategories(), nil }
+	portfolio := func(ctx context.Context) (*types.Portfolio, error) { return types.NewPortfolio(), nil }
+	unreachable := errors.New("unreachable")
+
+	r := NewRegistry()
+	for _, src := range []Source{
+		&Funcs{SourceName: "bank", TransactionsFunc: transactions, CheckFunc: func(ctx context.Context) error { return nil }},
+		&Funcs{SourceName: "broker", PortfolioFunc: portfolio, CheckFunc: func(ctx context.Context) error { return unreachable }},
+		&Funcs{SourceName: "books", TransactionsFunc: transactions, PortfolioFunc: portfolio, ExtraCapabilities: []Capability{
+			CapabilityAccounts, CapabilityTransactions, CapabilityAccounts,
+		}},
+	} {
+		if err := r.Register(src); err != nil {
+			t.Fatalf("Register(%s) error: %v", src.Name(), err)
+		}
+	}
+	if err := r.Register(&Funcs{SourceName: "bank", PortfolioFunc: portfolio}); err == nil {
+		t.Error("Register succeeded; want an error for a duplicate name")
+	}
+	if err := r.Register(portfolioOnly{}); err == nil {
+		t.Error("Register succeeded; want an error for an unimplemented capability")
+	}
+
+	if names := r.Names(); !slices.Equal(names, []string{"bank", "broker", "books"}) {
+		t.Errorf("Names = %v; want registration order", names)
+	}
+	want := []Capability{CapabilityTransactions, CapabilityPortfolio, CapabilityAccounts}
+	if src, ok := r.Get("books"); !ok || !slices.Equal(src.Capabilities(), want) {
+		t.Errorf("Get(books) = %v, %v; want capabilities %v", src, ok, want)
+	}
+	if !r.Has(CapabilityPortfolio) || !r.Has(CapabilityAccounts) || r.Has(CapabilityBudgets) {
+		t.Error("Has reports the wrong capabilities")
+	}
+	if got := r.Transactions(); len(got) != 2 || got[0].Name != "bank" || got[1].Name != "books" {
+		t.Errorf("Transactions = %v", got)
+	}
+	if got := r.Portfolios(); len(got) != 2 || got[0].Name != "broker" || got[1].Name != "books" {
+		t.Errorf("Portfolios = %v", got)
+	}
+
+	results := r.Check(context.Background(), time.Second)
+	if len(results) != 3 || results["bank"] != nil || results["books"] != nil || !errors.Is(results["broker"], unreachable) {
+		t.Errorf("Check = %v; want only broker unreachable", results)
+	}
+}
+
+func TestFuncs_Capabilities(t *testing.T) {
+	f := &Funcs{ExtraCapabilities: []Capability{CapabilityBudgets, CapabilityTransactions, CapabilityPortfolio}}
+	if got := f.Capabilities(); !slices.Equal(got, []Capability{CapabilityBudgets}) {
+		t.Errorf("Capabilities = %v; want declared capabilities without functions ignored", got)
+	}
+}
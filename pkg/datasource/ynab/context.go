@@ -0,0 +1,29 @@
+package ynab
+
+import (
+	"context"
+	"net/http"
+
+	ynabapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/ynab"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/secrets"
+)
+
+// InjectCredentials returns an HTTP context injector function that injects a YNAB client configured with the
+// given access token and client options into the context of incoming HTTP requests. The current value of the
+// token is used for every request, so rotated tokens take effect immediately.
+func InjectCredentials(token *secrets.Secret, opts ...ynabapi.Option) func(ctx context.Context, req *http.Request) context.Context {
+	return func(ctx context.Context, req *http.Request) context.Context {
+		return ynabapi.WithYNABCredentials(token.Value(), opts...)(ctx, req)
+	}
+}
+
+// clientFromContext returns the YNAB client injected by InjectCredentials, or an ErrNotConfigured error if
+// there is none.
+func clientFromContext(ctx context.Context) (ynabapi.Client, error) {
+	client, ok := ynabapi.FromContext(ctx)
+	if !ok {
+		return nil, ds.Errorf(ds.ErrNotConfigured, "no YNAB client in the request context")
+	}
+	return client, nil
+}
@@ -0,0 +1,128 @@
+package ynab
+
+import (
+	"context"
+
+	ynabapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/ynab"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// entities is a set of YNAB entities by ID, kept up to date with delta requests.
+type entities[T any] struct {
+	// knowledge is the server knowledge of the last request, zero before the first one.
+	knowledge int64
+	items     map[string]T
+}
+
+// put adds or replaces an entity, or removes it if it was deleted.
+func (e *entities[T]) put(id string, item T, deleted bool) {
+	if e.items == nil {
+		e.items = make(map[string]T)
+	}
+	if deleted {
+		delete(e.items, id)
+	} else {
+		e.items[id] = item
+	}
+}
+
+// snapshot is a consistent copy of the synced budget.
+type snapshot struct {
+	accounts     map[string]*ynabapi.Account
+	groups       map[string]*ynabapi.CategoryGroup
+	categories   map[string]*ynabapi.Category
+	transactions []*ynabapi.Transaction
+}
+
+// sync brings the accounts, categories and transactions dated on or after since up to date and returns a
+// snapshot of them. Only the first request of each kind downloads everything; later ones fetch what changed
+// since. Transactions are downloaded again in full when since is earlier than the dates synced so far.
+// The caller must hold s.mu.
+func (s *Source) sync(ctx context.Context, client ynabapi.Client, since types.Date) (*snapshot, error) {
+	if err := s.syncAccounts(ctx, client); err != nil {
+		return nil, err
+	}
+	if err := s.syncCategories(ctx, client); err != nil {
+		return nil, err
+	}
+	if err := s.syncTransactions(ctx, client, since); err != nil {
+		return nil, err
+	}
+
+	snap := &snapshot{
+		accounts:     s.accounts.items,
+		groups:       s.groups.items,
+		categories:   s.categories.items,
+		transactions: make([]*ynabapi.Transaction, 0, len(s.transactions.items)),
+	}
+	for _, txn := range s.transactions.items {
+		snap.transactions = append(snap.transactions, txn)
+	}
+	return snap, nil
+}
+
+func (s *Source) syncAccounts(ctx context.Context, client ynabapi.Client) error {
+	delta, err := client.ListAccounts(ctx, s.budgetID, s.accounts.knowledge)
+	if err != nil {
+		return err
+	}
+	accounts := s.accounts.clone()
+	for _, acct := range delta.Items {
+		accounts.put(acct.Id, acct, acct.Deleted)
+	}
+	accounts.knowledge = delta.ServerKnowledge
+	s.accounts = accounts
+	return nil
+}
+
+func (s *Source) syncCategories(ctx context.Context, client ynabapi.Client) error {
+	delta, err := client.ListCategories(ctx, s.budgetID, s.groups.knowledge)
+	if err != nil {
+		return err
+	}
+	groups, categories := s.groups.clone(), s.categories.clone()
+	for _, group := range delta.Items {
+		groups.put(group.Id, group, group.Deleted)
+		for _, cat := range group.Categories {
+			categories.put(cat.Id, cat, cat.Deleted || group.Deleted)
+		}
+	}
+	groups.knowledge = delta.ServerKnowledge
+	s.groups, s.categories = groups, categories
+	return nil
+}
+
+func (s *Source) syncTransactions(ctx context.Context, client ynabapi.Client, since types.Date) error {
+	transactions := s.transactions.clone()
+	if s.since.IsZero() || since.Before(s.since) {
+		transactions = entities[*ynabapi.Transaction]{}
+	} else {
+		// Deltas are not limited by date, so that transactions moved before the synced dates are seen as well.
+		since = s.since
+	}
+
+	sinceDate := ""
+	if transactions.knowledge == 0 {
+		sinceDate = since.String()
+	}
+	delta, err := client.ListTransactions(ctx, s.budgetID, sinceDate, transactions.knowledge)
+	if err != nil {
+		return err
+	}
+	for _, txn := range delta.Items {
+		transactions.put(txn.Id, txn, txn.Deleted)
+	}
+	transactions.knowledge = delta.ServerKnowledge
+	s.transactions, s.since = transactions, since
+	return nil
+}
+
+// clone returns a copy of the set, so that a failed delta request leaves the original intact and snapshots
+// handed out earlier are never modified.
+func (e entities[T]) clone() entities[T] {
+	items := make(map[string]T, len(e.items))
+	for id, item := range e.items {
+		items[id] = item
+	}
+	return entities[T]{knowledge: e.knowledge, items: items}
+}
@@ -0,0 +1,224 @@
+// Package ynab implements a data source that fetches transactions from a YNAB budget.
+package ynab
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	ynabapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/ynab"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Source fetches transactions from a YNAB budget with the client in the request context. It keeps the budget
+// in memory and uses delta requests, so that repeated calls only download what changed in the meantime.
+type Source struct {
+	budgetID string
+
+	mu           sync.Mutex
+	accounts     entities[*ynabapi.Account]
+	groups       entities[*ynabapi.CategoryGroup]
+	categories   entities[*ynabapi.Category]
+	transactions entities[*ynabapi.Transaction]
+	// since is the earliest date of the synced transactions, zero before the first sync.
+	since types.Date
+}
+
+// NewSource creates a Source that reads the budget with the given ID, or ynabapi.LastUsedBudget.
+func NewSource(budgetID string) *Source {
+	return &Source{budgetID: budgetID}
+}
+
+// Check verifies that YNAB is reachable, accepts the access token and has the budget, by syncing its accounts.
+func (s *Source) Check(ctx context.Context) error {
+	client, err := clientFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.syncAccounts(ctx, client)
+}
+
+// GetCategorizedTransactions implements ds.GetCategorizedTransactionsFunc. Inflows to Ready to Assign are
+// income, transfers and transactions of tracking accounts are ignored and everything else is an expense. Split
+// transactions are divided into their subtransactions.
+func (s *Source) GetCategorizedTransactions(ctx context.Context, interval ds.DateRange) (*types.Categories, error) {
+	snap, err := s.fetch(ctx, interval)
+	if err != nil {
+		return nil, err
+	}
+	return categorizeTransactions(ctx, snap, interval)
+}
+
+// fetch syncs the budget with YNAB and returns a snapshot of it.
+func (s *Source) fetch(ctx context.Context, interval ds.DateRange) (snap *snapshot, err error) {
+	ctx, span := tracing.Start(ctx, "ynab.fetch")
+	defer func() { tracing.End(span, err) }()
+
+	client, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sync(ctx, client, interval.StartDate)
+}
+
+// part is a transaction or one of the subtransactions of a split transaction.
+type part struct {
+	amount            ynabapi.Milliunits
+	payee             string
+	memo              string
+	categoryId        string
+	transferAccountId string
+}
+
+// categorizeTransactions converts YNAB transactions within interval into domain types and sorts them into
+// category trees.
+func categorizeTransactions(ctx context.Context, snap *snapshot, interval ds.DateRange) (result *types.Categories, err error) {
+	ctx, span := tracing.Start(ctx, "ynab.categorize", attribute.Int("transactions", len(snap.transactions)))
+	defer func() { tracing.End(span, err) }()
+
+	// Map iteration order is random, keep the output stable
+	sort.Slice(snap.transactions, func(i, j int) bool {
+		a, b := snap.transactions[i], snap.transactions[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		return a.Id < b.Id
+	})
+
+	result = types.NewCategories()
+	logger := logging.FromContext(ctx)
+	for _, txn := range snap.transactions {
+		date, err := types.ParseDate(txn.Date)
+		if err != nil {
+			return nil, err
+		}
+		if date.Before(interval.StartDate) || interval.EndDate.Before(date) {
+			continue
+		}
+		account := snap.accounts[txn.AccountId]
+
+		for _, p := range splitTransaction(txn) {
+			tx := types.NewTransaction(date, p.payee, -p.amount.Money())
+			tx.Description = p.memo
+			if txn.AccountName != "" {
+				tx.Annotate("account", txn.AccountName)
+			}
+			if txn.Cleared != "" {
+				tx.Annotate("cleared", txn.Cleared)
+			}
+			if txn.FlagColor != "" {
+				tx.Annotate("flag", txn.FlagColor)
+			}
+
+			cat, group := snap.categories[p.categoryId], (*ynabapi.CategoryGroup)(nil)
+			if cat != nil {
+				group = snap.groups[cat.CategoryGroupId]
+			}
+
+			// Determine which "bucket" does the transaction fall under
+			// Transfers between budget accounts are uncategorized and ignored, while a categorized transfer to a
+			// tracking account, such as a mortgage principal payment, leaves the budget and is spent.
+			var bucket *types.Category
+			switch {
+			case (p.transferAccountId != "" && p.categoryId == "") || (account != nil && !account.OnBudget):
+				bucket = result.Ignored
+			case group != nil && group.Name == ynabapi.InternalCategoryGroup && cat.Name == ynabapi.InflowCategory:
+				bucket = result.Income
+			case p.categoryId == "" && p.amount > 0:
+				bucket = result.Income
+			default:
+				bucket = result.Expenses
+			}
+
+			switch {
+			case p.categoryId == "":
+				bucket = getOrCreateCategoryByName(bucket, types.UncategorizedName)
+			case cat == nil:
+				logger.WarnContext(ctx, "Missing category from YNAB response", "category_id", p.categoryId)
+				tx.Annotate("category_error", "uncategorized due to invalid category id")
+				bucket = getOrCreateCategoryByName(bucket, types.UncategorizedName)
+			case group == nil:
+				logger.WarnContext(ctx, "Missing category group from YNAB response", "category_group_id", cat.CategoryGroupId)
+				tx.Annotate("category_error", "uncategorized due to invalid category group id")
+				bucket = getOrCreateCategoryByName(bucket, types.UncategorizedName)
+			case group.Name == ynabapi.InternalCategoryGroup:
+				// Internal categories are placed directly under the bucket
+				bucket = getOrCreateCategoryByName(bucket, cat.Name)
+			default:
+				if group.Name != bucket.Name {
+					bucket = getOrCreateCategoryByName(bucket, group.Name)
+				}
+				bucket = getOrCreateCategory(bucket, cat)
+			}
+			if err := bucket.AddTransaction(tx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// splitTransaction returns the subtransactions of a split transaction, or the transaction itself. Payees and
+// memos of subtransactions default to the ones of the transaction.
+func splitTransaction(txn *ynabapi.Transaction) []part {
+	whole := part{
+		amount:            txn.Amount,
+		payee:             txn.PayeeName,
+		memo:              txn.Memo,
+		categoryId:        txn.CategoryId,
+		transferAccountId: txn.TransferAccountId,
+	}
+	parts := make([]part, 0, len(txn.Subtransactions))
+	for _, sub := range txn.Subtransactions {
+		if sub.Deleted {
+			continue
+		}
+		p := part{
+			amount:            sub.Amount,
+			payee:             sub.PayeeName,
+			memo:              sub.Memo,
+			categoryId:        sub.CategoryId,
+			transferAccountId: sub.TransferAccountId,
+		}
+		if p.payee == "" {
+			p.payee = whole.payee
+		}
+		if p.memo == "" {
+			p.memo = whole.memo
+		}
+		parts = append(parts, p)
+	}
+	if len(parts) == 0 {
+		return []part{whole}
+	}
+	return parts
+}
+
+// getOrCreateCategory finds or creates a subcategory under the parent Category matching the given YNAB
+// Category. It also copies the note from YNAB as the description.
+func getOrCreateCategory(parent *types.Category, ycat *ynabapi.Category) *types.Category {
+	cat := getOrCreateCategoryByName(parent, ycat.Name)
+	cat.Description = ycat.Note
+	return cat
+}
+
+// getOrCreateCategoryByName returns an existing subcategory by name under the parent,
+// or creates and attaches a new Category with that name if none exists.
+func getOrCreateCategoryByName(parent *types.Category, name string) *types.Category {
+	for _, sc := range parent.Subcategories {
+		if sc.Name == name {
+			return sc
+		}
+	}
+	newCat := types.NewCategory(name)
+	_ = parent.AddSubcategory(newCat)
+	return newCat
+}
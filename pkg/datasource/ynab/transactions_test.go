@@ -0,0 +1,206 @@
+package ynab
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	ynabapi "github.com/wyvernzora/personal-finance-mcp/internal/clients/ynab"
+	"github.com/wyvernzora/personal-finance-mcp/internal/clients/ynab/ynabtest"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// newServer starts a fake YNAB server with a checking account, a tracking account and a few categories.
+func newServer(t *testing.T) *ynabtest.Server {
+	t.Helper()
+	srv := ynabtest.NewServer("token", &ynabapi.Budget{Id: "budget-1", Name: "Household"})
+	t.Cleanup(srv.Close)
+
+	srv.PutAccount(&ynabapi.Account{Id: "checking", Name: "Checking", OnBudget: true})
+	srv.PutAccount(&ynabapi.Account{Id: "brokerage", Name: "Brokerage", OnBudget: false})
+	srv.PutCategoryGroup(&ynabapi.CategoryGroup{Id: "internal", Name: ynabapi.InternalCategoryGroup, Categories: []*ynabapi.Category{
+		{Id: "inflow", Name: ynabapi.InflowCategory},
+	}})
+	srv.PutCategoryGroup(&ynabapi.CategoryGroup{Id: "bills", Name: "Bills", Categories: []*ynabapi.Category{
+		{Id: "rent", Name: "Rent", Note: "Due on the 1st"},
+		{Id: "power", Name: "Electricity"},
+	}})
+	srv.PutCategoryGroup(&ynabapi.CategoryGroup{Id: "food", Name: "Food", Categories: []*ynabapi.Category{
+		{Id: "groceries", Name: "Groceries"},
+	}})
+	return srv
+}
+
+func contextWithClient(srv *ynabtest.Server) context.Context {
+	return ynabapi.WithYNABClient(srv.NewClient())(context.Background(), &http.Request{})
+}
+
+func dateRange(t *testing.T, start, end string) ds.DateRange {
+	t.Helper()
+	s, err := types.ParseDate(start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := types.ParseDate(end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ds.DateRange{StartDate: s, EndDate: e}
+}
+
+func TestGetCategorizedTransactions(t *testing.T) {
+	srv := newServer(t)
+	srv.PutTransaction(&ynabapi.Transaction{
+		Id: "t1", Date: "2024-01-01", Amount: -1500000, AccountId: "checking", AccountName: "Checking",
+		PayeeName: "Landlord", CategoryId: "rent", Cleared: "cleared", FlagColor: "red",
+	})
+	srv.PutTransaction(&ynabapi.Transaction{
+		Id: "t2", Date: "2024-01-05", Amount: 3000000, AccountId: "checking", PayeeName: "Employer", CategoryId: "inflow",
+	})
+	srv.PutTransaction(&ynabapi.Transaction{
+		Id: "t3", Date: "2024-01-06", Amount: -120000, AccountId: "checking", PayeeName: "Market", Memo: "weekly",
+		Subtransactions: []*ynabapi.Subtransaction{
+			{Id: "s1", Amount: -100000, CategoryId: "groceries"},
+			{Id: "s2", Amount: -20000, CategoryId: "power", Memo: "prepaid meter"},
+		},
+	})
+	srv.PutTransaction(&ynabapi.Transaction{
+		Id: "t4", Date: "2024-01-07", Amount: -500000, AccountId: "checking", PayeeName: "Transfer : Brokerage",
+		TransferAccountId: "brokerage",
+	})
+	srv.PutTransaction(&ynabapi.Transaction{Id: "t5", Date: "2024-01-08", Amount: 25000, AccountId: "brokerage", PayeeName: "Dividend"})
+	srv.PutTransaction(&ynabapi.Transaction{Id: "t6", Date: "2024-01-09", Amount: -7000, AccountId: "checking", PayeeName: "Kiosk"})
+	srv.PutTransaction(&ynabapi.Transaction{Id: "t7", Date: "2024-02-01", Amount: -1500000, AccountId: "checking", CategoryId: "rent"})
+
+	src := NewSource("budget-1")
+	result, err := src.GetCategorizedTransactions(contextWithClient(srv), dateRange(t, "2024-01-01", "2024-01-31"))
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+
+	if len(result.Expenses.Subcategories) != 3 {
+		t.Fatalf("expense categories = %d; want Bills, Food and Uncategorized", len(result.Expenses.Subcategories))
+	}
+	bills := result.Expenses.Subcategories[0]
+	if bills.Name != "Bills" || bills.Subcategories[0].Name != "Rent" || bills.Subcategories[0].Description != "Due on the 1st" {
+		t.Fatalf("first expense category = %+v; want Bills/Rent with the note", bills)
+	}
+	tx := bills.Subcategories[0].Transactions[0]
+	if tx.Amount != types.Money(15000000) || tx.Payee != "Landlord" {
+		t.Errorf("rent = %+v; want 1500.00 to Landlord", tx)
+	}
+	if tx.Annotations["account"] != "Checking" || tx.Annotations["cleared"] != "cleared" || tx.Annotations["flag"] != "red" {
+		t.Errorf("rent annotations = %v", tx.Annotations)
+	}
+
+	power := bills.Subcategories[1]
+	if power.Name != "Electricity" || power.Transactions[0].Amount != types.Money(200000) ||
+		power.Transactions[0].Payee != "Market" || power.Transactions[0].Description != "prepaid meter" {
+		t.Errorf("electricity = %+v; want the split part of the market transaction", power.Transactions)
+	}
+	groceries := result.Expenses.Subcategories[1].Subcategories[0]
+	if groceries.Name != "Groceries" || groceries.Transactions[0].Description != "weekly" {
+		t.Errorf("groceries = %+v; want the split part with the memo of the transaction", groceries)
+	}
+	if unc := result.Expenses.Subcategories[2]; unc.Name != types.UncategorizedName || unc.Transactions[0].Payee != "Kiosk" {
+		t.Errorf("uncategorized expenses = %+v; want Kiosk", unc)
+	}
+
+	inflow := result.Income.Subcategories[0]
+	if inflow.Name != ynabapi.InflowCategory || inflow.Transactions[0].Amount != types.Money(-30000000) {
+		t.Errorf("income = %+v; want 3000.00 in Ready to Assign", inflow)
+	}
+	if len(result.Ignored.Subcategories) != 1 || len(result.Ignored.Subcategories[0].Transactions) != 2 {
+		t.Errorf("ignored = %+v; want the transfer and the tracking account transaction", result.Ignored.Subcategories)
+	}
+}
+
+func TestGetCategorizedTransactions_CategorizedTransfer(t *testing.T) {
+	srv := newServer(t)
+	srv.PutCategoryGroup(&ynabapi.CategoryGroup{Id: "savings", Name: "Savings", Categories: []*ynabapi.Category{
+		{Id: "retirement", Name: "401k"},
+	}})
+	srv.PutTransaction(&ynabapi.Transaction{
+		Id: "t1", Date: "2024-01-15", Amount: -500000, AccountId: "checking", PayeeName: "Transfer : Brokerage",
+		TransferAccountId: "brokerage", CategoryId: "retirement",
+	})
+	srv.PutTransaction(&ynabapi.Transaction{
+		Id: "t2", Date: "2024-01-15", Amount: 500000, AccountId: "brokerage", PayeeName: "Transfer : Checking",
+		TransferAccountId: "checking",
+	})
+
+	src := NewSource("budget-1")
+	result, err := src.GetCategorizedTransactions(contextWithClient(srv), dateRange(t, "2024-01-01", "2024-01-31"))
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+
+	if result.Expenses.TotalAmount != types.Money(5000000) || len(result.Expenses.Subcategories) != 1 {
+		t.Fatalf("expenses = %+v; want the categorized transfer to the tracking account spent", result.Expenses)
+	}
+	if retirement := result.Expenses.Subcategories[0].Subcategories[0]; retirement.Name != "401k" || len(retirement.Transactions) != 1 {
+		t.Errorf("expense category = %+v; want Savings/401k", retirement)
+	}
+	if len(result.Ignored.Subcategories) != 1 || len(result.Ignored.Subcategories[0].Transactions) != 1 ||
+		result.Ignored.Subcategories[0].Transactions[0].Payee != "Transfer : Checking" {
+		t.Errorf("ignored = %+v; want only the tracking account side of the transfer", result.Ignored.Subcategories)
+	}
+}
+
+func TestGetCategorizedTransactions_DeltaSync(t *testing.T) {
+	srv := newServer(t)
+	srv.PutTransaction(&ynabapi.Transaction{Id: "t1", Date: "2024-01-02", Amount: -10000, AccountId: "checking", CategoryId: "groceries"})
+	srv.PutTransaction(&ynabapi.Transaction{Id: "t2", Date: "2024-01-03", Amount: -20000, AccountId: "checking", CategoryId: "groceries"})
+	src := NewSource(ynabapi.LastUsedBudget)
+	ctx := contextWithClient(srv)
+	january := dateRange(t, "2024-01-01", "2024-01-31")
+
+	if _, err := src.GetCategorizedTransactions(ctx, january); err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+	knowledge := srv.ServerKnowledge()
+	srv.PutTransaction(&ynabapi.Transaction{Id: "t1", Date: "2024-01-02", Deleted: true})
+	srv.PutTransaction(&ynabapi.Transaction{Id: "t3", Date: "2024-01-04", Amount: -30000, AccountId: "checking", CategoryId: "groceries"})
+
+	result, err := src.GetCategorizedTransactions(ctx, january)
+	if err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+	txs := result.Expenses.Subcategories[0].Subcategories[0].Transactions
+	if len(txs) != 2 || txs[0].Amount != types.Money(200000) || txs[1].Amount != types.Money(300000) {
+		t.Errorf("groceries = %+v; want t2 and t3", txs)
+	}
+
+	reqs := srv.Requests()
+	last := reqs[len(reqs)-1].URL.Query()
+	if last.Get("last_knowledge_of_server") != strconv.FormatInt(knowledge, 10) || last.Get("since_date") != "" {
+		t.Errorf("last transactions query = %v; want a delta request", last)
+	}
+
+	// Requesting earlier dates downloads transactions again
+	if _, err := src.GetCategorizedTransactions(ctx, dateRange(t, "2023-12-01", "2024-01-31")); err != nil {
+		t.Fatalf("GetCategorizedTransactions error: %v", err)
+	}
+	reqs = srv.Requests()
+	last = reqs[len(reqs)-1].URL.Query()
+	if last.Has("last_knowledge_of_server") || last.Get("since_date") != "2023-12-01" {
+		t.Errorf("last transactions query = %v; want a full request since 2023-12-01", last)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	srv := newServer(t)
+	if err := NewSource("budget-1").Check(contextWithClient(srv)); err != nil {
+		t.Errorf("Check error: %v", err)
+	}
+	if err := NewSource("missing").Check(contextWithClient(srv)); err == nil {
+		t.Error("expected error for unknown budget")
+	}
+	ctx := ynabapi.WithYNABCredentials("wrong", ynabapi.WithBaseURL(srv.URL))(context.Background(), &http.Request{})
+	if err := NewSource("budget-1").Check(ctx); !errors.Is(err, ds.ErrUnauthorized) {
+		t.Errorf("err = %v; want unauthorized", err)
+	}
+}
@@ -0,0 +1,300 @@
+// Package duplicates finds transactions that are likely reported twice, such as when the same card feeds two data
+// sources or a data source imports a statement twice, and keeps them from being counted twice.
+package duplicates
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/render"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CategoryName is the name of the ignored category that suspected duplicates are moved to.
+const CategoryName = "Duplicates"
+
+// Options are the thresholds two transactions of different data sources must meet to be considered duplicates.
+// Their amounts must always be equal. Transactions of the same data source are only duplicates if their dates,
+// amounts and payees are identical, since a data source reports the same transaction the same way twice.
+type Options struct {
+	// MaxDays is the maximum number of days between the dates of duplicates, which may differ when one data
+	// source reports the date of purchase and another the date of posting.
+	MaxDays int
+	// MinSimilarity is the minimum similarity of the payees of duplicates, between 0 and 1, see Similarity.
+	MinSimilarity float64
+}
+
+// Detector finds clusters of likely duplicate transactions.
+type Detector struct {
+	opts Options
+}
+
+// New creates a Detector with the given thresholds.
+func New(opts Options) *Detector {
+	return &Detector{opts: opts}
+}
+
+// Match is a transaction of a cluster of duplicates.
+type Match struct {
+	*types.Transaction
+	// Category is the path of the category of the transaction, such as Expenses/Food.
+	Category string `json:"category"`
+	// Duplicate is false for the transaction that is kept and true for its suspected duplicates.
+	Duplicate bool `json:"duplicate"`
+}
+
+// Cluster is a group of transactions that are likely the same. The first transaction is kept, preferring
+// categorized transactions and then earlier ones, and the others are its suspected duplicates.
+type Cluster struct {
+	Transactions []*Match `json:"transactions"`
+
+	// categories are the categories holding the transactions, in the same order.
+	categories []*types.Category
+}
+
+// Find returns the clusters of likely duplicates among the income and expense transactions of cats, ordered by
+// the date of their first transaction. Ignored transactions are left out, since they are not counted anyway.
+// Transactions are told apart by their source annotation, see datasource.MergeTransactions, and those without one
+// are treated as the transactions of a single data source.
+func (d *Detector) Find(cats *types.Categories) []*Cluster {
+	type entry struct {
+		txn      *types.Transaction
+		category *types.Category
+		path     string
+	}
+	var entries []entry
+	var walk func(prefix string, cat *types.Category)
+	walk = func(prefix string, cat *types.Category) {
+		path := prefix + cat.Name
+		for _, txn := range cat.Transactions {
+			entries = append(entries, entry{txn: txn, category: cat, path: path})
+		}
+		for _, sub := range cat.Subcategories {
+			walk(path+"/", sub)
+		}
+	}
+	for _, root := range []*types.Category{cats.Income, cats.Expenses} {
+		if root != nil {
+			walk("", root)
+		}
+	}
+
+	// Only transactions with equal amounts can be duplicates, so compare those in date order. Each cluster is
+	// anchored on its earliest transaction and only takes transactions within MaxDays of it, so that a charge that
+	// recurs every day is not chained into a single cluster. It takes at most one transaction of every other data
+	// source, and only identical ones of a data source it already holds.
+	byAmount := make(map[types.Money][]int)
+	for i, e := range entries {
+		byAmount[e.txn.Amount] = append(byAmount[e.txn.Amount], i)
+	}
+	clustered := make([]bool, len(entries))
+	var groups [][]int
+	for _, group := range byAmount {
+		slices.SortStableFunc(group, func(a, b int) int {
+			return time.Time(entries[a].txn.Date).Compare(time.Time(entries[b].txn.Date))
+		})
+		for i, a := range group {
+			if clustered[a] {
+				continue
+			}
+			anchor := entries[a].txn
+			seen := sources(anchor)
+			members := []int{a}
+			for _, b := range group[i+1:] {
+				txn := entries[b].txn
+				if days(anchor.Date, txn.Date) > d.opts.MaxDays {
+					break
+				}
+				if clustered[b] {
+					continue
+				}
+				other := sources(txn)
+				if slices.ContainsFunc(other, func(s string) bool { return slices.Contains(seen, s) }) {
+					if days(anchor.Date, txn.Date) == 0 && normalize(txn.Payee) == normalize(anchor.Payee) {
+						members = append(members, b)
+					}
+					continue
+				}
+				if Similarity(anchor.Payee, txn.Payee) >= d.opts.MinSimilarity {
+					members = append(members, b)
+					seen = append(seen, other...)
+				}
+			}
+			if len(members) < 2 {
+				continue
+			}
+			for _, idx := range members {
+				clustered[idx] = true
+			}
+			groups = append(groups, members)
+		}
+	}
+
+	var clusters []*Cluster
+	for _, group := range groups {
+		slices.SortStableFunc(group, func(a, b int) int {
+			return cmp.Or(
+				cmp.Compare(uncategorized(entries[a].category), uncategorized(entries[b].category)),
+				time.Time(entries[a].txn.Date).Compare(time.Time(entries[b].txn.Date)),
+				cmp.Compare(a, b),
+			)
+		})
+		cluster := &Cluster{}
+		for i, idx := range group {
+			e := entries[idx]
+			cluster.Transactions = append(cluster.Transactions, &Match{Transaction: e.txn, Category: e.path, Duplicate: i > 0})
+			cluster.categories = append(cluster.categories, e.category)
+		}
+		clusters = append(clusters, cluster)
+	}
+	slices.SortFunc(clusters, func(a, b *Cluster) int {
+		x, y := a.Transactions[0], b.Transactions[0]
+		return cmp.Or(time.Time(x.Date).Compare(time.Time(y.Date)), cmp.Compare(x.Payee, y.Payee), cmp.Compare(x.Amount, y.Amount))
+	})
+	return clusters
+}
+
+// Ignore moves the suspected duplicates in cats to the ignored Duplicates category, annotated with the
+// transaction they duplicate and the category they were in, and returns the number of transactions moved.
+// Categories left empty are removed.
+func (d *Detector) Ignore(cats *types.Categories) (int, error) {
+	moved := 0
+	for _, cluster := range d.Find(cats) {
+		kept := cluster.Transactions[0]
+		for i, m := range cluster.Transactions[1:] {
+			category := cluster.categories[i+1]
+			if err := category.RemoveTransaction(m.Transaction); err != nil {
+				return moved, err
+			}
+			removeEmpty(category)
+			m.Annotate("duplicate_of", fmt.Sprintf("%s %s %s in %s", kept.Date, kept.Payee, render.Money(kept.Amount), kept.Category))
+			m.Annotate("category", m.Category)
+			if err := cats.Ignored.GetOrCreatePath(CategoryName).AddTransaction(m.Transaction); err != nil {
+				return moved, err
+			}
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+// Apply wraps a data source so that the suspected duplicates among its transactions are ignored.
+func (d *Detector) Apply(fn ds.GetCategorizedTransactionsFunc) ds.GetCategorizedTransactionsFunc {
+	return func(ctx context.Context, interval ds.DateRange) (*types.Categories, error) {
+		result, err := fn(ctx, interval)
+		if err != nil {
+			return nil, err
+		}
+		_, span := tracing.Start(ctx, "duplicates.ignore")
+		if result.Ignored == nil {
+			result.Ignored = types.NewCategory("Ignored")
+		}
+		moved, err := d.Ignore(result)
+		span.SetAttributes(attribute.Int("duplicates", moved))
+		tracing.End(span, err)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
+
+// removeEmpty removes cat, and then each of its ancestors in turn, while it holds neither transactions nor
+// subcategories. Root categories are kept.
+func removeEmpty(cat *types.Category) {
+	for cat.Parent != nil && len(cat.Transactions) == 0 && len(cat.Subcategories) == 0 {
+		parent := cat.Parent
+		_ = parent.RemoveSubcategory(cat)
+		cat = parent
+	}
+}
+
+// sources returns the names of the data sources that reported txn, from its source annotation. Transactions
+// without one are all reported by the same unnamed data source.
+func sources(txn *types.Transaction) []string {
+	return strings.Split(txn.Annotations["source"], ", ")
+}
+
+// uncategorized returns 1 for the Uncategorized category and 0 for others, so that categorized transactions sort
+// first.
+func uncategorized(cat *types.Category) int {
+	if cat.Name == types.UncategorizedName {
+		return 1
+	}
+	return 0
+}
+
+// days returns the number of days between two dates.
+func days(a, b types.Date) int {
+	d := time.Time(b).Sub(time.Time(a)).Hours() / 24
+	if d < 0 {
+		d = -d
+	}
+	return int(d + 0.5)
+}
+
+// Similarity returns how similar two payees are, between 0 and 1. Payees are compared regardless of case,
+// punctuation and numbers, such as store numbers, and a payee that is contained in the other, such as Whole Foods
+// in Whole Foods Market, is as similar as an identical one. Otherwise the similarity is the share of characters
+// that do not need to be edited to turn one into the other.
+func Similarity(a, b string) float64 {
+	a, b = normalize(a), normalize(b)
+	if a == b {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0
+	}
+	shorter, longer := a, b
+	if len(shorter) > len(longer) {
+		shorter, longer = longer, shorter
+	}
+	if len(shorter) >= 4 && strings.Contains(" "+longer+" ", " "+shorter+" ") {
+		return 1
+	}
+	x, y := []rune(a), []rune(b)
+	return 1 - float64(distance(x, y))/float64(max(len(x), len(y)))
+}
+
+// normalize lowercases a payee and reduces it to its words, without punctuation and numbers.
+func normalize(payee string) string {
+	words := strings.FieldsFunc(strings.ToLower(payee), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, word := range words {
+		if strings.IndexFunc(word, unicode.IsLetter) >= 0 {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,193 @@
+package duplicates
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func date(t *testing.T, s string) types.Date {
+	t.Helper()
+	d, err := types.ParseDate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// add adds a transaction reported by the named data source, if any, to cat.
+func add(t *testing.T, cat *types.Category, d, payee string, amount types.Money, source string) {
+	t.Helper()
+	txn := types.NewTransaction(date(t, d), payee, amount)
+	if source != "" {
+		txn.Annotate("source", source)
+	}
+	if err := cat.AddTransaction(txn); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testCategories returns transactions where the card purchase at Whole Foods is reported by the card and the bank
+// a day apart, and the Netflix subscription was imported twice by Lunch Money, in different categories.
+func testCategories(t *testing.T) *types.Categories {
+	cats := types.NewCategories()
+	groceries := cats.Expenses.GetOrCreatePath("Food", "Groceries")
+	add(t, groceries, "2024-03-04", "Whole Foods", 873500, "card")
+	add(t, cats.Expenses.GetOrCreatePath(types.UncategorizedName), "2024-03-05", "WHOLE FOODS MARKET #10234", 873500, "bank")
+	add(t, cats.Expenses.GetOrCreatePath("Subscriptions"), "2024-03-10", "Netflix.com", 154900, "lunch_money")
+	add(t, cats.Expenses.GetOrCreatePath("Streaming"), "2024-03-10", "NETFLIX.COM", 154900, "lunch_money")
+	// Same amount and payee, but a month apart: a recurring charge rather than a duplicate.
+	add(t, cats.Expenses.GetOrCreatePath("Subscriptions"), "2024-04-10", "Netflix.com", 154900, "lunch_money")
+	// Same amount and date, but a different payee.
+	add(t, groceries, "2024-03-04", "Trader Joe's", 873500, "bank")
+	add(t, cats.Income.GetOrCreatePath("Salary"), "2024-03-15", "Acme Corp", -50000000, "bank")
+	return cats
+}
+
+func TestFind(t *testing.T) {
+	clusters := New(Options{MaxDays: 3, MinSimilarity: 0.8}).Find(testCategories(t))
+	if len(clusters) != 2 {
+		t.Fatalf("clusters = %d; want 2", len(clusters))
+	}
+
+	food := clusters[0].Transactions
+	if len(food) != 2 || food[0].Payee != "Whole Foods" || food[0].Duplicate || !food[1].Duplicate {
+		t.Errorf("first cluster = %+v; want the categorized Whole Foods transaction kept", food)
+	}
+	if food[0].Category != "Expenses/Food/Groceries" || food[1].Category != "Expenses/Uncategorized" {
+		t.Errorf("categories = %q, %q", food[0].Category, food[1].Category)
+	}
+
+	netflix := clusters[1].Transactions
+	if len(netflix) != 2 || netflix[0].Category != "Expenses/Subscriptions" || netflix[1].Category != "Expenses/Streaming" {
+		t.Errorf("second cluster = %+v; want the first Netflix transaction kept", netflix)
+	}
+
+	if clusters := New(Options{MaxDays: 0, MinSimilarity: 0.8}).Find(testCategories(t)); len(clusters) != 1 {
+		t.Errorf("clusters = %d; want only the same-day duplicate without a date window", len(clusters))
+	}
+}
+
+func TestFind_RecurringCharge(t *testing.T) {
+	detector := New(Options{MaxDays: 3, MinSimilarity: 0.8})
+	cats := types.NewCategories()
+	coffee := cats.Expenses.GetOrCreatePath("Coffee")
+	for day := 1; day <= 30; day++ {
+		add(t, coffee, fmt.Sprintf("2024-03-%02d", day), "Blue Bottle", 4500, "card")
+	}
+	if clusters := detector.Find(cats); len(clusters) != 0 {
+		t.Errorf("clusters = %d; want a charge repeated daily by one data source left alone", len(clusters))
+	}
+	if _, err := detector.Ignore(cats); err != nil {
+		t.Fatal(err)
+	}
+	if len(coffee.Transactions) != 30 || cats.Ignored.FindSubcategory(CategoryName) != nil {
+		t.Errorf("Coffee = %d transactions; want none of them ignored", len(coffee.Transactions))
+	}
+
+	// The bank reports every charge again a day later: each is a duplicate of exactly one charge of the card.
+	for day := 2; day <= 31; day++ {
+		add(t, coffee, fmt.Sprintf("2024-03-%02d", day), "BLUE BOTTLE COFFEE", 4500, "bank")
+	}
+	clusters := detector.Find(cats)
+	if len(clusters) != 30 {
+		t.Fatalf("clusters = %d; want one per charge", len(clusters))
+	}
+	for _, cluster := range clusters {
+		kept, dup := cluster.Transactions[0], cluster.Transactions[len(cluster.Transactions)-1]
+		if len(cluster.Transactions) != 2 || days(kept.Date, dup.Date) != 1 {
+			t.Errorf("cluster = %+v; want a charge paired with its copy a day later", cluster.Transactions)
+		}
+	}
+	if moved, err := detector.Ignore(cats); err != nil || moved != 30 || len(coffee.Transactions) != 30 {
+		t.Errorf("Ignore = %d, %v, with %d left; want only the 30 copies ignored", moved, err, len(coffee.Transactions))
+	}
+}
+
+func TestFind_SameSource(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		source string
+	}{
+		{"annotated", "lunch_money"},
+		{"without source", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cats := types.NewCategories()
+			shopping := cats.Expenses.GetOrCreatePath("Shopping")
+			add(t, shopping, "2024-03-04", "Amazon.com", 25000, tc.source)
+			add(t, shopping, "2024-03-04", "AMAZON.COM", 25000, tc.source)
+			// Similar payee or a day later: a second order of the same data source rather than a double import.
+			add(t, shopping, "2024-03-04", "Amazon Marketplace", 25000, tc.source)
+			add(t, shopping, "2024-03-05", "Amazon.com", 25000, tc.source)
+			// Another data source reports the first order again, and is matched as usual.
+			add(t, shopping, "2024-03-06", "AMAZON MKTPL", 25000, "card")
+
+			clusters := New(Options{MaxDays: 3, MinSimilarity: 0.5}).Find(cats)
+			if len(clusters) != 1 {
+				t.Fatalf("clusters = %d; want 1", len(clusters))
+			}
+			var payees []string
+			for _, m := range clusters[0].Transactions {
+				payees = append(payees, m.Payee+" "+m.Date.String())
+			}
+			want := []string{"Amazon.com 2024-03-04", "AMAZON.COM 2024-03-04", "AMAZON MKTPL 2024-03-06"}
+			if !slices.Equal(payees, want) {
+				t.Errorf("cluster = %v; want %v", payees, want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	fn := New(Options{MaxDays: 3, MinSimilarity: 0.8}).Apply(func(ctx context.Context, _ ds.DateRange) (*types.Categories, error) {
+		return testCategories(t), nil
+	})
+	cats, err := fn(context.Background(), ds.DateRange{})
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	if cats.Expenses.TotalAmount != 873500+154900+154900+873500 {
+		t.Errorf("Expenses = %v; want duplicates no longer counted", cats.Expenses.TotalAmount)
+	}
+	if cats.Expenses.FindSubcategory(types.UncategorizedName) != nil || cats.Expenses.FindSubcategory("Streaming") != nil {
+		t.Errorf("Expenses = %+v; want categories left empty removed", cats.Expenses.Subcategories)
+	}
+	dups := cats.Ignored.FindSubcategory(CategoryName)
+	if dups == nil || len(dups.Transactions) != 2 || dups.TotalAmount != 873500+154900 {
+		t.Fatalf("Ignored = %+v; want the duplicates", cats.Ignored)
+	}
+	annotations := dups.Transactions[0].Annotations
+	if annotations["duplicate_of"] != "2024-03-04 Whole Foods 87.35 in Expenses/Food/Groceries" ||
+		annotations["category"] != "Expenses/Uncategorized" {
+		t.Errorf("annotations = %v", annotations)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want float64
+	}{
+		{"Whole Foods", "WHOLE FOODS MARKET #10234", 1},
+		{"Netflix.com", "NETFLIX.COM", 1},
+		{"Starbucks", "Starbucks #1234", 1},
+		{"Amazon", "Amazon Prime", 1},
+		{"Uber", "Uber Eats", 1},
+		{"Shell", "Shell Oil 5744", 1},
+		{"Trader Joe's", "Whole Foods", 0.25},
+		{"", "Whole Foods", 0},
+	} {
+		if got := Similarity(tc.a, tc.b); got < tc.want-0.1 || got > tc.want+0.1 {
+			t.Errorf("Similarity(%q, %q) = %.2f; want about %.2f", tc.a, tc.b, got, tc.want)
+		}
+	}
+	if got := Similarity("Costco Gas", "Costco Whse"); got >= 0.8 {
+		t.Errorf("Similarity = %.2f; want different departments apart", got)
+	}
+}
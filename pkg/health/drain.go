@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Drain tracks in-flight tool calls, so that shutdown can wait for them to complete.
+type Drain struct {
+	mu       sync.Mutex
+	inFlight sync.WaitGroup
+	draining bool
+}
+
+// NewDrain creates a Drain with no tool calls in flight.
+func NewDrain() *Drain {
+	return &Drain{}
+}
+
+// ToolMiddleware returns tool handler middleware that tracks in-flight tool calls and, once draining has begun,
+// rejects new ones with a retryable error, so that clients retry against another instance.
+func (d *Drain) ToolMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			d.mu.Lock()
+			if d.draining {
+				d.mu.Unlock()
+				return mcp.NewToolResultError("server is shutting down; retry the call shortly"), nil
+			}
+			d.inFlight.Add(1)
+			d.mu.Unlock()
+
+			defer d.inFlight.Done()
+			return next(ctx, req)
+		}
+	}
+}
+
+// Wait stops accepting new tool calls and waits until all in-flight tool calls complete or ctx is done.
+func (d *Drain) Wait(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
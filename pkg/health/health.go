@@ -0,0 +1,65 @@
+// Package health provides the HTTP endpoints probed by container orchestrators, namely liveness, readiness
+// and version, and tracks in-flight tool calls so that they can be drained before the server shuts down.
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+)
+
+// writeJSON writes v as the JSON response body with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// LivenessHandler returns a handler that reports that the process is alive. It never checks dependencies,
+// so that an upstream outage does not get the server restarted.
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+}
+
+// Version describes the running build.
+type Version struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	ModuleVersion string `json:"module_version,omitempty"`
+	GoVersion     string `json:"go_version,omitempty"`
+	Revision      string `json:"revision,omitempty"`
+	RevisionTime  string `json:"revision_time,omitempty"`
+	Modified      bool   `json:"modified,omitempty"`
+}
+
+// ReadVersion returns the version of the running build, filled in from the build information embedded
+// in the binary where available.
+func ReadVersion(name, version string) Version {
+	v := Version{Name: name, Version: version}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return v
+	}
+	v.ModuleVersion = info.Main.Version
+	v.GoVersion = info.GoVersion
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			v.Revision = setting.Value
+		case "vcs.time":
+			v.RevisionTime = setting.Value
+		case "vcs.modified":
+			v.Modified = setting.Value == "true"
+		}
+	}
+	return v
+}
+
+// VersionHandler returns a handler that reports version.
+func VersionHandler(version Version) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, version)
+	})
+}
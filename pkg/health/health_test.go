@@ -0,0 +1,120 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+)
+
+func TestReadiness_CachesResults(t *testing.T) {
+	calls := 0
+	var failWith error
+	r := NewReadiness(time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	r.SetChecks(func(ctx context.Context) context.Context { return ctx }, map[string]ds.CheckFunc{
+		"lunch_money": func(ctx context.Context) error {
+			calls++
+			return failWith
+		},
+	})
+
+	if _, ready := r.Check(context.Background()); !ready {
+		t.Fatal("expected ready")
+	}
+	failWith = errors.New("unauthorized")
+	now = now.Add(30 * time.Second)
+	if _, ready := r.Check(context.Background()); !ready || calls != 1 {
+		t.Fatalf("ready = %v, calls = %d; want cached ready result", ready, calls)
+	}
+	now = now.Add(time.Minute)
+	results, ready := r.Check(context.Background())
+	if ready || calls != 2 || results["lunch_money"] == nil {
+		t.Errorf("ready = %v, calls = %d, results = %v; want failed check after expiration", ready, calls, results)
+	}
+}
+
+func TestReadiness_Handler(t *testing.T) {
+	r := NewReadiness(time.Minute)
+	r.SetChecks(func(ctx context.Context) context.Context { return ctx }, map[string]ds.CheckFunc{
+		"kubera":      func(ctx context.Context) error { return nil },
+		"lunch_money": func(ctx context.Context) error { return errors.New("unauthorized") },
+	})
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d; want 503", rec.Code)
+	}
+	var body readinessResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Checks["kubera"] != "ok" || body.Checks["lunch_money"] != "unauthorized" {
+		t.Errorf("checks = %v", body.Checks)
+	}
+
+	r.SetChecks(func(ctx context.Context) context.Context { return ctx }, map[string]ds.CheckFunc{
+		"kubera": func(ctx context.Context) error { return nil },
+	})
+	rec = httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d; want 200 after checks were replaced", rec.Code)
+	}
+
+	r.SetDraining()
+	rec = httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d; want 503 while draining", rec.Code)
+	}
+}
+
+func TestDrain_WaitsForInFlightCalls(t *testing.T) {
+	d := NewDrain()
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := d.ToolMiddleware()(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-release
+		return mcp.NewToolResultText("done"), nil
+	})
+	go func() { _, _ = handler(context.Background(), mcp.CallToolRequest{}) }()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := d.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait = %v; want deadline exceeded while a call is in flight", err)
+	}
+
+	result, _ := handler(context.Background(), mcp.CallToolRequest{})
+	if !result.IsError {
+		t.Error("new tool call accepted while draining")
+	}
+
+	close(release)
+	if err := d.Wait(context.Background()); err != nil {
+		t.Errorf("Wait = %v; want nil after in-flight call completed", err)
+	}
+}
+
+func TestVersionHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	VersionHandler(ReadVersion("personal-finance-mcp", "1.0.0")).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	var v Version
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "personal-finance-mcp" || v.Version != "1.0.0" || v.GoVersion == "" {
+		t.Errorf("version = %+v", v)
+	}
+}
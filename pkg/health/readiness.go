@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+)
+
+// checkTimeout bounds how long a single readiness check may take.
+const checkTimeout = 10 * time.Second
+
+// Readiness reports whether the server is ready to serve tool calls, which requires every configured data
+// source to accept its credentials. Check results are cached, so that frequent probes do not exhaust
+// upstream API rate limits.
+type Readiness struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu sync.Mutex
+	// checks are the named data source checks.
+	checks map[string]ds.CheckFunc
+	// contextFunc prepares the context of checks, e.g. by injecting API clients.
+	contextFunc func(ctx context.Context) context.Context
+	// results are the cached results of the last run, which expire at checkedAt + ttl.
+	results   map[string]error
+	checkedAt time.Time
+	// draining is set once the server begins shutting down.
+	draining bool
+}
+
+// NewReadiness creates a Readiness that caches check results for ttl.
+func NewReadiness(ttl time.Duration) *Readiness {
+	return &Readiness{
+		ttl:         ttl,
+		now:         time.Now,
+		contextFunc: func(ctx context.Context) context.Context { return ctx },
+	}
+}
+
+// SetChecks replaces the checks, discarding cached results. contextFunc prepares the context of every check.
+func (r *Readiness) SetChecks(contextFunc func(ctx context.Context) context.Context, checks map[string]ds.CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = checks
+	r.contextFunc = contextFunc
+	r.results = nil
+}
+
+// SetDraining marks the server as shutting down, after which it is never ready again.
+func (r *Readiness) SetDraining() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.draining = true
+}
+
+// Check returns the result of every check, running them if the cached results have expired,
+// and whether the server is ready.
+func (r *Readiness) Check(ctx context.Context) (map[string]error, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.draining {
+		return nil, false
+	}
+	if r.results == nil || !r.now().Before(r.checkedAt.Add(r.ttl)) {
+		r.results = r.run(ctx)
+		r.checkedAt = r.now()
+	}
+	for _, err := range r.results {
+		if err != nil {
+			return r.results, false
+		}
+	}
+	return r.results, true
+}
+
+// run runs all checks concurrently.
+func (r *Readiness) run(ctx context.Context) map[string]error {
+	ctx, cancel := context.WithTimeout(r.contextFunc(ctx), checkTimeout)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]error, len(r.checks))
+	)
+	for name, check := range r.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := check(ctx)
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// readinessResponse is the body of the readiness endpoint.
+type readinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Handler returns a handler that responds with 200 OK when the server is ready and 503 Service Unavailable
+// otherwise, listing the outcome of each check.
+func (r *Readiness) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		results, ready := r.Check(req.Context())
+
+		resp := readinessResponse{Status: "ready", Checks: make(map[string]string, len(results))}
+		names := make([]string, 0, len(results))
+		for name := range results {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := results[name]; err != nil {
+				resp.Checks[name] = err.Error()
+			} else {
+				resp.Checks[name] = "ok"
+			}
+		}
+
+		status := http.StatusOK
+		if !ready {
+			resp.Status = "not ready"
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, resp)
+	})
+}
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func TestNew_RedactsSensitiveAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, FormatJSON, slog.LevelInfo)
+
+	tx := types.NewTransaction(types.Date{}, "Jita 4-4 Trade Hub", 1_500_000)
+	logger.Info("processed",
+		"payee", "Jita 4-4 Trade Hub",
+		"total", types.Money(42_0000),
+		"transaction", tx,
+		"lunchmoney_token", "hunter2",
+		slog.Group("kubera", "api_secret", "s3cr3t"),
+		"count", 3,
+	)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	for _, leaked := range []string{"Jita", "hunter2", "s3cr3t", "420000", "1500000"} {
+		if strings.Contains(buf.String(), leaked) {
+			t.Errorf("log record leaks %q: %s", leaked, buf.String())
+		}
+	}
+	for _, key := range []string{"payee", "total", "transaction", "lunchmoney_token"} {
+		if record[key] != Redacted {
+			t.Errorf("%s = %v; want %s", key, record[key], Redacted)
+		}
+	}
+	if record["count"] != float64(3) {
+		t.Errorf("count = %v; want 3", record["count"])
+	}
+	if record["msg"] != "processed" {
+		t.Errorf("msg = %v; want processed", record["msg"])
+	}
+}
+
+func TestNew_Level(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelWarn)
+	logger := New(&buf, FormatText, level)
+
+	logger.Info("hidden")
+	if buf.Len() != 0 {
+		t.Errorf("info record written at warn level: %s", buf.String())
+	}
+	level.Set(slog.LevelDebug)
+	logger.Debug("shown")
+	if !strings.Contains(buf.String(), "shown") {
+		t.Errorf("debug record not written after level change: %q", buf.String())
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() {
+		t.Error("FromContext without logger should return the default logger")
+	}
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	if FromContext(WithLogger(context.Background(), logger)) != logger {
+		t.Error("FromContext should return the injected logger")
+	}
+}
+
+func TestParse(t *testing.T) {
+	if level, err := ParseLevel("warn"); err != nil || level != slog.LevelWarn {
+		t.Errorf("ParseLevel(warn) = %v, %v", level, err)
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("ParseLevel(verbose) expected error")
+	}
+	if format, err := ParseFormat("JSON"); err != nil || format != FormatJSON {
+		t.Errorf("ParseFormat(JSON) = %v, %v", format, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) expected error")
+	}
+}
+
+func TestHTTPContextFunc(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, FormatJSON, slog.LevelInfo)
+
+	req, _ := http.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Mcp-Session-Id", "session-1")
+	ctx := HTTPContextFunc(logger)(context.Background(), req)
+	FromContext(ctx).Info("hello")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if record["session_id"] != "session-1" {
+		t.Errorf("session_id = %v; want session-1", record["session_id"])
+	}
+	if id, _ := record["request_id"].(string); len(id) != 16 {
+		t.Errorf("request_id = %v; want 16 hex digits", record["request_id"])
+	}
+}
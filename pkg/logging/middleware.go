@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sessionIDHeader is the HTTP header that carries the MCP session ID in the streamable HTTP transport.
+const sessionIDHeader = "Mcp-Session-Id"
+
+// NewRequestID returns a random identifier for correlating the log records of a single request.
+func NewRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// HTTPContextFunc returns an HTTP context function that attaches logger to the context of every request,
+// annotated with a new request ID and the MCP session ID of the request, if any.
+func HTTPContextFunc(logger *slog.Logger) server.HTTPContextFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		l := logger.With("request_id", NewRequestID())
+		if r != nil {
+			if sessionID := r.Header.Get(sessionIDHeader); sessionID != "" {
+				l = l.With("session_id", sessionID)
+			}
+		}
+		return WithLogger(ctx, l)
+	}
+}
+
+// ToolMiddleware returns tool handler middleware that annotates the logger in the context with the name
+// of the called tool, and logs the outcome and duration of every call.
+func ToolMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			logger := FromContext(ctx).With("tool", req.Params.Name)
+			ctx = WithLogger(ctx, logger)
+
+			start := time.Now()
+			result, err := next(ctx, req)
+			elapsed := time.Since(start)
+			switch {
+			case err != nil:
+				logger.ErrorContext(ctx, "Tool call failed", "duration", elapsed, "error", err)
+			case result != nil && result.IsError:
+				logger.WarnContext(ctx, "Tool call returned an error", "duration", elapsed)
+			default:
+				logger.InfoContext(ctx, "Tool call completed", "duration", elapsed)
+			}
+			return result, err
+		}
+	}
+}
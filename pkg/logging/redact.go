@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// Redacted replaces the values of redacted attributes.
+const Redacted = "[REDACTED]"
+
+// redactedKeys are attribute keys whose values are always redacted.
+var redactedKeys = map[string]bool{
+	"amount":        true,
+	"balance":       true,
+	"value":         true,
+	"net_worth":     true,
+	"payee":         true,
+	"description":   true,
+	"notes":         true,
+	"authorization": true,
+}
+
+// redactedKeyParts are substrings of attribute keys, such as "api_secret", whose values are always redacted.
+var redactedKeyParts = []string{"token", "secret", "password", "api_key", "apikey"}
+
+// redact is a slog.HandlerOptions.ReplaceAttr function that redacts attributes which may hold amounts, payees
+// or credentials, either by their key or by the type of their value.
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindGroup {
+		return a
+	}
+	if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey || a.Key == slog.SourceKey) {
+		return a
+	}
+	if isRedactedKey(a.Key) || isRedactedValue(a.Value) {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
+
+// isRedactedKey reports whether values of attributes with the given key are redacted.
+func isRedactedKey(key string) bool {
+	key = strings.ToLower(key)
+	if redactedKeys[key] {
+		return true
+	}
+	for _, part := range redactedKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRedactedValue reports whether v holds financial data that must never be logged, regardless of its key.
+func isRedactedValue(v slog.Value) bool {
+	if v.Kind() != slog.KindAny {
+		return false
+	}
+	switch v.Any().(type) {
+	case types.Money,
+		types.Transaction, *types.Transaction, []*types.Transaction,
+		types.Category, *types.Category, types.Categories, *types.Categories,
+		types.Portfolio, *types.Portfolio,
+		types.AssetPosition, *types.AssetPosition, types.DebtPosition, *types.DebtPosition:
+		return true
+	default:
+		return false
+	}
+}
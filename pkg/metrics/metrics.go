@@ -0,0 +1,184 @@
+// Package metrics exports Prometheus metrics about tool calls, upstream API requests, caching and the
+// size of responses. Instrumentation is applied by wrapping tool handlers, HTTP client transports and
+// data source functions.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+const namespace = "personal_finance_mcp"
+
+// Outcomes of tool calls.
+const (
+	outcomeSuccess   = "success"
+	outcomeToolError = "tool_error"
+	outcomeError     = "error"
+)
+
+// Metrics holds the collectors exported by the server.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	toolCalls        *prometheus.CounterVec
+	toolDuration     *prometheus.HistogramVec
+	upstreamRequests *prometheus.CounterVec
+	upstreamDuration *prometheus.HistogramVec
+	cacheLookups     *prometheus.CounterVec
+	transactions     *prometheus.HistogramVec
+}
+
+// New creates the collectors and registers them, along with Go runtime and process collectors, in a new registry.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tool_calls_total",
+			Help:      "Number of tool calls by tool and outcome (success, tool_error or error).",
+		}, []string{"tool", "outcome"}),
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "tool_call_duration_seconds",
+			Help:      "Latency of tool calls by tool.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"tool"}),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upstream_requests_total",
+			Help:      "Number of requests to upstream APIs by client and HTTP status code, or error if no response was received.",
+		}, []string{"client", "status"}),
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upstream_request_duration_seconds",
+			Help:      "Latency of requests to upstream APIs by client.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"client"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Number of data source cache lookups by cache and result (hit or miss).",
+		}, []string{"cache", "result"}),
+		transactions: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "transactions_per_response",
+			Help:      "Number of transactions returned by the data source per tool call, by tool.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+		}, []string{"tool"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.toolCalls,
+		m.toolDuration,
+		m.upstreamRequests,
+		m.upstreamDuration,
+		m.cacheLookups,
+		m.transactions,
+	)
+	return m
+}
+
+// Handler returns the HTTP handler that serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+type toolKeyType struct{}
+
+var toolKey = toolKeyType{}
+
+// toolFromContext returns the name of the tool being called, or "none" outside of tool calls.
+func toolFromContext(ctx context.Context) string {
+	if tool, ok := ctx.Value(toolKey).(string); ok {
+		return tool
+	}
+	return "none"
+}
+
+// ToolMiddleware returns tool handler middleware that counts tool calls by outcome and measures their latency.
+func (m *Metrics) ToolMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			tool := req.Params.Name
+			ctx = context.WithValue(ctx, toolKey, tool)
+
+			start := time.Now()
+			result, err := next(ctx, req)
+			m.toolDuration.WithLabelValues(tool).Observe(time.Since(start).Seconds())
+
+			outcome := outcomeSuccess
+			switch {
+			case err != nil:
+				outcome = outcomeError
+			case result != nil && result.IsError:
+				outcome = outcomeToolError
+			}
+			m.toolCalls.WithLabelValues(tool, outcome).Inc()
+			return result, err
+		}
+	}
+}
+
+// Transport wraps next, or http.DefaultTransport if nil, so that requests made through it are counted by status
+// code and timed under the given client name.
+func (m *Metrics) Transport(client string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		m.upstreamDuration.WithLabelValues(client).Observe(time.Since(start).Seconds())
+
+		status := "error"
+		if err == nil {
+			status = strconv.Itoa(resp.StatusCode)
+		}
+		m.upstreamRequests.WithLabelValues(client, status).Inc()
+		return resp, err
+	})
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// CacheObserver returns a cache observer that counts hits and misses of the named cache.
+func (m *Metrics) CacheObserver(cache string) ds.CacheObserver {
+	hits := m.cacheLookups.WithLabelValues(cache, "hit")
+	misses := m.cacheLookups.WithLabelValues(cache, "miss")
+	return func(hit bool) {
+		if hit {
+			hits.Inc()
+		} else {
+			misses.Inc()
+		}
+	}
+}
+
+// CountTransactions wraps a data source so that the number of transactions in each result is recorded
+// under the name of the tool that requested it.
+func (m *Metrics) CountTransactions(fn ds.GetCategorizedTransactionsFunc) ds.GetCategorizedTransactionsFunc {
+	return func(ctx context.Context, interval ds.DateRange) (*types.Categories, error) {
+		cats, err := fn(ctx, interval)
+		if err == nil {
+			m.transactions.WithLabelValues(toolFromContext(ctx)).Observe(float64(cats.TransactionCount()))
+		}
+		return cats, err
+	}
+}
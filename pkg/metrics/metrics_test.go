@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func TestToolMiddleware(t *testing.T) {
+	m := New()
+	handler := m.ToolMiddleware()(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		switch req.Params.Arguments {
+		case "fail":
+			return nil, errors.New("boom")
+		case "tool_error":
+			return mcp.NewToolResultError("bad input"), nil
+		default:
+			return mcp.NewToolResultText("ok"), nil
+		}
+	})
+
+	call := func(args any) {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "get_net_worth_summary"
+		req.Params.Arguments = args
+		_, _ = handler(context.Background(), req)
+	}
+	call(nil)
+	call(nil)
+	call("tool_error")
+	call("fail")
+
+	for outcome, want := range map[string]float64{outcomeSuccess: 2, outcomeToolError: 1, outcomeError: 1} {
+		if got := testutil.ToFloat64(m.toolCalls.WithLabelValues("get_net_worth_summary", outcome)); got != want {
+			t.Errorf("tool_calls_total{outcome=%q} = %v; want %v", outcome, got, want)
+		}
+	}
+	if got := testutil.CollectAndCount(m.toolDuration); got != 1 {
+		t.Errorf("tool_call_duration_seconds series = %d; want 1", got)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	m := New()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer upstream.Close()
+
+	client := &http.Client{Transport: m.Transport("lunch_money", nil)}
+	for _, path := range []string{"/ok", "/ok", "/limited"} {
+		resp, err := client.Get(upstream.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	upstream.Close()
+	if _, err := client.Get(upstream.URL); err == nil {
+		t.Fatal("expected error from closed server")
+	}
+
+	for status, want := range map[string]float64{"200": 2, "429": 1, "error": 1} {
+		if got := testutil.ToFloat64(m.upstreamRequests.WithLabelValues("lunch_money", status)); got != want {
+			t.Errorf("upstream_requests_total{status=%q} = %v; want %v", status, got, want)
+		}
+	}
+}
+
+func TestCacheObserverAndTransactionCounts(t *testing.T) {
+	m := New()
+	fn := ds.CacheTransactions(
+		m.CountTransactions(func(ctx context.Context, _ ds.DateRange) (*types.Categories, error) {
+			cats := types.NewCategories()
+			_ = cats.Expenses.AddTransaction(types.NewTransaction(types.Date{}, "Payee", -100))
+			_ = cats.Income.AddTransaction(types.NewTransaction(types.Date{}, "Employer", 1000))
+			return cats, nil
+		}),
+		time.Minute,
+		m.CacheObserver("transactions"),
+	)
+
+	ctx := context.WithValue(context.Background(), toolKey, "get_categorized_summaries")
+	_, _ = fn(ctx, ds.DateRange{})
+	_, _ = fn(ctx, ds.DateRange{})
+
+	if got := testutil.ToFloat64(m.cacheLookups.WithLabelValues("transactions", "hit")); got != 1 {
+		t.Errorf("cache hits = %v; want 1", got)
+	}
+	if got := testutil.ToFloat64(m.cacheLookups.WithLabelValues("transactions", "miss")); got != 1 {
+		t.Errorf("cache misses = %v; want 1", got)
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	want := `personal_finance_mcp_transactions_per_response_sum{tool="get_categorized_summaries"} 2`
+	if !strings.Contains(body, want) {
+		t.Errorf("metrics output lacks %q:\n%s", want, body)
+	}
+}
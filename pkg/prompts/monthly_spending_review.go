@@ -0,0 +1,70 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// MonthlySpendingReviewData is the data available to the monthly_spending_review template.
+type MonthlySpendingReviewData struct {
+	MonthName     string
+	StartDate     string
+	EndDate       string
+	PrevStartDate string
+	PrevEndDate   string
+	FocusCategory string
+}
+
+func MonthlySpendingReviewPrompt(t *Templates) server.ServerPrompt {
+	const name = "monthly_spending_review"
+	const description = "Review spending for a month, compare it to the previous month and dig into notable categories."
+	return server.ServerPrompt{
+		Prompt: mcp.NewPrompt(name,
+			mcp.WithPromptDescription(description),
+			mcp.WithArgument("month",
+				mcp.ArgumentDescription("Month to review, formatted like YYYY-MM. Defaults to the previous calendar month"),
+			),
+			mcp.WithArgument("focus_category",
+				mcp.ArgumentDescription("Name of a category to review transaction by transaction"),
+			),
+		),
+		Handler: func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			month, err := parseMonth(req.Params.Arguments["month"], t.now())
+			if err != nil {
+				return nil, err
+			}
+			start, end := monthRange(month)
+			prevStart, prevEnd := monthRange(start.AddDate(0, -1, 0))
+
+			text, err := t.render(name, MonthlySpendingReviewData{
+				MonthName:     month.Format("January 2006"),
+				StartDate:     formatDate(start),
+				EndDate:       formatDate(end),
+				PrevStartDate: formatDate(prevStart),
+				PrevEndDate:   formatDate(prevEnd),
+				FocusCategory: req.Params.Arguments["focus_category"],
+			})
+			if err != nil {
+				return nil, err
+			}
+			return userPrompt(description, text), nil
+		},
+	}
+}
+
+// parseMonth parses a month formatted like YYYY-MM. An empty value yields the calendar month preceding now.
+func parseMonth(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		start, _ := monthRange(now)
+		return start.AddDate(0, -1, 0), nil
+	}
+	month, err := time.ParseInLocation("2006-01", value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q, expected YYYY-MM: %w", value, err)
+	}
+	return month, nil
+}
@@ -0,0 +1,37 @@
+package prompts
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// NetWorthCheckInData is the data available to the net_worth_check_in template.
+type NetWorthCheckInData struct {
+	Today string
+	Focus string
+}
+
+func NetWorthCheckInPrompt(t *Templates) server.ServerPrompt {
+	const name = "net_worth_check_in"
+	const description = "Summarize net worth, asset allocation and debts, and assess overall financial health."
+	return server.ServerPrompt{
+		Prompt: mcp.NewPrompt(name,
+			mcp.WithPromptDescription(description),
+			mcp.WithArgument("focus",
+				mcp.ArgumentDescription("Aspect to pay particular attention to, e.g. liquidity or debt payoff"),
+			),
+		),
+		Handler: func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			text, err := t.render(name, NetWorthCheckInData{
+				Today: formatDate(t.now()),
+				Focus: req.Params.Arguments["focus"],
+			})
+			if err != nil {
+				return nil, err
+			}
+			return userPrompt(description, text), nil
+		},
+	}
+}
@@ -0,0 +1,93 @@
+// Package prompts provides MCP prompts that expand into multi-step instructions for common financial reviews.
+// Each prompt is rendered from a text/template; the built-in templates can be overridden by placing a file
+// named <prompt name>.tmpl into a user-supplied directory.
+package prompts
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// Templates resolves prompt templates by name, preferring user-supplied overrides over the built-in defaults.
+type Templates struct {
+	// overrideDir is the directory that is searched for overrides; empty disables overrides.
+	overrideDir string
+	// now returns the current time; relative dates in prompts are computed against it.
+	now func() time.Time
+}
+
+// NewTemplates creates Templates that look for overrides in overrideDir and compute relative dates in loc.
+// Overrides are read on every use, so edits take effect without restarting the server.
+func NewTemplates(overrideDir string, loc *time.Location) *Templates {
+	return &Templates{
+		overrideDir: overrideDir,
+		now:         func() time.Time { return time.Now().In(loc) },
+	}
+}
+
+// render executes the template of the named prompt against data.
+func (t *Templates) render(name string, data any) (string, error) {
+	text, err := t.load(name)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template for prompt %s: %w", name, err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt %s: %w", name, err)
+	}
+	return sb.String(), nil
+}
+
+// load returns the template text of the named prompt from the override directory, if present,
+// or from the built-in templates otherwise.
+func (t *Templates) load(name string) (string, error) {
+	filename := name + ".tmpl"
+	if t.overrideDir != "" {
+		data, err := os.ReadFile(filepath.Join(t.overrideDir, filename))
+		switch {
+		case err == nil:
+			return string(data), nil
+		case !errors.Is(err, fs.ErrNotExist):
+			return "", fmt.Errorf("failed to read template override for prompt %s: %w", name, err)
+		}
+	}
+	data, err := builtinTemplates.ReadFile("templates/" + filename)
+	if err != nil {
+		return "", fmt.Errorf("no template for prompt %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// userPrompt wraps rendered instructions into a prompt result with a single user message.
+func userPrompt(description, text string) *mcp.GetPromptResult {
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	})
+}
+
+// monthRange returns the first and last day of the month containing t.
+func monthRange(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 1, -1)
+}
+
+// formatDate formats t as YYYY-MM-DD, the format expected by the tools.
+func formatDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
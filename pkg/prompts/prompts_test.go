@@ -0,0 +1,111 @@
+package prompts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newTestTemplates returns Templates with a fixed clock.
+func newTestTemplates(overrideDir string) *Templates {
+	t := NewTemplates(overrideDir, time.UTC)
+	t.now = func() time.Time { return time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC) }
+	return t
+}
+
+// getPrompt invokes the prompt handler with the supplied arguments and returns the text of the single message.
+func getPrompt(t *testing.T, p server.ServerPrompt, args map[string]string) string {
+	t.Helper()
+	var req mcp.GetPromptRequest
+	req.Params.Name = p.Prompt.Name
+	req.Params.Arguments = args
+	res, err := p.Handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("prompt %s returned error: %v", p.Prompt.Name, err)
+	}
+	if len(res.Messages) != 1 {
+		t.Fatalf("len(Messages) = %d; want 1", len(res.Messages))
+	}
+	text, ok := res.Messages[0].Content.(mcp.TextContent)
+	if !ok {
+		t.Fatalf("Content = %T; want mcp.TextContent", res.Messages[0].Content)
+	}
+	return text.Text
+}
+
+func TestMonthlySpendingReview_DefaultMonth(t *testing.T) {
+	text := getPrompt(t, MonthlySpendingReviewPrompt(newTestTemplates("")), nil)
+
+	for _, want := range []string{
+		"February 2024",
+		"start_date 2024-02-01 and end_date 2024-02-29",
+		"start_date 2024-01-01 and end_date 2024-01-31",
+		"`get_categorized_summaries`",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("prompt does not contain %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestMonthlySpendingReview_FocusCategory(t *testing.T) {
+	text := getPrompt(t, MonthlySpendingReviewPrompt(newTestTemplates("")), map[string]string{
+		"month":          "2023-12",
+		"focus_category": "Dining Out",
+	})
+	if !strings.Contains(text, `"Dining Out" category`) {
+		t.Errorf("prompt does not mention focus category:\n%s", text)
+	}
+	if !strings.Contains(text, "start_date 2023-11-01 and end_date 2023-11-30") {
+		t.Errorf("prompt does not compare against previous month:\n%s", text)
+	}
+}
+
+func TestMonthlySpendingReview_InvalidMonth(t *testing.T) {
+	p := MonthlySpendingReviewPrompt(newTestTemplates(""))
+	var req mcp.GetPromptRequest
+	req.Params.Arguments = map[string]string{"month": "March"}
+	if _, err := p.Handler(context.Background(), req); err == nil {
+		t.Error("expected error for invalid month, got nil")
+	}
+}
+
+func TestSubscriptionAudit_Months(t *testing.T) {
+	text := getPrompt(t, SubscriptionAuditPrompt(newTestTemplates("")), map[string]string{"months": "12"})
+	if !strings.Contains(text, "start_date 2023-03-15 and end_date 2024-03-15") {
+		t.Errorf("prompt does not cover 12 months:\n%s", text)
+	}
+}
+
+func TestNetWorthCheckIn_Focus(t *testing.T) {
+	text := getPrompt(t, NetWorthCheckInPrompt(newTestTemplates("")), map[string]string{"focus": "liquidity"})
+	if !strings.Contains(text, "as of 2024-03-15") || !strings.Contains(text, "liquidity") {
+		t.Errorf("unexpected prompt:\n%s", text)
+	}
+}
+
+func TestTemplates_Override(t *testing.T) {
+	dir := t.TempDir()
+	override := "Check-in on {{ .Today }}, focusing on {{ .Focus }}."
+	if err := os.WriteFile(filepath.Join(dir, "net_worth_check_in.tmpl"), []byte(override), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	templates := newTestTemplates(dir)
+
+	text := getPrompt(t, NetWorthCheckInPrompt(templates), map[string]string{"focus": "debt"})
+	if text != "Check-in on 2024-03-15, focusing on debt." {
+		t.Errorf("text = %q; want rendered override", text)
+	}
+
+	// Prompts without an override fall back to the built-in template
+	text = getPrompt(t, SubscriptionAuditPrompt(templates), nil)
+	if !strings.Contains(text, "last 6 months") {
+		t.Errorf("text = %q; want built-in template", text)
+	}
+}
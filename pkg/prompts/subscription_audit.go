@@ -0,0 +1,51 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SubscriptionAuditData is the data available to the subscription_audit template.
+type SubscriptionAuditData struct {
+	Months    int
+	StartDate string
+	EndDate   string
+}
+
+func SubscriptionAuditPrompt(t *Templates) server.ServerPrompt {
+	const name = "subscription_audit"
+	const description = "Find recurring charges and subscriptions, estimate their annual cost and suggest what to cancel."
+	return server.ServerPrompt{
+		Prompt: mcp.NewPrompt(name,
+			mcp.WithPromptDescription(description),
+			mcp.WithArgument("months",
+				mcp.ArgumentDescription("Number of months to look back. Defaults to 6"),
+			),
+		),
+		Handler: func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			months := 6
+			if val := req.Params.Arguments["months"]; val != "" {
+				n, err := strconv.Atoi(val)
+				if err != nil || n <= 0 {
+					return nil, fmt.Errorf("invalid months %q, expected a positive integer", val)
+				}
+				months = n
+			}
+			now := t.now()
+
+			text, err := t.render(name, SubscriptionAuditData{
+				Months:    months,
+				StartDate: formatDate(now.AddDate(0, -months, 0)),
+				EndDate:   formatDate(now),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return userPrompt(description, text), nil
+		},
+	}
+}
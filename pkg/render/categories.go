@@ -0,0 +1,128 @@
+package render
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// maxTextSubcategories caps the number of subcategories listed per root category in the compact text.
+const maxTextSubcategories = 8
+
+// Categories writes cats in the given format. Markdown lists the category totals followed by the transactions,
+// if there are any; CSV holds a single table, the transactions if there are any and the category totals otherwise.
+func Categories(w io.Writer, cats *types.Categories, format Format) error {
+	switch format {
+	case JSON:
+		return json.NewEncoder(w).Encode(cats)
+	case Markdown:
+		if err := TotalsTable(cats).Write(w, Markdown); err != nil {
+			return err
+		}
+		if cats.TransactionCount() == 0 {
+			return nil
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+		return TransactionsTable(cats).Write(w, Markdown)
+	default:
+		return CategoriesTable(cats).Write(w, format)
+	}
+}
+
+// CategoriesTable returns the transactions of cats as a table if there are any, and the category totals otherwise.
+func CategoriesTable(cats *types.Categories) *Table {
+	if cats.TransactionCount() > 0 {
+		return TransactionsTable(cats)
+	}
+	return TotalsTable(cats)
+}
+
+// TotalsTable returns the total of every category, identified by its path, in depth-first order.
+func TotalsTable(cats *types.Categories) *Table {
+	t := &Table{Header: []string{"Category", "Total"}}
+	walkCategories(cats, func(path string, cat *types.Category) {
+		t.Rows = append(t.Rows, []string{path, Money(cat.TotalAmount)})
+	})
+	return t
+}
+
+// TransactionsTable returns every transaction along with the path of its category.
+func TransactionsTable(cats *types.Categories) *Table {
+	t := &Table{Header: []string{"Date", "Category", "Payee", "Amount", "Description"}}
+	walkCategories(cats, func(path string, cat *types.Category) {
+		for _, txn := range cat.Transactions {
+			t.Rows = append(t.Rows, []string{txn.Date.String(), path, txn.Payee, Money(txn.Amount), txn.Description})
+		}
+	})
+	return t
+}
+
+// CategoriesText summarizes cats in one line per root category: its total, the number of transactions, and the
+// totals of its largest direct subcategories. A warning on the expense category, such as one about data sources
+// that failed, is added as the last line.
+func CategoriesText(cats *types.Categories) string {
+	var lines []string
+	for _, root := range []*types.Category{cats.Income, cats.Expenses, cats.Ignored} {
+		if root == nil || (root.TotalAmount == 0 && len(root.Subcategories) == 0 && len(root.Transactions) == 0) {
+			continue
+		}
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%s: %s", root.Name, Money(root.TotalAmount))
+		if count := root.TransactionCount(); count > 0 {
+			fmt.Fprintf(&sb, " across %d transactions", count)
+		}
+		subs := slices.SortedStableFunc(slices.Values(root.Subcategories), func(a, b *types.Category) int {
+			return cmp.Compare(abs(b.TotalAmount), abs(a.TotalAmount))
+		})
+		if len(subs) > 0 {
+			var parts []string
+			for _, sub := range subs[:min(len(subs), maxTextSubcategories)] {
+				parts = append(parts, sub.Name+" "+Money(sub.TotalAmount))
+			}
+			if more := len(subs) - maxTextSubcategories; more > 0 {
+				parts = append(parts, fmt.Sprintf("and %d more", more))
+			}
+			fmt.Fprintf(&sb, " (%s)", strings.Join(parts, ", "))
+		}
+		lines = append(lines, sb.String())
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No transactions.")
+	}
+	if cats.Expenses != nil && cats.Expenses.Annotations["warning"] != "" {
+		lines = append(lines, "Warning: "+cats.Expenses.Annotations["warning"])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// walkCategories calls fn for every category in depth-first order, with the category names along its path
+// joined by slashes like in payee rules.
+func walkCategories(cats *types.Categories, fn func(path string, cat *types.Category)) {
+	var walk func(prefix string, cat *types.Category)
+	walk = func(prefix string, cat *types.Category) {
+		path := prefix + cat.Name
+		fn(path, cat)
+		for _, sub := range cat.Subcategories {
+			walk(path+"/", sub)
+		}
+	}
+	for _, root := range []*types.Category{cats.Income, cats.Expenses, cats.Ignored} {
+		if root != nil {
+			walk("", root)
+		}
+	}
+}
+
+func abs(m types.Money) types.Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
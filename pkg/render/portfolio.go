@@ -0,0 +1,48 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// Portfolio writes p in the given format. Markdown precedes the positions with the portfolio totals.
+func Portfolio(w io.Writer, p *types.Portfolio, format Format) error {
+	switch format {
+	case JSON:
+		return json.NewEncoder(w).Encode(p)
+	case Markdown:
+		if _, err := fmt.Fprintf(w, "%s\n\n", PortfolioText(p)); err != nil {
+			return err
+		}
+	}
+	return PortfolioTable(p).Write(w, format)
+}
+
+// PortfolioTable returns every asset and debt position of p.
+func PortfolioTable(p *types.Portfolio) *Table {
+	t := &Table{Header: []string{"Kind", "Name", "Type", "Ticker", "Value"}}
+	for _, asset := range p.Assets {
+		t.Rows = append(t.Rows, []string{"asset", asset.Name, asset.Type, asset.Ticker, Money(asset.Value)})
+	}
+	for _, debt := range p.Debts {
+		t.Rows = append(t.Rows, []string{"debt", debt.Name, debt.Type, "", Money(debt.Value)})
+	}
+	return t
+}
+
+// PortfolioText summarizes p in a single line, followed by the portfolios combined into p and the warning of p
+// if it has them.
+func PortfolioText(p *types.Portfolio) string {
+	text := fmt.Sprintf("Net worth: %s, with %d assets totaling %s and %d debts totaling %s",
+		Money(p.NetWorth), len(p.Assets), Money(p.TotalAssets), len(p.Debts), Money(p.TotalDebts))
+	if portfolios := p.Annotations["portfolios"]; portfolios != "" {
+		text += "\nCombined portfolios: " + portfolios
+	}
+	if warning := p.Annotations["warning"]; warning != "" {
+		text += "\nWarning: " + warning
+	}
+	return text
+}
@@ -0,0 +1,99 @@
+// Package render renders tool results as compact text, Markdown or CSV, which take far fewer tokens than the
+// equivalent JSON and are readable by clients that do not support structured content.
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// Format is the format in which a tool result is rendered.
+type Format string
+
+const (
+	// JSON leaves the result as structured content.
+	JSON Format = "json"
+	// Markdown renders the result as Markdown tables.
+	Markdown Format = "markdown"
+	// CSV renders the result as comma-separated values with a header row.
+	CSV Format = "csv"
+)
+
+// Formats lists all supported formats.
+var Formats = []string{string(JSON), string(Markdown), string(CSV)}
+
+// ParseFormat parses the name of a format. An empty name yields JSON.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "":
+		return JSON, nil
+	case JSON, Markdown, CSV:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown format %q, expected one of %s", s, strings.Join(Formats, ", "))
+	}
+}
+
+// Table is tabular data that can be written as Markdown or CSV.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Write writes the table in the given format, which must be Markdown or CSV.
+func (t *Table) Write(w io.Writer, format Format) error {
+	switch format {
+	case Markdown:
+		return t.writeMarkdown(w)
+	case CSV:
+		return t.writeCSV(w)
+	default:
+		return fmt.Errorf("tables cannot be rendered as %s", format)
+	}
+}
+
+func (t *Table) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write(t.Header)
+	_ = cw.WriteAll(t.Rows)
+	return cw.Error()
+}
+
+func (t *Table) writeMarkdown(w io.Writer) error {
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		sb.WriteString("|")
+		for _, cell := range cells {
+			sb.WriteString(" ")
+			sb.WriteString(escapeMarkdown(cell))
+			sb.WriteString(" |")
+		}
+		sb.WriteString("\n")
+	}
+	writeRow(t.Header)
+	sb.WriteString("|")
+	sb.WriteString(strings.Repeat(" --- |", len(t.Header)))
+	sb.WriteString("\n")
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// markdownEscaper keeps cell contents from breaking the table structure.
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// Money formats m with two decimal places.
+func Money(m types.Money) string {
+	return strconv.FormatFloat(float64(m)/types.FACTOR, 'f', 2, 64)
+}
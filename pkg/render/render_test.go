@@ -0,0 +1,121 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func testCategories(t *testing.T) *types.Categories {
+	t.Helper()
+	date, err := types.ParseDate("2024-03-14")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cats := types.NewCategories()
+	_ = cats.Income.GetOrCreatePath("Bounties").AddTransaction(types.NewTransaction(date, "CONCORD", 5000000))
+	_ = cats.Expenses.GetOrCreatePath("Ships", "Frigates").AddTransaction(types.NewTransaction(date, "Jita 4-4", 1250000))
+	txn := types.NewTransaction(date, "Amarr | Emperor", 100000)
+	txn.Description = "tithe\nmonthly"
+	_ = cats.Expenses.GetOrCreatePath("Taxes").AddTransaction(txn)
+	return cats
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"": JSON, "json": JSON, "Markdown": Markdown, "CSV": CSV} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) succeeded; want error")
+	}
+}
+
+func TestCategories_CSV(t *testing.T) {
+	cats := testCategories(t)
+	var sb strings.Builder
+	if err := Categories(&sb, cats, CSV); err != nil {
+		t.Fatal(err)
+	}
+	want := "Date,Category,Payee,Amount,Description\n" +
+		"2024-03-14,Income/Bounties,CONCORD,500.00,\n" +
+		"2024-03-14,Expenses/Ships/Frigates,Jita 4-4,125.00,\n" +
+		"2024-03-14,Expenses/Taxes,Amarr | Emperor,10.00,\"tithe\nmonthly\"\n"
+	if sb.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
+
+func TestCategories_CSVWithoutTransactions(t *testing.T) {
+	cats := testCategories(t)
+	walkCategories(cats, func(_ string, cat *types.Category) { cat.Transactions = nil })
+
+	var sb strings.Builder
+	if err := Categories(&sb, cats, CSV); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sb.String(), "Category,Total\nIncome,500.00\nIncome/Bounties,500.00\nExpenses,135.00\n") {
+		t.Errorf("CSV =\n%s\nwant category totals", sb.String())
+	}
+}
+
+func TestCategories_Markdown(t *testing.T) {
+	var sb strings.Builder
+	if err := Categories(&sb, testCategories(t), Markdown); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	for _, want := range []string{
+		"| Category | Total |\n| --- | --- |\n| Income | 500.00 |\n",
+		"| Date | Category | Payee | Amount | Description |\n",
+		`| 2024-03-14 | Expenses/Taxes | Amarr \| Emperor | 10.00 | tithe monthly |`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Markdown does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCategoriesText(t *testing.T) {
+	got := CategoriesText(testCategories(t))
+	want := "Income: 500.00 across 1 transactions (Bounties 500.00)\n" +
+		"Expenses: 135.00 across 2 transactions (Ships 125.00, Taxes 10.00)"
+	if got != want {
+		t.Errorf("CategoriesText =\n%s\nwant\n%s", got, want)
+	}
+	if got := CategoriesText(types.NewCategories()); got != "No transactions." {
+		t.Errorf("CategoriesText(empty) = %q", got)
+	}
+	partial := types.NewCategories()
+	partial.Expenses.Annotate("warning", "ynab failed")
+	if got := CategoriesText(partial); got != "No transactions.\nWarning: ynab failed" {
+		t.Errorf("CategoriesText(partial) = %q", got)
+	}
+}
+
+func TestPortfolio(t *testing.T) {
+	p := types.NewPortfolio()
+	p.AddAsset(types.NewAssetPosition("Brokerage", "VTI", "stock", "equity", 10000000))
+	p.AddDebt(types.NewDebtPosition("Mortgage", "loan", 4000000))
+
+	if got, want := PortfolioText(p), "Net worth: 600.00, with 1 assets totaling 1000.00 and 1 debts totaling 400.00"; got != want {
+		t.Errorf("PortfolioText = %q; want %q", got, want)
+	}
+	p.Annotate("warning", "kubera failed")
+	if got := PortfolioText(p); !strings.HasSuffix(got, "400.00\nWarning: kubera failed") {
+		t.Errorf("PortfolioText = %q; want warning on the last line", got)
+	}
+	p.Annotate("portfolios", "Personal, Estate")
+	if got := PortfolioText(p); !strings.HasSuffix(got, "400.00\nCombined portfolios: Personal, Estate\nWarning: kubera failed") {
+		t.Errorf("PortfolioText = %q; want the combined portfolios before the warning", got)
+	}
+	var sb strings.Builder
+	if err := Portfolio(&sb, p, CSV); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Kind,Name,Type,Ticker,Value\nasset,Brokerage,stock,VTI,1000.00\ndebt,Mortgage,loan,,400.00\n"; sb.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
@@ -0,0 +1,31 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+)
+
+// CategoriesURI is the URI of the category hierarchy resource.
+const CategoriesURI = "finance://categories"
+
+func CategoriesResource(ds ds.ListCategoriesFunc) server.ServerResource {
+	return server.ServerResource{
+		Resource: mcp.NewResource(CategoriesURI, "categories",
+			mcp.WithResourceDescription(
+				"Hierarchy of categories that transactions are organized by, including their descriptions and "+
+					"whether they are income or excluded from budget and totals.",
+			),
+			mcp.WithMIMEType("application/json"),
+		),
+		Handler: func(ctx context.Context, _ mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			result, err := ds(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return jsonContents(CategoriesURI, result)
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+)
+
+// PortfolioURI is the URI of the portfolio resource.
+const PortfolioURI = "finance://portfolio"
+
+func PortfolioResource(ds ds.GetPortfolioFunc) server.ServerResource {
+	return server.ServerResource{
+		Resource: mcp.NewResource(PortfolioURI, "portfolio",
+			mcp.WithResourceDescription("User's current net worth, including all asset holdings, debts and their respective values."),
+			mcp.WithMIMEType("application/json"),
+		),
+		Handler: func(ctx context.Context, _ mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			result, err := ds(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return jsonContents(PortfolioURI, result)
+		},
+	}
+}
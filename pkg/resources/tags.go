@@ -0,0 +1,28 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+)
+
+// TagsURI is the URI of the transaction tags resource.
+const TagsURI = "finance://tags"
+
+func TagsResource(ds ds.ListTagsFunc) server.ServerResource {
+	return server.ServerResource{
+		Resource: mcp.NewResource(TagsURI, "tags",
+			mcp.WithResourceDescription("Tags that the user attaches to transactions, along with their descriptions."),
+			mcp.WithMIMEType("application/json"),
+		),
+		Handler: func(ctx context.Context, _ mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			result, err := ds(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return jsonContents(TagsURI, result)
+		},
+	}
+}
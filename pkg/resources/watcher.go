@@ -0,0 +1,109 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+)
+
+// Watcher periodically re-reads resources in the background and notifies connected clients
+// whenever the content of a resource changes. The server does not support subscriptions to individual
+// resources, so clients are told that the resource list changed, which prompts them to read it again.
+type Watcher struct {
+	resources []server.ServerResource
+	interval  time.Duration
+	// contextFunc prepares the background context before reading resources, e.g. by injecting API clients.
+	contextFunc func(ctx context.Context) context.Context
+	// notify is called once per refresh with the URIs of the resources whose content has changed.
+	notify func(uris []string)
+	// hashes tracks the content hash of each resource as of the last refresh.
+	hashes map[string][sha256.Size]byte
+}
+
+// NewWatcher creates a Watcher that re-reads the supplied resources every interval and sends
+// resource list change notifications to all clients connected to the MCP server.
+func NewWatcher(
+	mcpServer *server.MCPServer,
+	interval time.Duration,
+	contextFunc func(ctx context.Context) context.Context,
+	resources ...server.ServerResource,
+) *Watcher {
+	return &Watcher{
+		resources:   resources,
+		interval:    interval,
+		contextFunc: contextFunc,
+		notify: func([]string) {
+			mcpServer.SendNotificationToAllClients(mcp.MethodNotificationResourcesListChanged, nil)
+		},
+		hashes: make(map[string][sha256.Size]byte),
+	}
+}
+
+// Run refreshes the resources until ctx is cancelled. The first refresh only records the
+// initial content of each resource and does not result in any notifications.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.refresh(ctx)
+		}
+	}
+}
+
+// refresh reads every resource once and notifies clients once if any changed since the last refresh.
+// Resources that fail to read are skipped and keep their last known hash.
+func (w *Watcher) refresh(ctx context.Context) {
+	ctx = w.contextFunc(ctx)
+	var changed []string
+	for _, res := range w.resources {
+		uri := res.Resource.URI
+		hash, err := w.read(ctx, res)
+		if err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "Failed to refresh resource", "uri", uri, "error", err)
+			continue
+		}
+
+		prev, seen := w.hashes[uri]
+		w.hashes[uri] = hash
+		if seen && prev != hash {
+			changed = append(changed, uri)
+		}
+	}
+	if len(changed) > 0 {
+		logging.FromContext(ctx).DebugContext(ctx, "Resources changed", "uris", changed)
+		w.notify(changed)
+	}
+}
+
+// read reads the resource and returns the hash of its text contents.
+func (w *Watcher) read(ctx context.Context, res server.ServerResource) ([sha256.Size]byte, error) {
+	var req mcp.ReadResourceRequest
+	req.Params.URI = res.Resource.URI
+	contents, err := res.Handler(ctx, req)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	h := sha256.New()
+	for _, c := range contents {
+		switch c := c.(type) {
+		case mcp.TextResourceContents:
+			h.Write([]byte(c.Text))
+		case mcp.BlobResourceContents:
+			h.Write([]byte(c.Blob))
+		}
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// fakeTags is a tags data source whose result can be changed between refreshes.
+type fakeTags struct {
+	tags []*types.Tag
+	fail bool
+}
+
+func (f *fakeTags) ListTags(ctx context.Context) ([]*types.Tag, error) {
+	if f.fail {
+		return nil, errors.New("upstream down")
+	}
+	return f.tags, nil
+}
+
+// newTestWatcher returns a Watcher over the tags resource that records notifications instead of sending them.
+func newTestWatcher(src *fakeTags, notified *[]string) *Watcher {
+	return &Watcher{
+		resources:   []server.ServerResource{TagsResource(src.ListTags)},
+		contextFunc: func(ctx context.Context) context.Context { return ctx },
+		notify:      func(uris []string) { *notified = append(*notified, uris...) },
+		hashes:      make(map[string][sha256.Size]byte),
+	}
+}
+
+func TestWatcher_NotifiesOnChange(t *testing.T) {
+	src := &fakeTags{tags: []*types.Tag{types.NewTag("travel", "")}}
+	var notified []string
+	w := newTestWatcher(src, &notified)
+	ctx := context.Background()
+
+	w.refresh(ctx)
+	if len(notified) != 0 {
+		t.Fatalf("initial refresh notified %v; want none", notified)
+	}
+
+	w.refresh(ctx)
+	if len(notified) != 0 {
+		t.Fatalf("unchanged refresh notified %v; want none", notified)
+	}
+
+	src.tags = append(src.tags, types.NewTag("business", "reimbursable"))
+	w.refresh(ctx)
+	if !slices.Equal(notified, []string{TagsURI}) {
+		t.Errorf("notified = %v; want [%s]", notified, TagsURI)
+	}
+}
+
+func TestWatcher_SkipsFailedReads(t *testing.T) {
+	src := &fakeTags{tags: []*types.Tag{types.NewTag("travel", "")}}
+	var notified []string
+	w := newTestWatcher(src, &notified)
+	ctx := context.Background()
+
+	w.refresh(ctx)
+	src.fail = true
+	w.refresh(ctx)
+	src.fail = false
+	w.refresh(ctx)
+
+	if len(notified) != 0 {
+		t.Errorf("notified = %v; want none after transient failure", notified)
+	}
+}
@@ -0,0 +1,163 @@
+// Package rules implements user-configurable rules that categorize transactions by payee.
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/tracing"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PathSeparator separates the levels of a category path in Rule.Category.
+const PathSeparator = "/"
+
+// Rule assigns transactions whose payee matches Payee to the category identified by Category.
+type Rule struct {
+	// Payee is a regular expression matched case-insensitively against the transaction payee.
+	Payee string `yaml:"payee" toml:"payee"`
+	// Category is the path of the target category below the income or expense bucket,
+	// with levels separated by PathSeparator, e.g. "Food/Groceries".
+	Category string `yaml:"category" toml:"category"`
+}
+
+// compiledRule is a Rule with its payee pattern compiled and its category path split into levels.
+type compiledRule struct {
+	Rule
+	payee *regexp.Regexp
+	path  []string
+}
+
+// Rules is a compiled, ordered set of rules. The first matching rule wins.
+// A nil *Rules is valid and matches nothing.
+type Rules struct {
+	rules []compiledRule
+}
+
+// Compile validates and compiles the supplied rules, reporting every invalid rule.
+func Compile(rules []Rule) (*Rules, error) {
+	result := &Rules{rules: make([]compiledRule, 0, len(rules))}
+	var errs []error
+	for i, rule := range rules {
+		re, err := regexp.Compile("(?i)" + rule.Payee)
+		switch {
+		case rule.Payee == "":
+			errs = append(errs, fmt.Errorf("rules[%d]: payee must be set", i))
+		case err != nil:
+			errs = append(errs, fmt.Errorf("rules[%d]: invalid payee pattern: %w", i, err))
+		}
+		path := SplitPath(rule.Category)
+		if len(path) == 0 {
+			errs = append(errs, fmt.Errorf("rules[%d]: category must be set", i))
+		}
+		result.rules = append(result.rules, compiledRule{Rule: rule, payee: re, path: path})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return result, nil
+}
+
+// SplitPath splits a category path into its levels, ignoring surrounding whitespace and empty levels.
+func SplitPath(path string) []string {
+	var result []string
+	for _, name := range strings.Split(path, PathSeparator) {
+		if name = strings.TrimSpace(name); name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// Len returns the number of rules.
+func (r *Rules) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.rules)
+}
+
+// Match returns the category path of the first rule matching the payee.
+func (r *Rules) Match(payee string) ([]string, bool) {
+	if rule := r.match(payee); rule != nil {
+		return rule.path, true
+	}
+	return nil, false
+}
+
+// match returns the first rule matching the payee, or nil if none does.
+func (r *Rules) match(payee string) *compiledRule {
+	if r == nil {
+		return nil
+	}
+	for i := range r.rules {
+		if r.rules[i].payee.MatchString(payee) {
+			return &r.rules[i]
+		}
+	}
+	return nil
+}
+
+// Categorize adds the transaction to the category under bucket selected by the first matching rule,
+// or to the Uncategorized category under bucket if no rule matches.
+func (r *Rules) Categorize(bucket *types.Category, tx *types.Transaction) error {
+	rule := r.match(tx.Payee)
+	if rule == nil {
+		return bucket.GetOrCreatePath(types.UncategorizedName).AddTransaction(tx)
+	}
+	tx.Annotate("category_rule", rule.Payee)
+	return bucket.GetOrCreatePath(rule.path...).AddTransaction(tx)
+}
+
+// Recategorize moves transactions out of the Uncategorized income and expense categories into
+// the categories selected by matching rules. Uncategorized categories left empty are removed.
+func (r *Rules) Recategorize(cats *types.Categories) error {
+	for _, bucket := range []*types.Category{cats.Income, cats.Expenses} {
+		unc := bucket.FindSubcategory(types.UncategorizedName)
+		if unc == nil {
+			continue
+		}
+		for _, tx := range append([]*types.Transaction(nil), unc.Transactions...) {
+			if r.match(tx.Payee) == nil {
+				continue
+			}
+			if err := unc.RemoveTransaction(tx); err != nil {
+				return err
+			}
+			if err := r.Categorize(bucket, tx); err != nil {
+				return err
+			}
+		}
+		if len(unc.Transactions) == 0 && len(unc.Subcategories) == 0 {
+			if err := bucket.RemoveSubcategory(unc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Apply wraps a data source so that rules are applied to the transactions it leaves uncategorized.
+func (r *Rules) Apply(fn ds.GetCategorizedTransactionsFunc) ds.GetCategorizedTransactionsFunc {
+	if r.Len() == 0 {
+		return fn
+	}
+	return func(ctx context.Context, interval ds.DateRange) (*types.Categories, error) {
+		result, err := fn(ctx, interval)
+		if err != nil {
+			return nil, err
+		}
+		_, span := tracing.Start(ctx, "rules.recategorize", attribute.Int("rules", r.Len()))
+		err = r.Recategorize(result)
+		tracing.End(span, err)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
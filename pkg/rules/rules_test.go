@@ -0,0 +1,122 @@
+package rules
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func mustCompile(t *testing.T, rules ...Rule) *Rules {
+	t.Helper()
+	r, err := Compile(rules)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+	return r
+}
+
+func TestCompile_ReportsAllInvalidRules(t *testing.T) {
+	_, err := Compile([]Rule{
+		{Payee: "ok", Category: "Food"},
+		{Payee: "", Category: "Food"},
+		{Payee: "([", Category: "Food"},
+		{Payee: "ok", Category: " / "},
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{"rules[1]: payee must be set", "rules[2]: invalid payee pattern", "rules[3]: category must be set"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err.Error(), want)
+		}
+	}
+}
+
+func TestMatch_FirstRuleWinsCaseInsensitive(t *testing.T) {
+	r := mustCompile(t,
+		Rule{Payee: "^whole foods", Category: "Food / Groceries"},
+		Rule{Payee: "foods", Category: "Food"},
+	)
+	path, ok := r.Match("WHOLE FOODS #123")
+	if !ok || !slices.Equal(path, []string{"Food", "Groceries"}) {
+		t.Errorf("Match = %v, %v; want [Food Groceries], true", path, ok)
+	}
+	if _, ok := r.Match("Netflix"); ok {
+		t.Error("Match(Netflix) = true; want false")
+	}
+
+	var none *Rules
+	if _, ok := none.Match("anything"); ok {
+		t.Error("nil Rules matched; want no match")
+	}
+}
+
+func TestCategorize(t *testing.T) {
+	r := mustCompile(t, Rule{Payee: "netflix", Category: "Subscriptions/Streaming"})
+	bucket := types.NewCategory("Expenses")
+
+	matched := types.NewTransaction(types.Date{}, "Netflix.com", 1599)
+	other := types.NewTransaction(types.Date{}, "Corner Store", 500)
+	if err := r.Categorize(bucket, matched); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Categorize(bucket, other); err != nil {
+		t.Fatal(err)
+	}
+
+	if matched.Category.Name != "Streaming" || matched.Category.Parent.Name != "Subscriptions" {
+		t.Errorf("matched transaction in %q; want Subscriptions/Streaming", matched.Category.Name)
+	}
+	if matched.Annotations["category_rule"] != "netflix" {
+		t.Errorf("Annotations = %v; want category_rule", matched.Annotations)
+	}
+	if other.Category.Name != types.UncategorizedName {
+		t.Errorf("other transaction in %q; want Uncategorized", other.Category.Name)
+	}
+	if bucket.TotalAmount != 2099 {
+		t.Errorf("bucket.TotalAmount = %v; want 2099", bucket.TotalAmount)
+	}
+}
+
+func TestApply_RecategorizesUncategorized(t *testing.T) {
+	r := mustCompile(t,
+		Rule{Payee: "payroll", Category: "Salary"},
+		Rule{Payee: "shell", Category: "Transport/Fuel"},
+	)
+	source := func(ctx context.Context, _ ds.DateRange) (*types.Categories, error) {
+		cats := types.NewCategories()
+		_ = cats.Income.GetOrCreatePath(types.UncategorizedName).AddTransaction(types.NewTransaction(types.Date{}, "ACME PAYROLL", 100000))
+		unc := cats.Expenses.GetOrCreatePath(types.UncategorizedName)
+		_ = unc.AddTransaction(types.NewTransaction(types.Date{}, "Shell Oil", 4000))
+		_ = unc.AddTransaction(types.NewTransaction(types.Date{}, "Mystery", 1000))
+		return cats, nil
+	}
+
+	result, err := r.Apply(source)(context.Background(), ds.DateRange{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Income.FindSubcategory(types.UncategorizedName) != nil {
+		t.Error("empty Income/Uncategorized was not removed")
+	}
+	if salary := result.Income.FindSubcategory("Salary"); salary == nil || salary.TotalAmount != 100000 {
+		t.Errorf("Income/Salary = %+v; want total 100000", salary)
+	}
+	if result.Income.TotalAmount != 100000 {
+		t.Errorf("Income.TotalAmount = %v; want 100000", result.Income.TotalAmount)
+	}
+	if unc := result.Expenses.FindSubcategory(types.UncategorizedName); unc == nil || unc.TotalAmount != 1000 {
+		t.Errorf("Expenses/Uncategorized = %+v; want total 1000", unc)
+	}
+	if fuel := result.Expenses.GetOrCreatePath("Transport", "Fuel"); fuel.TotalAmount != 4000 {
+		t.Errorf("Expenses/Transport/Fuel total = %v; want 4000", fuel.TotalAmount)
+	}
+	if result.Expenses.TotalAmount != 5000 {
+		t.Errorf("Expenses.TotalAmount = %v; want 5000", result.Expenses.TotalAmount)
+	}
+}
@@ -0,0 +1,185 @@
+// Package secrets resolves credentials from secret references, so that they need not be passed as plain
+// environment variables that leak into process listings and container metadata. A reference is one of:
+//
+//   - file:///run/secrets/token reads the secret from a file, trimming surrounding whitespace
+//   - env:NAME reads the secret from the environment variable NAME
+//   - exec:command arg... runs the command and reads the secret from its standard output
+//
+// Any other value is used as the secret itself.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/wyvernzora/personal-finance-mcp/pkg/logging"
+)
+
+const (
+	filePrefix = "file://"
+	envPrefix  = "env:"
+	execPrefix = "exec:"
+)
+
+// execTimeout bounds how long the command of an exec reference may run.
+const execTimeout = 30 * time.Second
+
+// Resolve returns the secret that ref refers to.
+func Resolve(ctx context.Context, ref string) (string, error) {
+	var (
+		value string
+		err   error
+	)
+	switch {
+	case strings.HasPrefix(ref, filePrefix):
+		value, err = resolveFile(strings.TrimPrefix(ref, filePrefix))
+	case strings.HasPrefix(ref, envPrefix):
+		value, err = resolveEnv(strings.TrimPrefix(ref, envPrefix))
+	case strings.HasPrefix(ref, execPrefix):
+		value, err = resolveExec(ctx, strings.TrimPrefix(ref, execPrefix))
+	default:
+		return ref, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return "", fmt.Errorf("secret %s is empty", describe(ref))
+	}
+	return value, nil
+}
+
+// IsReference reports whether ref refers to a secret stored elsewhere rather than being the secret itself.
+func IsReference(ref string) bool {
+	return strings.HasPrefix(ref, filePrefix) || strings.HasPrefix(ref, envPrefix) || strings.HasPrefix(ref, execPrefix)
+}
+
+// resolveFile reads the secret from the file at path.
+func resolveFile(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("file secret reference has no path")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// resolveEnv reads the secret from the named environment variable.
+func resolveEnv(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("env secret reference has no variable name")
+	}
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", name)
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// resolveExec runs command, split on whitespace and without a shell, and reads the secret from its output.
+func resolveExec(ctx context.Context, command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", errors.New("exec secret reference has no command")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, execTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("secret command %s failed: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("secret command %s failed: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// describe returns a description of ref that is safe to log. Literal secrets are never included.
+func describe(ref string) string {
+	if strings.HasPrefix(ref, execPrefix) {
+		if args := strings.Fields(strings.TrimPrefix(ref, execPrefix)); len(args) > 0 {
+			return execPrefix + args[0]
+		}
+	}
+	if IsReference(ref) {
+		return ref
+	}
+	return "(literal)"
+}
+
+// Secret is a credential resolved from a reference. Referenced secrets can be re-read periodically,
+// so that rotated credentials take effect without restarting the server.
+type Secret struct {
+	ref   string
+	value atomic.Pointer[string]
+}
+
+// New resolves ref and returns the resulting Secret.
+func New(ctx context.Context, ref string) (*Secret, error) {
+	s := &Secret{ref: ref}
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Literal returns a Secret with a fixed value.
+func Literal(value string) *Secret {
+	s := &Secret{ref: value}
+	s.value.Store(&value)
+	return s
+}
+
+// Value returns the current value of the secret.
+func (s *Secret) Value() string {
+	return *s.value.Load()
+}
+
+// String describes the secret without revealing its value, so that secrets are safe to log by accident.
+func (s *Secret) String() string {
+	return "secret " + describe(s.ref)
+}
+
+// Refresh re-reads the secret. The previous value is kept if the secret cannot be read.
+func (s *Secret) Refresh(ctx context.Context) error {
+	value, err := Resolve(ctx, s.ref)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", s, err)
+	}
+	s.value.Store(&value)
+	return nil
+}
+
+// Watch re-reads the secret every interval until ctx is cancelled. Failures are logged and the previous value
+// stays in effect. Literal secrets never change, so Watch returns immediately for them.
+func (s *Secret) Watch(ctx context.Context, interval time.Duration) {
+	if !IsReference(s.ref) {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				logging.FromContext(ctx).WarnContext(ctx, "Keeping previous value of secret", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SECRETS_TEST_TOKEN", "from-env")
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"plain-token", "plain-token"},
+		{"file://" + path, "from-file"},
+		{"env:SECRETS_TEST_TOKEN", "from-env"},
+		{"exec:echo from-exec", "from-exec"},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(context.Background(), tt.ref)
+		if err != nil {
+			t.Errorf("Resolve(%q) error: %v", tt.ref, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Resolve(%q) = %q; want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	t.Setenv("SECRETS_TEST_EMPTY", "")
+	for _, ref := range []string{
+		"file://" + filepath.Join(t.TempDir(), "missing"),
+		"file://",
+		"env:SECRETS_TEST_UNSET",
+		"env:SECRETS_TEST_EMPTY",
+		"exec:",
+		"exec:false",
+	} {
+		if _, err := Resolve(context.Background(), ref); err == nil {
+			t.Errorf("Resolve(%q) expected error, got nil", ref)
+		}
+	}
+}
+
+func TestSecret_Refresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	write := func(value string) {
+		if err := os.WriteFile(path, []byte(value), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	write("first")
+	s, err := New(ctx, "file://"+path)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if s.Value() != "first" {
+		t.Fatalf("Value() = %q; want first", s.Value())
+	}
+
+	write("rotated")
+	if err := s.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh error: %v", err)
+	}
+	if s.Value() != "rotated" {
+		t.Errorf("Value() = %q; want rotated", s.Value())
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Refresh(ctx); err == nil {
+		t.Error("expected error after secret file removal, got nil")
+	}
+	if s.Value() != "rotated" {
+		t.Errorf("Value() = %q; want previous value to be kept", s.Value())
+	}
+}
+
+func TestSecret_StringRedactsLiterals(t *testing.T) {
+	s := Literal("hunter2")
+	if strings.Contains(s.String(), "hunter2") {
+		t.Errorf("String() = %q; reveals secret value", s.String())
+	}
+	s, err := New(context.Background(), "exec:echo hunter2")
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if strings.Contains(s.String(), "hunter2") {
+		t.Errorf("String() = %q; reveals command arguments", s.String())
+	}
+}
@@ -0,0 +1,216 @@
+package tools
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+// Budget limits the size of tool responses, so that they fit into the context window of the LLM.
+// Zero disables a limit.
+type Budget struct {
+	// MaxTransactions is the maximum number of transactions in a single response.
+	MaxTransactions int
+	// MaxTokens is the approximate maximum number of tokens in a single response.
+	MaxTokens int
+}
+
+// bytesPerToken is the approximate number of bytes of JSON per token, used to estimate response sizes.
+const bytesPerToken = 4
+
+// CategorizedTransactionsPage is a response of get_categorized_transactions that was cut down to fit into
+// the response budget. The category tree and its totals are complete, but only some transactions are included.
+type CategorizedTransactionsPage struct {
+	*types.Categories
+	// TotalTransactions is the number of transactions in the date range.
+	TotalTransactions int `json:"total_transactions"`
+	// NextCursor gets the next page of transactions. It is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// pageCursor identifies a page of the transactions that did not fit into the first response.
+type pageCursor struct {
+	StartDate types.Date `json:"start_date"`
+	EndDate   types.Date `json:"end_date"`
+	// TopN is the number of transactions per category included in the first response, which later pages skip.
+	TopN int `json:"top_n"`
+	// Offset is the index of the first transaction of the page among the remaining transactions.
+	Offset int `json:"offset"`
+}
+
+func (c pageCursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// parseCursor decodes a cursor supplied by the LLM.
+func parseCursor(s string) (*pageCursor, error) {
+	var c pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil || c.TopN < 0 || c.Offset < 0 {
+		return nil, invalidCursorError()
+	}
+	return &c, nil
+}
+
+func invalidCursorError() error {
+	return ds.Errorf(ds.ErrInvalidInput, "invalid cursor").
+		WithHint("pass next_cursor from the previous response unchanged along with the same dates, or omit it to start over")
+}
+
+// paginate cuts cats down to fit into the budget. Without a cursor, it keeps the largest transactions of every
+// category, as many per category as fit. With a cursor, it keeps the page of the remaining transactions that the
+// cursor points to. Transactions are ordered deterministically, so that pages neither overlap nor skip any.
+// It returns a nil page if there is no cursor and cats fits into the budget as is, and otherwise a page along with
+// a note that tells the LLM how to get the rest.
+func (b Budget) paginate(cats *types.Categories, interval ds.DateRange, cursor *pageCursor) (*CategorizedTransactionsPage, string, error) {
+	total := cats.TransactionCount()
+	limit := b.transactionLimit(cats)
+	if cursor == nil && limit >= total {
+		return nil, "", nil
+	}
+
+	var categories []*types.Category
+	walkCategories(cats, func(cat *types.Category) {
+		slices.SortStableFunc(cat.Transactions, compareTransactions)
+		categories = append(categories, cat)
+	})
+	page := &CategorizedTransactionsPage{Categories: cats, TotalTransactions: total}
+
+	if cursor == nil {
+		topN := 0
+		for keptWith(categories, topN+1) <= limit && keptWith(categories, topN+1) > keptWith(categories, topN) {
+			topN++
+		}
+		kept := keptWith(categories, topN)
+		for _, cat := range categories {
+			cat.Transactions = cat.Transactions[:min(len(cat.Transactions), topN)]
+		}
+		page.NextCursor = pageCursor{StartDate: interval.StartDate, EndDate: interval.EndDate, TopN: topN}.String()
+		shown := fmt.Sprintf("the %d largest transactions of each category, %d of %d in total", topN, kept, total)
+		if topN == 0 {
+			shown = "only the category totals"
+		}
+		note := fmt.Sprintf("Response cut down to fit the response budget: showing %s. "+
+			"Call again with the same dates and cursor %s for the rest.", shown, page.NextCursor)
+		return page, note, nil
+	}
+
+	if time.Time(cursor.StartDate) != time.Time(interval.StartDate) || time.Time(cursor.EndDate) != time.Time(interval.EndDate) {
+		return nil, "", ds.Errorf(ds.ErrInvalidInput, "cursor was issued for %s to %s", cursor.StartDate, cursor.EndDate).
+			WithHint("call again with the dates of the call that returned the cursor")
+	}
+	remaining := total - keptWith(categories, cursor.TopN)
+	if cursor.Offset >= remaining {
+		return nil, "", ds.Errorf(ds.ErrInvalidInput, "cursor points past the last transaction").
+			WithHint("transactions may have changed since the cursor was issued, omit the cursor to start over")
+	}
+	size := max(limit, 1)
+	end := min(cursor.Offset+size, remaining)
+	index := 0
+	for _, cat := range categories {
+		rest := cat.Transactions[min(len(cat.Transactions), cursor.TopN):]
+		from, to := max(cursor.Offset-index, 0), min(end-index, len(rest))
+		index += len(rest)
+		if from >= to {
+			cat.Transactions = nil
+			continue
+		}
+		cat.Transactions = rest[from:to]
+	}
+	note := fmt.Sprintf("Showing transactions %d to %d of the %d that were not included in the first response.",
+		cursor.Offset+1, end, remaining)
+	if end < remaining {
+		next := *cursor
+		next.Offset = end
+		page.NextCursor = next.String()
+		note += fmt.Sprintf(" Call again with the same dates and cursor %s for the next page.", page.NextCursor)
+	} else {
+		note += " This is the last page."
+	}
+	return page, note, nil
+}
+
+// transactionLimit returns the number of transactions of cats that fit into the budget.
+func (b Budget) transactionLimit(cats *types.Categories) int {
+	total := cats.TransactionCount()
+	limit := total
+	if b.MaxTransactions > 0 {
+		limit = min(limit, b.MaxTransactions)
+	}
+	if b.MaxTokens > 0 && total > 0 {
+		full := estimateTokens(cats)
+		if full > b.MaxTokens {
+			// The category tree is returned in full, so only the remaining tokens are available for transactions.
+			tree := full
+			if clone, err := cats.Clone(); err == nil {
+				removeTransactions(clone.Income)
+				removeTransactions(clone.Expenses)
+				removeTransactions(clone.Ignored)
+				tree = estimateTokens(clone)
+			}
+			perTransaction := max((full-tree)/total, 1)
+			limit = min(limit, max(b.MaxTokens-tree, 0)/perTransaction)
+		}
+	}
+	return limit
+}
+
+// estimateTokens approximates the number of tokens v takes up when returned as JSON.
+func estimateTokens(v any) int {
+	data, _ := json.Marshal(v)
+	return len(data) / bytesPerToken
+}
+
+// keptWith returns the number of transactions kept when keeping at most n per category.
+func keptWith(categories []*types.Category, n int) int {
+	kept := 0
+	for _, cat := range categories {
+		kept += min(len(cat.Transactions), n)
+	}
+	return kept
+}
+
+// compareTransactions orders transactions by descending absolute amount, and then by date, payee and description.
+func compareTransactions(a, b *types.Transaction) int {
+	return cmp.Or(
+		cmp.Compare(abs(b.Amount), abs(a.Amount)),
+		time.Time(a.Date).Compare(time.Time(b.Date)),
+		strings.Compare(a.Payee, b.Payee),
+		strings.Compare(a.Description, b.Description),
+		cmp.Compare(a.Amount, b.Amount),
+	)
+}
+
+// walkCategories calls fn for every category in depth-first order.
+func walkCategories(cats *types.Categories, fn func(cat *types.Category)) {
+	var walk func(cat *types.Category)
+	walk = func(cat *types.Category) {
+		fn(cat)
+		for _, sub := range cat.Subcategories {
+			walk(sub)
+		}
+	}
+	for _, root := range []*types.Category{cats.Income, cats.Expenses, cats.Ignored} {
+		if root != nil {
+			walk(root)
+		}
+	}
+}
+
+func abs(m types.Money) types.Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
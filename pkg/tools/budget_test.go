@@ -0,0 +1,207 @@
+package tools
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func date(t *testing.T, s string) types.Date {
+	t.Helper()
+	d, err := types.ParseDate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// testInterval is the date range of the transactions of testCategories.
+func testInterval(t *testing.T) ds.DateRange {
+	return ds.DateRange{StartDate: date(t, "2024-03-01"), EndDate: date(t, "2024-03-31")}
+}
+
+// testCategories returns 8 transactions: 5 groceries of 1.00 to 5.00, the rent and 2 salaries. Payees are unique.
+func testCategories(t *testing.T) *types.Categories {
+	cats := types.NewCategories()
+	add := func(cat *types.Category, d, payee string, amount types.Money) {
+		if err := cat.AddTransaction(types.NewTransaction(date(t, d), payee, amount)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	groceries := cats.Expenses.GetOrCreatePath("Food", "Groceries")
+	for i := 1; i <= 5; i++ {
+		add(groceries, fmt.Sprintf("2024-03-%02d", i), fmt.Sprintf("Market %d", i), types.Money(i*10000))
+	}
+	add(cats.Expenses.GetOrCreatePath("Rent"), "2024-03-01", "Landlord", 1500000)
+	salary := cats.Income.GetOrCreatePath("Salary")
+	add(salary, "2024-03-15", "Acme", -3000000)
+	add(salary, "2024-03-31", "Acme Bonus", -500000)
+	return cats
+}
+
+// payees returns the payees of every transaction of cats, sorted.
+func payees(cats *types.Categories) []string {
+	var result []string
+	walkCategories(cats, func(cat *types.Category) {
+		for _, txn := range cat.Transactions {
+			result = append(result, txn.Payee)
+		}
+	})
+	slices.Sort(result)
+	return result
+}
+
+func TestPaginate_FirstPage(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		budget Budget
+		// want are the payees kept, or nil if the response fits as is.
+		want []string
+		topN int
+	}{
+		{"unlimited", Budget{}, nil, 0},
+		{"fits", Budget{MaxTransactions: 8}, nil, 0},
+		{"two per category", Budget{MaxTransactions: 5}, []string{"Acme", "Acme Bonus", "Landlord", "Market 4", "Market 5"}, 2},
+		{"one per category", Budget{MaxTransactions: 4}, []string{"Acme", "Landlord", "Market 5"}, 1},
+		{"totals only", Budget{MaxTransactions: 2}, []string{}, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cats := testCategories(t)
+			page, note, err := tc.budget.paginate(cats, testInterval(t), nil)
+			if err != nil {
+				t.Fatalf("paginate error: %v", err)
+			}
+			if tc.want == nil {
+				if page != nil {
+					t.Errorf("page = %+v; want nil", page)
+				}
+				return
+			}
+			if page == nil {
+				t.Fatal("page = nil; want a page")
+			}
+			if got := payees(page.Categories); !slices.Equal(got, tc.want) {
+				t.Errorf("payees = %v; want %v", got, tc.want)
+			}
+			if page.TotalTransactions != 8 || page.NextCursor == "" || !strings.Contains(note, page.NextCursor) {
+				t.Errorf("page = %d transactions, cursor %q, note %q", page.TotalTransactions, page.NextCursor, note)
+			}
+			if cursor, err := parseCursor(page.NextCursor); err != nil || cursor.TopN != tc.topN || cursor.Offset != 0 {
+				t.Errorf("cursor = %+v, %v; want top %d", cursor, err, tc.topN)
+			}
+			if cats.Expenses.TotalAmount != 1650000 {
+				t.Errorf("Expenses = %v; want totals kept in full", cats.Expenses.TotalAmount)
+			}
+		})
+	}
+}
+
+func TestPaginate_CursorPages(t *testing.T) {
+	for _, budget := range []Budget{{MaxTransactions: 1}, {MaxTransactions: 2}, {MaxTransactions: 3}, {MaxTransactions: 5}} {
+		t.Run(fmt.Sprint(budget.MaxTransactions), func(t *testing.T) {
+			page, _, err := budget.paginate(testCategories(t), testInterval(t), nil)
+			if err != nil {
+				t.Fatalf("paginate error: %v", err)
+			}
+			seen := payees(page.Categories)
+			for pages := 0; page.NextCursor != ""; pages++ {
+				if pages > 8 {
+					t.Fatal("too many pages")
+				}
+				cursor, err := parseCursor(page.NextCursor)
+				if err != nil {
+					t.Fatalf("parseCursor error: %v", err)
+				}
+				if page, _, err = budget.paginate(testCategories(t), testInterval(t), cursor); err != nil {
+					t.Fatalf("paginate error: %v", err)
+				}
+				got := payees(page.Categories)
+				if len(got) == 0 || len(got) > budget.MaxTransactions {
+					t.Errorf("page %d = %v; want 1 to %d transactions", pages, got, budget.MaxTransactions)
+				}
+				seen = append(seen, got...)
+			}
+			slices.Sort(seen)
+			if want := payees(testCategories(t)); !slices.Equal(seen, want) {
+				t.Errorf("payees = %v; want every transaction exactly once: %v", seen, want)
+			}
+		})
+	}
+}
+
+func TestPaginate_InvalidCursor(t *testing.T) {
+	budget := Budget{MaxTransactions: 5}
+	for _, tc := range []struct {
+		name     string
+		interval ds.DateRange
+		cursor   pageCursor
+	}{
+		{"different dates", ds.DateRange{StartDate: date(t, "2024-03-01"), EndDate: date(t, "2024-04-30")},
+			pageCursor{StartDate: date(t, "2024-03-01"), EndDate: date(t, "2024-03-31"), TopN: 2}},
+		{"past the end", testInterval(t),
+			pageCursor{StartDate: date(t, "2024-03-01"), EndDate: date(t, "2024-03-31"), TopN: 2, Offset: 3}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := budget.paginate(testCategories(t), tc.interval, &tc.cursor)
+			if !errors.Is(err, ds.ErrInvalidInput) {
+				t.Errorf("err = %v; want invalid input", err)
+			}
+		})
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	cursor := pageCursor{StartDate: date(t, "2024-03-01"), EndDate: date(t, "2024-03-31"), TopN: 2, Offset: 4}
+	if got, err := parseCursor(cursor.String()); err != nil || *got != cursor {
+		t.Errorf("parseCursor = %+v, %v; want %+v", got, err, cursor)
+	}
+	for _, s := range []string{"", "not a cursor!", base64.RawURLEncoding.EncodeToString([]byte(`{"offset":-1}`))} {
+		if _, err := parseCursor(s); !errors.Is(err, ds.ErrInvalidInput) {
+			t.Errorf("parseCursor(%q) error = %v; want invalid input", s, err)
+		}
+	}
+}
+
+func TestPaginate_MaxTokens(t *testing.T) {
+	full := estimateTokens(testCategories(t))
+	for _, tc := range []struct {
+		name      string
+		maxTokens int
+		truncated bool
+	}{
+		{"fits", full, false},
+		{"three quarters", full * 3 / 4, true},
+		{"tree only", 1, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			budget := Budget{MaxTokens: tc.maxTokens}
+			page, note, err := budget.paginate(testCategories(t), testInterval(t), nil)
+			if err != nil {
+				t.Fatalf("paginate error: %v", err)
+			}
+			if !tc.truncated {
+				if page != nil {
+					t.Errorf("page = %+v; want nil within the budget", page)
+				}
+				return
+			}
+			if page == nil {
+				t.Fatal("page = nil; want a page cut down to the budget")
+			}
+			kept := len(payees(page.Categories))
+			if kept >= 8 || !strings.Contains(note, "Response cut down") {
+				t.Errorf("kept %d transactions, note %q; want fewer than 8", kept, note)
+			}
+			// The category tree is kept in full, so only the transactions are held to the budget.
+			if tokens := estimateTokens(page.Categories); kept > 0 && tokens > tc.maxTokens {
+				t.Errorf("categories = %d tokens; want at most %d", tokens, tc.maxTokens)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+)
+
+// ToolError is the structured payload of a failed tool call. It tells the LLM what went wrong,
+// whether repeating the same call may succeed, and what to do differently.
+type ToolError struct {
+	Kind              string `json:"kind"`
+	Message           string `json:"message"`
+	Retryable         bool   `json:"retryable"`
+	RetryAfterSeconds int64  `json:"retry_after_seconds,omitempty"`
+	Hint              string `json:"hint,omitempty"`
+}
+
+// String renders the error as a single line of text for clients without structured content support.
+func (e ToolError) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Kind)
+	sb.WriteString(": ")
+	sb.WriteString(e.Message)
+	if e.Retryable {
+		sb.WriteString(" (retryable")
+		if e.RetryAfterSeconds > 0 {
+			fmt.Fprintf(&sb, " after %ds", e.RetryAfterSeconds)
+		}
+		sb.WriteString(")")
+	} else {
+		sb.WriteString(" (not retryable)")
+	}
+	if e.Hint != "" {
+		sb.WriteString(". Hint: ")
+		sb.WriteString(e.Hint)
+	}
+	return sb.String()
+}
+
+// newToolError converts err into a ToolError, using the classification attached by the data source if any.
+// Unclassified errors are reported as non-retryable internal errors.
+func newToolError(err error) ToolError {
+	dsErr := ds.AsError(err)
+	if dsErr == nil {
+		return ToolError{Kind: "internal error", Message: err.Error()}
+	}
+	return ToolError{
+		Kind:              string(dsErr.Kind),
+		Message:           err.Error(),
+		Retryable:         dsErr.Retryable(),
+		RetryAfterSeconds: int64(dsErr.RetryAfter / time.Second),
+		Hint:              dsErr.RecoveryHint(),
+	}
+}
+
+// errorResult renders err as a tool error result. Errors are reported to the LLM via the result rather than
+// the Go error, since the latter turns into a protocol-level error the LLM never gets to see.
+func errorResult(err error) *mcp.CallToolResult {
+	toolErr := newToolError(err)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(toolErr.String()),
+		},
+		StructuredContent: map[string]any{"error": toolErr},
+		IsError:           true,
+	}
+}
+
+// validateDateRange ensures that the date range supplied by the LLM is well-formed.
+func validateDateRange(r ds.DateRange) error {
+	switch {
+	case r.StartDate.IsZero() || r.EndDate.IsZero():
+		return ds.Errorf(ds.ErrInvalidInput, "start_date and end_date are required").
+			WithHint("supply both dates formatted like YYYY-MM-DD")
+	case r.EndDate.Before(r.StartDate):
+		return ds.Errorf(ds.ErrInvalidInput, "end_date %s is before start_date %s", r.EndDate, r.StartDate).
+			WithHint("swap start_date and end_date")
+	default:
+		return nil
+	}
+}
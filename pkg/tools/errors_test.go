@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+)
+
+func TestErrorResult(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want ToolError
+		text string
+	}{
+		{
+			"unclassified",
+			errors.New("boom"),
+			ToolError{Kind: "internal error", Message: "boom"},
+			"internal error: boom (not retryable)",
+		},
+		{
+			"not configured",
+			fmt.Errorf("kubera: %w", ds.Errorf(ds.ErrNotConfigured, "no Kubera client in the request context")),
+			ToolError{
+				Kind:    "not configured",
+				Message: "kubera: not configured: no Kubera client in the request context",
+				Hint:    ds.ErrNotConfigured.Hint(),
+			},
+			"not configured: kubera: not configured: no Kubera client in the request context (not retryable). " +
+				"Hint: " + ds.ErrNotConfigured.Hint(),
+		},
+		{
+			"rate limited",
+			ds.Errorf(ds.ErrRateLimited, "slow down").WithRetryAfter(30 * time.Second).WithHint("wait a minute"),
+			ToolError{
+				Kind: "rate limited", Message: "rate limited: slow down", Retryable: true, RetryAfterSeconds: 30,
+				Hint: "wait a minute",
+			},
+			"rate limited: rate limited: slow down (retryable after 30s). Hint: wait a minute",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := errorResult(tc.err)
+			if !result.IsError {
+				t.Error("IsError = false; want true")
+			}
+			if got := result.StructuredContent.(map[string]any)["error"]; got != tc.want {
+				t.Errorf("error = %+v; want %+v", got, tc.want)
+			}
+			if len(result.Content) != 1 || result.Content[0].(mcp.TextContent).Text != tc.text {
+				t.Errorf("content = %+v; want %q", result.Content, tc.text)
+			}
+		})
+	}
+}
+
+func TestValidateDateRange(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		start, end string
+		hint       string
+	}{
+		{"valid", "2024-03-01", "2024-03-31", ""},
+		{"single day", "2024-03-01", "2024-03-01", ""},
+		{"missing start", "", "2024-03-31", "supply both dates formatted like YYYY-MM-DD"},
+		{"missing end", "2024-03-01", "", "supply both dates formatted like YYYY-MM-DD"},
+		{"reversed", "2024-03-31", "2024-03-01", "swap start_date and end_date"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var r ds.DateRange
+			if tc.start != "" {
+				r.StartDate = date(t, tc.start)
+			}
+			if tc.end != "" {
+				r.EndDate = date(t, tc.end)
+			}
+			err := validateDateRange(r)
+			if tc.hint == "" {
+				if err != nil {
+					t.Errorf("validateDateRange error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ds.ErrInvalidInput) || ds.AsError(err).RecoveryHint() != tc.hint {
+				t.Errorf("err = %v; want invalid input with hint %q", err, tc.hint)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/duplicates"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/render"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+type FindDuplicateTransactionsInput struct {
+	ds.DateRange
+	Format string `json:"format"`
+}
+
+// DuplicateClusters is the response of find_duplicate_transactions.
+type DuplicateClusters struct {
+	Clusters []*duplicates.Cluster `json:"clusters"`
+}
+
+// FindDuplicateTransactionsTool lists the clusters of likely duplicate transactions found by detector among the
+// transactions of ds, which must not have had duplicates ignored yet.
+func FindDuplicateTransactionsTool(ds ds.GetCategorizedTransactionsFunc, detector *duplicates.Detector) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("find_duplicate_transactions",
+			mcp.WithDescription("Find transactions in the specified date range that are likely duplicates, such as a "+
+				"purchase reported by two data sources or imported twice, grouped into clusters with equal amounts, close dates and "+
+				"similar payees. The first transaction of each cluster is kept and the others are suspected "+
+				"duplicates, which are ignored in other tools if the server is configured to. Use to review "+
+				"spending that looks too high"),
+			mcp.WithString("start_date",
+				mcp.Description("Inclusive start date of the interval to search, formatted like YYYY-MM-DD"),
+				mcp.Pattern("[0-9]{4}-[0-9]{2}-[0-9]{2}"),
+				mcp.Required(),
+			),
+			mcp.WithString("end_date",
+				mcp.Description("Inclusive end date of the interval to search, formatted like YYYY-MM-DD"),
+				mcp.Pattern("[0-9]{4}-[0-9]{2}-[0-9]{2}"),
+				mcp.Required(),
+			),
+			withFormat(),
+		),
+		Handler: mcp.NewTypedToolHandler(
+			func(ctx context.Context, _ mcp.CallToolRequest, input FindDuplicateTransactionsInput) (*mcp.CallToolResult, error) {
+				if err := validateDateRange(input.DateRange); err != nil {
+					return errorResult(err), nil
+				}
+				format, err := parseFormat(input.Format)
+				if err != nil {
+					return errorResult(err), nil
+				}
+				cats, err := ds(ctx, input.DateRange)
+				if err != nil {
+					return errorResult(err), nil
+				}
+				result := &DuplicateClusters{Clusters: detector.Find(cats)}
+				if result.Clusters == nil {
+					result.Clusters = []*duplicates.Cluster{}
+				}
+				return formattedResult(format, duplicatesText(result), result, func(w io.Writer, f render.Format) error {
+					return DuplicateClustersTable(result).Write(w, f)
+				}), nil
+			},
+		),
+	}
+}
+
+// duplicatesText summarizes the clusters in a single line.
+func duplicatesText(result *DuplicateClusters) string {
+	if len(result.Clusters) == 0 {
+		return "No likely duplicates."
+	}
+	count, total := 0, types.Money(0)
+	for _, cluster := range result.Clusters {
+		for _, m := range cluster.Transactions[1:] {
+			count++
+			total += m.Amount
+		}
+	}
+	return fmt.Sprintf("Found %d clusters of likely duplicates, with %d suspected duplicates totaling %s",
+		len(result.Clusters), count, render.Money(total))
+}
+
+// DuplicateClustersTable returns every transaction of the clusters, numbered by cluster.
+func DuplicateClustersTable(result *DuplicateClusters) *render.Table {
+	t := &render.Table{Header: []string{"Cluster", "Date", "Category", "Payee", "Amount", "Duplicate"}}
+	for i, cluster := range result.Clusters {
+		for _, m := range cluster.Transactions {
+			t.Rows = append(t.Rows, []string{
+				strconv.Itoa(i + 1), m.Date.String(), m.Category, m.Payee, render.Money(m.Amount), strconv.FormatBool(m.Duplicate),
+			})
+		}
+	}
+	return t
+}
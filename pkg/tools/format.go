@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/render"
+)
+
+// withFormat declares the optional argument that selects how a tool renders its result.
+func withFormat() mcp.ToolOption {
+	return mcp.WithString("format",
+		mcp.Description("Format of the result. json (default) returns structured content; markdown and csv return "+
+			"a text rendering instead, which is considerably more compact"),
+		mcp.Enum(render.Formats...),
+	)
+}
+
+// parseFormat parses the format argument supplied by the LLM.
+func parseFormat(s string) (render.Format, error) {
+	format, err := render.ParseFormat(s)
+	if err != nil {
+		return "", ds.Errorf(ds.ErrInvalidInput, "%v", err).
+			WithHint("set format to one of " + strings.Join(render.Formats, ", ") + ", or omit it")
+	}
+	return format, nil
+}
+
+// formattedResult builds a tool result in the requested format. Every result starts with a compact text
+// summary, so that clients without structured content support get something readable. JSON results add the
+// structured content, while Markdown and CSV results add the rendering as a second text block instead.
+func formattedResult(
+	format render.Format, summary string, value any, write func(io.Writer, render.Format) error,
+) *mcp.CallToolResult {
+	if format == render.JSON {
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{mcp.NewTextContent(summary)},
+			StructuredContent: value,
+		}
+	}
+	var buf bytes.Buffer
+	if err := write(&buf, format); err != nil {
+		return errorResult(err)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{mcp.NewTextContent(summary), mcp.NewTextContent(buf.String())},
+	}
+}
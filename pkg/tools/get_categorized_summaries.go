@@ -2,15 +2,18 @@ package tools
 
 import (
 	"context"
+	"io"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/render"
 	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
 )
 
 type GetCategorizedSummariesInput struct {
 	ds.DateRange
+	Format string `json:"format"`
 }
 
 func GetCategorizedSummariesTool(ds ds.GetCategorizedTransactionsFunc) server.ServerTool {
@@ -31,23 +34,38 @@ func GetCategorizedSummariesTool(ds ds.GetCategorizedTransactionsFunc) server.Se
 				mcp.Pattern("[0-9]{4}-[0-9]{2}-[0-9]{2}"),
 				mcp.Required(),
 			),
+			withFormat(),
 		),
 		Handler: mcp.NewTypedToolHandler(
 			func(ctx context.Context, _ mcp.CallToolRequest, input GetCategorizedSummariesInput) (*mcp.CallToolResult, error) {
+				if err := validateDateRange(input.DateRange); err != nil {
+					return errorResult(err), nil
+				}
+				format, err := parseFormat(input.Format)
+				if err != nil {
+					return errorResult(err), nil
+				}
 				result, err := ds(ctx, input.DateRange)
 				if err != nil {
-					return mcp.NewToolResultErrorFromErr("datasource error", err), err
+					return errorResult(err), nil
 				}
+				// Summarize before removing the transactions, so that the summary still tells how many there were.
+				summary := render.CategoriesText(result)
 				removeTransactions(result.Income)
 				removeTransactions(result.Expenses)
 				removeTransactions(result.Ignored)
-				return mcp.NewToolResultStructuredOnly(result), nil
+				return formattedResult(format, summary, result, func(w io.Writer, f render.Format) error {
+					return render.Categories(w, result, f)
+				}), nil
 			},
 		),
 	}
 }
 
 func removeTransactions(cat *types.Category) {
+	if cat == nil {
+		return
+	}
 	cat.Transactions = nil
 	for _, sub := range cat.Subcategories {
 		removeTransactions(sub)
@@ -2,20 +2,26 @@ package tools
 
 import (
 	"context"
+	"io"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/render"
 )
 
 type GetCategorizedTransactionsInput struct {
 	ds.DateRange
+	Format string `json:"format"`
+	Cursor string `json:"cursor"`
 }
 
-func GetCategorizedTransactionsTool(ds ds.GetCategorizedTransactionsFunc) server.ServerTool {
+func GetCategorizedTransactionsTool(ds ds.GetCategorizedTransactionsFunc, budget Budget) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_categorized_transactions",
-			mcp.WithDescription("Get full transaction list for the specified date range, organized by categories. "),
+			mcp.WithDescription("Get full transaction list for the specified date range, organized by categories. "+
+				"Large responses are cut down to the largest transactions of each category, along with a cursor "+
+				"that pages through the rest"),
 			mcp.WithString("start_date",
 				mcp.Description("Inclusive start date of the interval to list transactions for, formatted like YYYY-MM-DD"),
 				mcp.Pattern("[0-9]{4}-[0-9]{2}-[0-9]{2}"),
@@ -26,14 +32,43 @@ func GetCategorizedTransactionsTool(ds ds.GetCategorizedTransactionsFunc) server
 				mcp.Pattern("[0-9]{4}-[0-9]{2}-[0-9]{2}"),
 				mcp.Required(),
 			),
+			withFormat(),
+			mcp.WithString("cursor",
+				mcp.Description("Cursor returned by a previous call with the same dates, to get the next page of transactions"),
+			),
 		),
 		Handler: mcp.NewTypedToolHandler(
 			func(ctx context.Context, _ mcp.CallToolRequest, input GetCategorizedTransactionsInput) (*mcp.CallToolResult, error) {
+				if err := validateDateRange(input.DateRange); err != nil {
+					return errorResult(err), nil
+				}
+				format, err := parseFormat(input.Format)
+				if err != nil {
+					return errorResult(err), nil
+				}
+				var cursor *pageCursor
+				if input.Cursor != "" {
+					if cursor, err = parseCursor(input.Cursor); err != nil {
+						return errorResult(err), nil
+					}
+				}
 				result, err := ds(ctx, input.DateRange)
 				if err != nil {
-					return mcp.NewToolResultErrorFromErr("datasource error", err), err
+					return errorResult(err), nil
+				}
+				// Summarize before paginating, so that the summary covers all transactions.
+				summary := render.CategoriesText(result)
+				page, note, err := budget.paginate(result, input.DateRange, cursor)
+				if err != nil {
+					return errorResult(err), nil
+				}
+				write := func(w io.Writer, f render.Format) error {
+					return render.Categories(w, result, f)
+				}
+				if page == nil {
+					return formattedResult(format, summary, result, write), nil
 				}
-				return mcp.NewToolResultStructuredOnly(result), nil
+				return formattedResult(format, summary+"\n"+note, page, write), nil
 			},
 		),
 	}
@@ -2,23 +2,55 @@ package tools
 
 import (
 	"context"
+	"io"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/render"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
 )
 
-func GetNetWorthSummary(ds ds.GetPortfolioFunc) server.ServerTool {
+type GetNetWorthSummaryInput struct {
+	Portfolio string `json:"portfolio"`
+	Format    string `json:"format"`
+}
+
+// GetNetWorthSummary reports the portfolio of ds. If selected is not nil, which it is for data sources that keep
+// several portfolios, the tool takes a portfolio argument that selects and combines some of those portfolios in place
+// of the default one, along with the portfolios of the other data sources.
+func GetNetWorthSummary(ds ds.GetPortfolioFunc, selected ds.SelectPortfolioFunc) server.ServerTool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Get a summary of user's net worth, including all asset holdings, debts and their respective values."),
+	}
+	if selected != nil {
+		opts = append(opts, mcp.WithString("portfolio",
+			mcp.Description("Kubera portfolios to summarize instead of the default, as a comma-separated list of "+
+				"portfolio names, which are summed into a combined view, or all for every portfolio. The assets and "+
+				"debts of other data sources are included either way"),
+		))
+	}
+	opts = append(opts, withFormat())
 	return server.ServerTool{
-		Tool: mcp.NewTool("get_net_worth_summary",
-			mcp.WithDescription("Get a summary of user's net worth, including all asset holdings, debts and their respective values."),
+		Tool: mcp.NewTool("get_net_worth_summary", opts...),
+		Handler: mcp.NewTypedToolHandler(
+			func(ctx context.Context, _ mcp.CallToolRequest, input GetNetWorthSummaryInput) (*mcp.CallToolResult, error) {
+				format, err := parseFormat(input.Format)
+				if err != nil {
+					return errorResult(err), nil
+				}
+				get := ds
+				if input.Portfolio != "" && selected != nil {
+					get = func(ctx context.Context) (*types.Portfolio, error) { return selected(ctx, input.Portfolio) }
+				}
+				result, err := get(ctx)
+				if err != nil {
+					return errorResult(err), nil
+				}
+				return formattedResult(format, render.PortfolioText(result), result, func(w io.Writer, f render.Format) error {
+					return render.Portfolio(w, result, f)
+				}), nil
+			},
 		),
-		Handler: func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			result, err := ds(ctx)
-			if err != nil {
-				return mcp.NewToolResultErrorFromErr("datasource error", err), err
-			}
-			return mcp.NewToolResultStructuredOnly(result), nil
-		},
 	}
 }
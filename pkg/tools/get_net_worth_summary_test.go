@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/types"
+)
+
+func TestGetNetWorthSummary_Portfolio(t *testing.T) {
+	kubera := ds.Named[ds.SelectPortfolioFunc]{Name: "kubera", Fn: func(ctx context.Context, selector string) (*types.Portfolio, error) {
+		p := types.NewPortfolio()
+		if selector == "" {
+			selector = "Personal"
+		}
+		p.AddAsset(types.NewAssetPosition(selector+" Brokerage", "", "stock", "", 1000000))
+		return p, nil
+	}}
+	sources := []ds.Named[ds.GetPortfolioFunc]{
+		{Name: "kubera", Fn: func(ctx context.Context) (*types.Portfolio, error) { return kubera.Fn(ctx, "") }},
+		{Name: "manual", Fn: func(ctx context.Context) (*types.Portfolio, error) {
+			p := types.NewPortfolio()
+			p.AddAsset(types.NewAssetPosition("Gold Coins", "", "other", "", 200000))
+			p.AddDebt(types.NewDebtPosition("Car Loan", "loan", 300000))
+			return p, nil
+		}},
+	}
+	tool := GetNetWorthSummary(ds.MergePortfolios(ds.MergeFail, sources...),
+		ds.MergeSelectedPortfolios(ds.MergeFail, kubera, sources...))
+
+	for _, tc := range []struct {
+		name      string
+		portfolio string
+		want      string
+	}{
+		{"default", "", "Personal Brokerage"},
+		{"selected", "Estate", "Estate Brokerage"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Name = "get_net_worth_summary"
+			req.Params.Arguments = map[string]any{"portfolio": tc.portfolio}
+			result, err := tool.Handler(context.Background(), req)
+			if err != nil || result.IsError {
+				t.Fatalf("result = %+v, %v", result, err)
+			}
+			p := result.StructuredContent.(*types.Portfolio)
+			// The selected Kubera portfolio takes the place of the default one, and manual holdings are kept.
+			if len(p.Assets) != 2 || len(p.Debts) != 1 || p.NetWorth != 1000000+200000-300000 {
+				t.Fatalf("portfolio = %d assets, %d debts, net worth %v; want both data sources",
+					len(p.Assets), len(p.Debts), p.NetWorth)
+			}
+			sources := make(map[string]string)
+			for _, asset := range p.Assets {
+				sources[asset.Name] = asset.Annotations["source"]
+			}
+			if sources[tc.want] != "kubera" || sources["Gold Coins"] != "manual" {
+				t.Errorf("sources = %v; want %s from kubera and Gold Coins from manual", sources, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	ds "github.com/wyvernzora/personal-finance-mcp/pkg/datasource"
+	"github.com/wyvernzora/personal-finance-mcp/pkg/render"
+)
+
+// checkTimeout bounds how long list_data_sources waits for the data sources to respond.
+const checkTimeout = 10 * time.Second
+
+type ListDataSourcesInput struct {
+	Format string `json:"format"`
+}
+
+// DataSources is the response of list_data_sources.
+type DataSources struct {
+	Sources []DataSourceStatus `json:"sources"`
+}
+
+// DataSourceStatus is a configured data source and whether it is reachable.
+type DataSourceStatus struct {
+	Name         string          `json:"name"`
+	Capabilities []ds.Capability `json:"capabilities"`
+	Healthy      bool            `json:"healthy"`
+	Error        string          `json:"error,omitempty"`
+}
+
+// ListDataSourcesTool reports the data sources in registry, the kinds of data they provide and whether they are
+// reachable right now.
+func ListDataSourcesTool(registry *ds.Registry) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_data_sources",
+			mcp.WithDescription("List the data sources the server is configured with, the kinds of data each provides, "+
+				"such as transactions or portfolio, and whether each is reachable right now. Use to explain missing "+
+				"or incomplete data"),
+			withFormat(),
+		),
+		Handler: mcp.NewTypedToolHandler(
+			func(ctx context.Context, _ mcp.CallToolRequest, input ListDataSourcesInput) (*mcp.CallToolResult, error) {
+				format, err := parseFormat(input.Format)
+				if err != nil {
+					return errorResult(err), nil
+				}
+				errs := registry.Check(ctx, checkTimeout)
+				result := &DataSources{Sources: []DataSourceStatus{}}
+				for _, src := range registry.Sources() {
+					status := DataSourceStatus{Name: src.Name(), Capabilities: src.Capabilities(), Healthy: true}
+					if err := errs[src.Name()]; err != nil {
+						status.Healthy, status.Error = false, err.Error()
+					}
+					result.Sources = append(result.Sources, status)
+				}
+				return formattedResult(format, dataSourcesText(result), result, func(w io.Writer, f render.Format) error {
+					return DataSourcesTable(result).Write(w, f)
+				}), nil
+			},
+		),
+	}
+}
+
+// dataSourcesText summarizes the data sources in a single line.
+func dataSourcesText(result *DataSources) string {
+	var unhealthy []string
+	for _, src := range result.Sources {
+		if !src.Healthy {
+			unhealthy = append(unhealthy, src.Name)
+		}
+	}
+	if len(unhealthy) == 0 {
+		return fmt.Sprintf("%d data sources configured, all healthy", len(result.Sources))
+	}
+	return fmt.Sprintf("%d data sources configured, unhealthy: %s", len(result.Sources), strings.Join(unhealthy, ", "))
+}
+
+// DataSourcesTable returns a row for every data source.
+func DataSourcesTable(result *DataSources) *render.Table {
+	t := &render.Table{Header: []string{"Name", "Capabilities", "Healthy", "Error"}}
+	for _, src := range result.Sources {
+		capabilities := make([]string, len(src.Capabilities))
+		for i, c := range src.Capabilities {
+			capabilities[i] = string(c)
+		}
+		t.Rows = append(t.Rows, []string{src.Name, strings.Join(capabilities, " "), strconv.FormatBool(src.Healthy), src.Error})
+	}
+	return t
+}
@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTPContextFunc returns an HTTP context function that continues the trace propagated by the headers
+// of incoming MCP requests, such as traceparent.
+func HTTPContextFunc() server.HTTPContextFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		if r == nil {
+			return ctx
+		}
+		return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+	}
+}
+
+// ToolMiddleware returns tool handler middleware that records a span for every tool call.
+func ToolMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := otel.Tracer(instrumentationName).Start(ctx, "tools/call "+req.Params.Name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attribute.String("mcp.tool.name", req.Params.Name)),
+			)
+			defer span.End()
+
+			result, err := next(ctx, req)
+			switch {
+			case err != nil:
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			case result != nil && result.IsError:
+				span.SetStatus(codes.Error, "tool returned an error result")
+			}
+			return result, err
+		}
+	}
+}
+
+// Transport wraps next, or http.DefaultTransport if nil, so that a client span is recorded for every request
+// to the named upstream API. Trace context is deliberately not propagated to third-party APIs.
+func Transport(client string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		ctx, span := otel.Tracer(instrumentationName).Start(req.Context(), req.Method+" "+client,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("http.request.method", req.Method),
+				attribute.String("server.address", req.URL.Hostname()),
+				attribute.String("url.path", req.URL.Path),
+				attribute.String("upstream.client", client),
+			),
+		)
+		defer span.End()
+
+		resp, err := next.RoundTrip(req.WithContext(ctx))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return resp, err
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, "HTTP "+strconv.Itoa(resp.StatusCode))
+		}
+		return resp, nil
+	})
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,116 @@
+// Package tracing provides OpenTelemetry tracing of tool calls, upstream API requests and data source
+// processing stages. Spans are recorded through the global tracer provider, which is a no-op until Setup
+// installs an exporter.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans created by this module.
+const instrumentationName = "github.com/wyvernzora/personal-finance-mcp"
+
+// Exporter is the destination of recorded spans.
+type Exporter string
+
+const (
+	// ExporterNone disables tracing.
+	ExporterNone Exporter = "none"
+	// ExporterStdout writes spans to standard output, for local debugging.
+	ExporterStdout Exporter = "stdout"
+	// ExporterOTLP sends spans to an OpenTelemetry collector over OTLP/HTTP.
+	ExporterOTLP Exporter = "otlp"
+)
+
+// ParseExporter parses an exporter name: none, stdout or otlp.
+func ParseExporter(name string) (Exporter, error) {
+	switch exporter := Exporter(strings.ToLower(name)); exporter {
+	case ExporterNone, ExporterStdout, ExporterOTLP:
+		return exporter, nil
+	default:
+		return "", fmt.Errorf("unknown trace exporter %q, expected none, stdout or otlp", name)
+	}
+}
+
+// Options configures Setup.
+type Options struct {
+	// Exporter is the destination of recorded spans.
+	Exporter Exporter
+	// Endpoint is the OTLP/HTTP endpoint URL, e.g. http://localhost:4318. If empty, the standard
+	// OTEL_EXPORTER_OTLP_ENDPOINT environment variable or the exporter default is used.
+	Endpoint string
+	// SampleRatio is the fraction of traces without a sampled parent that are recorded.
+	SampleRatio float64
+	// ServiceName and ServiceVersion identify the server in recorded spans.
+	ServiceName    string
+	ServiceVersion string
+	// Stdout is where the stdout exporter writes spans.
+	Stdout io.Writer
+}
+
+// Setup installs the global tracer provider and the W3C trace context and baggage propagators. It returns a
+// function that flushes and stops the exporter, which must be called before the process exits.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if opts.Exporter == ExporterNone || opts.Exporter == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch opts.Exporter {
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(opts.Stdout), stdouttrace.WithPrettyPrint())
+	case ExporterOTLP:
+		var otlpOpts []otlptracehttp.Option
+		if opts.Endpoint != "" {
+			otlpOpts = append(otlpOpts, otlptracehttp.WithEndpointURL(opts.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, otlpOpts...)
+	default:
+		err = fmt.Errorf("unknown trace exporter %q", opts.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", opts.ServiceName),
+			attribute.String("service.version", opts.ServiceVersion),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span with the given name as a child of the span in ctx, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err, if any, on span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
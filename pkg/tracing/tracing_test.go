@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider that records spans in memory for the duration of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return recorder
+}
+
+func TestToolMiddleware_ContinuesPropagatedTrace(t *testing.T) {
+	recorder := recordSpans(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := HTTPContextFunc()(context.Background(), req)
+
+	handler := ToolMiddleware()(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, span := Start(ctx, "stage")
+		End(span, nil)
+		return mcp.NewToolResultError("bad input"), nil
+	})
+	call := mcp.CallToolRequest{}
+	call.Params.Name = "get_categorized_summaries"
+	_, _ = handler(ctx, call)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans; want 2", len(spans))
+	}
+	stage, tool := spans[0], spans[1]
+	if tool.Name() != "tools/call get_categorized_summaries" {
+		t.Errorf("tool span name = %q", tool.Name())
+	}
+	if got := tool.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s; want propagated trace ID", got)
+	}
+	if tool.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("tool span parent = %s; want propagated span", tool.Parent().SpanID())
+	}
+	if stage.Parent().SpanID() != tool.SpanContext().SpanID() {
+		t.Error("stage span is not a child of the tool span")
+	}
+	if tool.Status().Code != codes.Error {
+		t.Errorf("tool span status = %v; want error for tool error result", tool.Status())
+	}
+}
+
+func TestTransport(t *testing.T) {
+	recorder := recordSpans(t)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("traceparent") != "" {
+			t.Error("trace context leaked to upstream API")
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer upstream.Close()
+
+	ctx, parent := Start(context.Background(), "parent")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL+"/v1/transactions", nil)
+	resp, err := (&http.Client{Transport: Transport("lunch_money", nil)}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans; want 2", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET lunch_money" || span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("span = %q with parent %s; want GET lunch_money under parent", span.Name(), span.Parent().SpanID())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("status = %v; want error for HTTP 401", span.Status())
+	}
+}
+
+func TestParseExporter(t *testing.T) {
+	if exporter, err := ParseExporter("OTLP"); err != nil || exporter != ExporterOTLP {
+		t.Errorf("ParseExporter(OTLP) = %v, %v", exporter, err)
+	}
+	if _, err := ParseExporter("jaeger"); err == nil {
+		t.Error("ParseExporter(jaeger) expected error")
+	}
+}
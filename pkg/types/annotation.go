@@ -17,6 +17,11 @@ func NewAnnotatedObject() AnnotatedObject {
 	return AnnotatedObject{make(Annotations)}
 }
 
+// Annotate sets the annotation with the given key, creating the annotations map if needed, such as for objects
+// decoded from JSON without annotations.
 func (o *AnnotatedObject) Annotate(key, value string) {
+	if o.Annotations == nil {
+		o.Annotations = make(Annotations)
+	}
 	o.Annotations[key] = value
 }
@@ -3,6 +3,7 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 )
 
 // Category represents a financial category which may contain nested subcategories
@@ -27,6 +28,9 @@ type Category struct {
 	Transactions []*Transaction `json:"transactions,omitempty"`
 }
 
+// UncategorizedName is the name of the category that holds transactions which could not be categorized.
+const UncategorizedName = "Uncategorized"
+
 // NewCategory constructs and returns a pointer to a Category with the provided name.
 // The resulting Category has no parent, zero total amount, and empty slices for
 // subcategories and transactions.
@@ -99,6 +103,69 @@ func (c *Category) AddTransaction(txn *Transaction) error {
 	return nil
 }
 
+// RemoveTransaction detaches the given transaction from this Category, clears its
+// Category pointer, and decrements the TotalAmount of this Category and its ancestors.
+// It returns an error if the transaction is not assigned to this Category.
+func (c *Category) RemoveTransaction(txn *Transaction) error {
+	idx := slices.Index(c.Transactions, txn)
+	if txn.Category != c || idx < 0 {
+		return fmt.Errorf("transaction does not belong to category %s", c.Name)
+	}
+	c.Transactions = slices.Delete(c.Transactions, idx, idx+1)
+	c.addToTotalAmount(-txn.Amount)
+	txn.Category = nil
+	return nil
+}
+
+// RemoveSubcategory detaches the given subcategory from this Category, clears its Parent
+// pointer, and recomputes the total amounts up the tree.
+// It returns an error if the subcategory does not belong to this Category.
+func (c *Category) RemoveSubcategory(sub *Category) error {
+	idx := slices.Index(c.Subcategories, sub)
+	if sub.Parent != c || idx < 0 {
+		return fmt.Errorf("category %s is not a subcategory of %s", sub.Name, c.Name)
+	}
+	c.Subcategories = slices.Delete(c.Subcategories, idx, idx+1)
+	sub.Parent = nil
+	c.addToTotalAmount(-sub.TotalAmount)
+	return nil
+}
+
+// FindSubcategory returns the direct subcategory with the given name, or nil if there is none.
+func (c *Category) FindSubcategory(name string) *Category {
+	for _, sub := range c.Subcategories {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// GetOrCreatePath walks down the subcategory hierarchy along the given path of names,
+// creating any subcategories that do not exist yet, and returns the last one.
+// An empty path returns this Category.
+func (c *Category) GetOrCreatePath(path ...string) *Category {
+	cat := c
+	for _, name := range path {
+		sub := cat.FindSubcategory(name)
+		if sub == nil {
+			sub = NewCategory(name)
+			_ = cat.AddSubcategory(sub)
+		}
+		cat = sub
+	}
+	return cat
+}
+
+// TransactionCount returns the number of transactions in the category and all of its subcategories.
+func (c *Category) TransactionCount() int {
+	count := len(c.Transactions)
+	for _, sub := range c.Subcategories {
+		count += sub.TransactionCount()
+	}
+	return count
+}
+
 // Categories groups the root‐level income, expense, and ignored categories
 // for the financial application.
 type Categories struct {
@@ -145,3 +212,27 @@ func (c *Categories) UnmarshalJSON(data []byte) error {
 	*c = Categories(aux)
 	return nil
 }
+
+// TransactionCount returns the total number of transactions across income, expense and ignored categories.
+func (c *Categories) TransactionCount() int {
+	count := 0
+	for _, cat := range []*Category{c.Income, c.Expenses, c.Ignored} {
+		if cat != nil {
+			count += cat.TransactionCount()
+		}
+	}
+	return count
+}
+
+// Clone returns a deep copy of the Categories, including all subcategories, transactions and annotations.
+func (c *Categories) Clone() (*Categories, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var clone Categories
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
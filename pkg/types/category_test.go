@@ -198,3 +198,109 @@ func TestCategories_UnmarshalJSON(t *testing.T) {
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+func TestRemoveTransaction_UpdatesTotals(t *testing.T) {
+	root := NewCategory("Caldari State")
+	sub := NewCategory("Kaalakiota")
+	_ = root.AddSubcategory(sub)
+	keep := makeTestTransaction("Wiyrkomi", Money(10000))
+	drop := makeTestTransaction("Sukuuvestaa", Money(30000))
+	_ = sub.AddTransaction(keep)
+	_ = sub.AddTransaction(drop)
+
+	if err := sub.RemoveTransaction(drop); err != nil {
+		t.Fatalf("unexpected error removing transaction: %v", err)
+	}
+	if len(sub.Transactions) != 1 || sub.Transactions[0] != keep {
+		t.Errorf("Transactions = %v; want only the kept transaction", sub.Transactions)
+	}
+	if drop.Category != nil {
+		t.Errorf("drop.Category = %v; want nil", drop.Category)
+	}
+	if sub.TotalAmount != 10000 || root.TotalAmount != 10000 {
+		t.Errorf("totals = %v / %v; want 10000 / 10000", sub.TotalAmount, root.TotalAmount)
+	}
+	if err := sub.RemoveTransaction(drop); err == nil {
+		t.Error("expected error removing transaction twice, got nil")
+	}
+}
+
+func TestGetOrCreatePath(t *testing.T) {
+	root := NewCategory("Expenses")
+	leaf := root.GetOrCreatePath("Ships", "Frigates")
+	if leaf.Name != "Frigates" || leaf.Parent == nil || leaf.Parent.Name != "Ships" || leaf.Parent.Parent != root {
+		t.Fatalf("unexpected path: %+v", leaf)
+	}
+	if again := root.GetOrCreatePath("Ships", "Frigates"); again != leaf {
+		t.Errorf("GetOrCreatePath returned a different category on second call")
+	}
+	if len(root.Subcategories) != 1 {
+		t.Errorf("len(Subcategories) = %d; want 1", len(root.Subcategories))
+	}
+	if got := root.GetOrCreatePath(); got != root {
+		t.Errorf("GetOrCreatePath() = %v; want root", got)
+	}
+}
+
+func TestRemoveSubcategory(t *testing.T) {
+	root := NewCategory("Amarr Empire")
+	sub := NewCategory("Ardishapur")
+	_ = root.AddSubcategory(sub)
+	_ = sub.AddTransaction(makeTestTransaction("Tithe", Money(5000)))
+
+	if err := root.RemoveSubcategory(sub); err != nil {
+		t.Fatalf("unexpected error removing subcategory: %v", err)
+	}
+	if len(root.Subcategories) != 0 || sub.Parent != nil {
+		t.Errorf("subcategory not detached: %v, parent %v", root.Subcategories, sub.Parent)
+	}
+	if root.TotalAmount != 0 {
+		t.Errorf("root.TotalAmount = %v; want 0", root.TotalAmount)
+	}
+	if err := root.RemoveSubcategory(sub); err == nil {
+		t.Error("expected error removing subcategory twice, got nil")
+	}
+}
+
+func TestCategories_Clone(t *testing.T) {
+	cats := NewCategories()
+	sub := cats.Expenses.GetOrCreatePath("Ammunition")
+	txn := makeTestTransaction("Republic Fleet", Money(70000))
+	txn.Annotate("tag:1", "war: yes")
+	_ = sub.AddTransaction(txn)
+
+	clone, err := cats.Clone()
+	if err != nil {
+		t.Fatalf("Clone error: %v", err)
+	}
+	cloneSub := clone.Expenses.FindSubcategory("Ammunition")
+	if cloneSub == nil || cloneSub == sub {
+		t.Fatalf("clone shares or lacks subcategory: %v", cloneSub)
+	}
+	if cloneSub.Parent != clone.Expenses || cloneSub.Transactions[0].Category != cloneSub {
+		t.Error("clone tree links were not restored")
+	}
+	if cloneSub.Transactions[0].Annotations["tag:1"] != "war: yes" {
+		t.Errorf("clone annotations = %v; want tag:1", cloneSub.Transactions[0].Annotations)
+	}
+
+	cloneSub.Transactions = nil
+	if len(sub.Transactions) != 1 {
+		t.Error("modifying clone affected the original")
+	}
+}
+
+func TestCategories_TransactionCount(t *testing.T) {
+	cats := NewCategories()
+	_ = cats.Income.AddTransaction(makeTestTransaction("Mission Reward", Money(100000)))
+	_ = cats.Expenses.GetOrCreatePath("Ships", "Frigates").AddTransaction(makeTestTransaction("Rifter", Money(-50000)))
+	_ = cats.Expenses.GetOrCreatePath("Ships").AddTransaction(makeTestTransaction("Thorax", Money(-90000)))
+	_ = cats.Ignored.AddTransaction(makeTestTransaction("Wallet Transfer", Money(10000)))
+
+	if got := cats.TransactionCount(); got != 4 {
+		t.Errorf("TransactionCount() = %d; want 4", got)
+	}
+	if got := cats.Expenses.TransactionCount(); got != 2 {
+		t.Errorf("Expenses.TransactionCount() = %d; want 2", got)
+	}
+}
@@ -59,3 +59,13 @@ func (d Date) String() string {
 	}
 	return t.Format("2006-01-02")
 }
+
+// IsZero reports whether the Date is the zero value.
+func (d Date) IsZero() bool {
+	return time.Time(d).IsZero()
+}
+
+// Before reports whether the Date is strictly before the other Date.
+func (d Date) Before(other Date) bool {
+	return time.Time(d).Before(time.Time(other))
+}
@@ -1,8 +1,16 @@
 package types
 
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+)
+
 // Portfolio holds a snapshot of financial positions for a user.
 // It tracks net worth, aggregated asset and debt totals, and individual asset/debt entries.
 type Portfolio struct {
+	// AnnotatedObject holds system descriptions of the portfolio as a whole, such as warnings.
+	AnnotatedObject
 	NetWorth    Money            `json:"net_worth"`
 	TotalAssets Money            `json:"total_assets"`
 	TotalDebts  Money            `json:"total_debts"`
@@ -13,11 +21,12 @@ type Portfolio struct {
 // NewPortfolio initializes and returns an empty Portfolio with zero balances and no positions.
 func NewPortfolio() *Portfolio {
 	return &Portfolio{
-		NetWorth:    0,
-		TotalAssets: 0,
-		TotalDebts:  0,
-		Assets:      make([]*AssetPosition, 0),
-		Debts:       make([]*DebtPosition, 0),
+		AnnotatedObject: NewAnnotatedObject(),
+		NetWorth:        0,
+		TotalAssets:     0,
+		TotalDebts:      0,
+		Assets:          make([]*AssetPosition, 0),
+		Debts:           make([]*DebtPosition, 0),
 	}
 }
 
@@ -34,3 +43,32 @@ func (p *Portfolio) AddDebt(debt *DebtPosition) {
 	p.TotalDebts += debt.Value
 	p.NetWorth -= debt.Value
 }
+
+// Sort orders assets and debts by descending value and then by name, so that the portfolio is stable
+// regardless of the order in which positions were added.
+func (p *Portfolio) Sort() {
+	slices.SortStableFunc(p.Assets, func(a, b *AssetPosition) int {
+		return comparePositions(&a.Position, &b.Position)
+	})
+	slices.SortStableFunc(p.Debts, func(a, b *DebtPosition) int {
+		return comparePositions(&a.Position, &b.Position)
+	})
+}
+
+// comparePositions orders positions by descending value, then by name.
+func comparePositions(a, b *Position) int {
+	return cmp.Or(cmp.Compare(b.Value, a.Value), cmp.Compare(a.Name, b.Name))
+}
+
+// Clone returns a deep copy of the Portfolio, including all positions and their annotations.
+func (p *Portfolio) Clone() (*Portfolio, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var clone Portfolio
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
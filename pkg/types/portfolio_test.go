@@ -0,0 +1,45 @@
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPortfolio_AddPositions(t *testing.T) {
+	p := NewPortfolio()
+	p.AddAsset(NewAssetPosition("Brokerage", "VTI", "stock", "equity", Money(1000000)))
+	p.AddAsset(NewAssetPosition("Checking", "", "cash", "cash", Money(250000)))
+	p.AddDebt(NewDebtPosition("Mortgage", "loan", Money(400000)))
+
+	if p.TotalAssets != Money(1250000) {
+		t.Errorf("TotalAssets = %v; want %v", p.TotalAssets, Money(1250000))
+	}
+	if p.TotalDebts != Money(400000) {
+		t.Errorf("TotalDebts = %v; want %v", p.TotalDebts, Money(400000))
+	}
+	if p.NetWorth != Money(850000) {
+		t.Errorf("NetWorth = %v; want %v", p.NetWorth, Money(850000))
+	}
+}
+
+func TestPortfolio_Sort(t *testing.T) {
+	p := NewPortfolio()
+	p.AddAsset(NewAssetPosition("Shuttle", "", "other", "", Money(100)))
+	p.AddAsset(NewAssetPosition("Frigate", "", "other", "", Money(500)))
+	p.AddAsset(NewAssetPosition("Corvette", "", "other", "", Money(100)))
+	p.AddDebt(NewDebtPosition("Loan B", "loan", Money(10)))
+	p.AddDebt(NewDebtPosition("Loan A", "loan", Money(20)))
+
+	p.Sort()
+
+	var names []string
+	for _, a := range p.Assets {
+		names = append(names, a.Name)
+	}
+	if got, want := names, []string{"Frigate", "Corvette", "Shuttle"}; !slices.Equal(got, want) {
+		t.Errorf("asset order = %v; want %v", got, want)
+	}
+	if p.Debts[0].Name != "Loan A" || p.Debts[1].Name != "Loan B" {
+		t.Errorf("debt order = [%s %s]; want [Loan A Loan B]", p.Debts[0].Name, p.Debts[1].Name)
+	}
+}
@@ -0,0 +1,17 @@
+package types
+
+// Tag is a user-defined label that can be attached to transactions.
+type Tag struct {
+	// Name is the short name of the tag.
+	Name string `json:"name"`
+	// Description is an optional text provided by the end user for context.
+	Description string `json:"description,omitempty"`
+}
+
+// NewTag constructs and returns a pointer to a Tag with the provided name and description.
+func NewTag(name, description string) *Tag {
+	return &Tag{
+		Name:        name,
+		Description: description,
+	}
+}